@@ -39,6 +39,10 @@ var (
 	// This error is returned by WaitDeployed if contract creation leaves an
 	// empty contract behind.
 	ErrNoCodeAfterDeploy = errors.New("no contract code after deployment")
+
+	// This error is raised when attempting to create an access list on a
+	// backend that doesn't implement AccessListTransactor.
+	ErrNoAccessList = errors.New("backend does not support access lists")
 )
 
 // ContractCaller defines the methods needed to allow operating with a contract on a read
@@ -84,6 +88,16 @@ type ContractTransactor interface {
 	SendTransaction(ctx context.Context, tx *types.Transaction) error
 }
 
+// AccessListTransactor defines methods to create EIP-2930 access lists for transactions.
+// Transact will try to discover this interface when an access list is requested. If the
+// backend does not support access lists, Transact returns ErrNoAccessList.
+type AccessListTransactor interface {
+	// CreateAccessList creates an access list for the transaction based on the current
+	// pending state, returning the gas used with the list and the error message of the
+	// execution, if it failed.
+	CreateAccessList(ctx context.Context, call ethereum.CallMsg) (*types.AccessList, uint64, string, error)
+}
+
 // ContractFilterer defines the methods needed to access log events using one-off
 // queries or continuous event subscriptions.
 type ContractFilterer interface {
@@ -582,6 +582,63 @@ func TestSimulatedBackend_EstimateGasWithPrice(t *testing.T) {
 	}
 }
 
+func TestSimulatedBackend_CreateAccessList(t *testing.T) {
+	// The caller reads slot 0 of the callee through a static call, failing if
+	// the call fails, so that both the callee and its slot can be warmed up by
+	// an access list.
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	opts, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+
+	var (
+		callee = common.HexToAddress("0xc0ffee")
+		caller = common.HexToAddress("0xca11e5")
+	)
+	sim := NewSimulatedBackend(genesisT.GenesisAlloc{
+		addr:   {Balance: big.NewInt(vars.Ether)},
+		callee: {Balance: new(big.Int), Code: common.FromHex("60005400")},
+		caller: {Balance: new(big.Int), Code: append(append(common.FromHex("600060006000600073"), callee.Bytes()...), common.FromHex("5afa15602457005bfe")...)},
+	}, 10000000)
+	defer sim.Close()
+
+	msg := ethereum.CallMsg{From: addr, To: &caller}
+	list, gas, vmErr, err := sim.CreateAccessList(context.Background(), msg)
+	if err != nil || vmErr != "" {
+		t.Fatalf("failed to create access list: %v %s", err, vmErr)
+	}
+	want := types.AccessList{{Address: callee, StorageKeys: []common.Hash{{}}}}
+	if !reflect.DeepEqual(*list, want) {
+		t.Fatalf("access list mismatch: have %v, want %v", *list, want)
+	}
+	estimate, err := bind.EstimateGasWithAccessList(context.Background(), sim, msg)
+	if err != nil {
+		t.Fatalf("failed to estimate gas: %v", err)
+	}
+	if estimate.GasWithAccessList >= estimate.Gas {
+		t.Fatalf("access list saves no gas: %d with list, %d without", estimate.GasWithAccessList, estimate.Gas)
+	}
+
+	// Transacting with an automatic access list picks the cheaper transaction
+	contract := bind.NewBoundContract(caller, abi.ABI{}, sim, sim, sim)
+	opts.AutoAccessList = true
+	tx, err := contract.RawTransact(opts, nil)
+	if err != nil {
+		t.Fatalf("failed to transact: %v", err)
+	}
+	if tx.Type() != types.AccessListTxType || !reflect.DeepEqual(tx.AccessList(), want) {
+		t.Fatalf("transaction mismatch: type %d, access list %v", tx.Type(), tx.AccessList())
+	}
+	sim.Commit()
+
+	receipt, err := sim.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatalf("failed to retrieve receipt: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful || receipt.GasUsed != gas {
+		t.Errorf("receipt mismatch: status %d, gas used %d, want %d", receipt.Status, receipt.GasUsed, gas)
+	}
+}
+
 func TestSimulatedBackend_HeaderByHash(t *testing.T) {
 	testAddr := crypto.PubkeyToAddress(testKey.PublicKey)
 
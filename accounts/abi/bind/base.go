@@ -53,6 +53,8 @@ type TransactOpts struct {
 	GasPrice *big.Int // Gas price to use for the transaction execution (nil = gas price oracle)
 	GasLimit uint64   // Gas limit to set for the transaction execution (0 = estimate)
 
+	AutoAccessList bool // Whether to attach an EIP-2930 access list to the transaction if it saves gas
+
 	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
 
 	NoSend bool // Do all transact steps but do not send the transaction
@@ -232,7 +234,8 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 		}
 	}
 	gasLimit := opts.GasLimit
-	if gasLimit == 0 {
+	var accessList types.AccessList
+	if gasLimit == 0 || opts.AutoAccessList {
 		// Gas estimation cannot succeed without code for method invocations
 		if contract != nil {
 			if code, err := c.transactor.PendingCodeAt(ensureContext(opts.Context), c.address); err != nil {
@@ -243,16 +246,42 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, i
 		}
 		// If the contract surely has code (or code is not needed), estimate the transaction
 		msg := ethereum.CallMsg{From: opts.From, To: contract, GasPrice: gasPrice, Value: value, Data: input}
-		gasLimit, err = c.transactor.EstimateGas(ensureContext(opts.Context), msg)
-		if err != nil {
-			return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
+		if opts.AutoAccessList {
+			estimate, err := EstimateGasWithAccessList(ensureContext(opts.Context), c.transactor, msg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
+			}
+			// Only attach the access list if it makes the transaction cheaper
+			gas := estimate.Gas
+			if estimate.GasWithAccessList < estimate.Gas {
+				accessList, gas = estimate.AccessList, estimate.GasWithAccessList
+			}
+			if gasLimit == 0 {
+				gasLimit = gas
+			}
+		} else {
+			gasLimit, err = c.transactor.EstimateGas(ensureContext(opts.Context), msg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
+			}
 		}
 	}
 	// Create the transaction, sign it and schedule it for execution
 	var rawTx *types.Transaction
-	if contract == nil {
+	switch {
+	case len(accessList) > 0:
+		rawTx = types.NewTx(&types.AccessListTx{
+			Nonce:      nonce,
+			GasPrice:   gasPrice,
+			Gas:        gasLimit,
+			To:         contract,
+			Value:      value,
+			Data:       input,
+			AccessList: accessList,
+		})
+	case contract == nil:
 		rawTx = types.NewContractCreation(nonce, value, gasLimit, gasPrice, input)
-	} else {
+	default:
 		rawTx = types.NewTransaction(nonce, c.address, value, gasLimit, gasPrice, input)
 	}
 	if opts.Signer == nil {
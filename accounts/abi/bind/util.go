@@ -19,8 +19,10 @@ package bind
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
@@ -74,3 +76,44 @@ func WaitDeployed(ctx context.Context, b DeployBackend, tx *types.Transaction) (
 	}
 	return receipt.ContractAddress, err
 }
+
+// AccessListEstimate is the gas needed by a transaction both without and with the
+// EIP-2930 access list created for it.
+type AccessListEstimate struct {
+	AccessList        types.AccessList // Access list created for the transaction
+	Gas               uint64           // Gas needed without the access list
+	GasWithAccessList uint64           // Gas needed with the access list attached
+}
+
+// EstimateGasWithAccessList creates an access list for the call, then estimates the
+// gas needed to execute it both without and with the list, based on the current
+// pending state of the backend. The backend must implement AccessListTransactor.
+//
+// EstimateGas itself keeps reporting a single cost, as eth_estimateGas does across
+// clients, so that both costs are only reported here.
+func EstimateGasWithAccessList(ctx context.Context, b ContractTransactor, call ethereum.CallMsg) (*AccessListEstimate, error) {
+	creator, ok := b.(AccessListTransactor)
+	if !ok {
+		return nil, ErrNoAccessList
+	}
+	list, _, vmErr, err := creator.CreateAccessList(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+	if vmErr != "" {
+		return nil, fmt.Errorf("failed to create access list: %s", vmErr)
+	}
+	estimate := new(AccessListEstimate)
+	if list != nil {
+		estimate.AccessList = *list
+	}
+	call.AccessList = nil
+	if estimate.Gas, err = b.EstimateGas(ctx, call); err != nil {
+		return nil, err
+	}
+	call.AccessList = estimate.AccessList
+	if estimate.GasWithAccessList, err = b.EstimateGas(ctx, call); err != nil {
+		return nil, err
+	}
+	return estimate, nil
+}
@@ -74,6 +74,7 @@ var (
 	allToolsArchiveFiles = []string{
 		"COPYING",
 		executablePath("abigen"),
+		executablePath("ancient-store"),
 		executablePath("bootnode"),
 		executablePath("echainspec"),
 		executablePath("evm"),
@@ -88,6 +89,10 @@ var (
 			BinaryName:  "abigen",
 			Description: "Source code generator to convert Ethereum contract definitions into easy to use, compile-time type-safe Go packages.",
 		},
+		{
+			BinaryName:  "ancient-store",
+			Description: "Remote ancient store server, storing ancient chain data in flat files for geth --ancient.rpc.",
+		},
 		{
 			BinaryName:  "bootnode",
 			Description: "Ethereum bootnode.",
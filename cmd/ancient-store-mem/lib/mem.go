@@ -22,6 +22,9 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 )
 
 const (
@@ -78,6 +81,27 @@ func (f *MemFreezerRemoteServerAPI) Ancient(kind string, number uint64) ([]byte,
 	return v, nil
 }
 
+func (f *MemFreezerRemoteServerAPI) AncientRange(kind string, start, count, maxBytes uint64) ([]hexutil.Bytes, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if start >= f.count {
+		return nil, errOutOfBounds
+	}
+	var (
+		items []hexutil.Bytes
+		size  uint64
+	)
+	for number := start; number < f.count && uint64(len(items)) < count; number++ {
+		v := f.store[f.storeKey(kind, number)]
+		if len(items) > 0 && maxBytes > 0 && size+uint64(len(v)) > maxBytes {
+			break
+		}
+		items = append(items, v)
+		size += uint64(len(v))
+	}
+	return items, nil
+}
+
 func (f *MemFreezerRemoteServerAPI) Ancients() (uint64, error) {
 	// fmt.Println("mock server called", "method=Ancients")
 	return f.count, nil
@@ -117,6 +141,15 @@ func (f *MemFreezerRemoteServerAPI) AppendAncient(number uint64, hash, header, b
 	return nil
 }
 
+func (f *MemFreezerRemoteServerAPI) AppendAncients(items []rawdb.FreezerRemoteItem) error {
+	for _, item := range items {
+		if err := f.AppendAncient(item.Number, item.Hash, item.Header, item.Body, item.Receipts, item.Td); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (f *MemFreezerRemoteServerAPI) TruncateAncients(n uint64) error {
 	// fmt.Println("mock server called", "method=TruncateAncients")
 	f.count = n
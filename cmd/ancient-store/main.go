@@ -0,0 +1,176 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of core-geth.
+//
+// core-geth is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// core-geth is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with core-geth. If not, see <http://www.gnu.org/licenses/>.
+
+// ancient-store is a remote ancient store server, for use with geth --ancient.rpc.
+// Ancient data is stored in append-only flat files, in the same format as
+// used by the builtin ancient store (--datadir.ancient).
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	// Git SHA1 commit hash of the release (set via linker flags)
+	gitCommit = ""
+	gitDate   = ""
+)
+
+var (
+	datadirFlag = cli.StringFlag{
+		Name:  "datadir",
+		Usage: "Directory for the ancient store flat files",
+	}
+	readonlyFlag = cli.BoolFlag{
+		Name:  "readonly",
+		Usage: "Open the ancient store in read-only mode",
+	}
+	ipcPathFlag = cli.StringFlag{
+		Name:  "ipcpath",
+		Usage: "Filename for the IPC socket/pipe (disabled if empty)",
+	}
+	httpAddrFlag = cli.StringFlag{
+		Name:  "http.addr",
+		Usage: "HTTP and WebSocket server listening interface",
+		Value: "localhost",
+	}
+	httpPortFlag = cli.IntFlag{
+		Name:  "http.port",
+		Usage: "HTTP and WebSocket server listening port (disabled if 0)",
+		Value: 8547,
+	}
+	wsOriginsFlag = cli.StringFlag{
+		Name:  "ws.origins",
+		Usage: "Comma separated list of origins from which to accept WebSocket requests",
+	}
+	verbosityFlag = cli.IntFlag{
+		Name:  "verbosity",
+		Usage: "Logging verbosity: 0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=detail",
+		Value: 3,
+	}
+)
+
+var app = flags.NewApp(gitCommit, gitDate, "remote ancient store server")
+
+func init() {
+	app.Flags = []cli.Flag{
+		datadirFlag,
+		readonlyFlag,
+		ipcPathFlag,
+		httpAddrFlag,
+		httpPortFlag,
+		wsOriginsFlag,
+		verbosityFlag,
+	}
+	app.Action = serve
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// isWebsocket checks the header of an http request for a websocket upgrade request.
+func isWebsocket(r *http.Request) bool {
+	return strings.ToLower(r.Header.Get("Upgrade")) == "websocket" &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
+func serve(ctx *cli.Context) error {
+	log.Root().SetHandler(log.LvlFilterHandler(log.Lvl(ctx.Int(verbosityFlag.Name)), log.StreamHandler(os.Stderr, log.TerminalFormat(true))))
+
+	datadir := ctx.String(datadirFlag.Name)
+	if datadir == "" {
+		return errors.New("--datadir is required")
+	}
+	ipcPath, port := ctx.String(ipcPathFlag.Name), ctx.Int(httpPortFlag.Name)
+	if ipcPath == "" && port == 0 {
+		return errors.New("no endpoint enabled, use --ipcpath and/or --http.port")
+	}
+	store, err := rawdb.NewFreezerAncientStore(datadir, "", ctx.Bool(readonlyFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	api := rawdb.NewFreezerRemoteServerAPI(store)
+
+	errc := make(chan error, 1)
+	if ipcPath != "" {
+		listener, server, err := rpc.StartIPCEndpoint(ipcPath, []rpc.API{
+			{Namespace: "freezer", Version: "1.0", Service: api, Public: true},
+		})
+		if err != nil {
+			return err
+		}
+		defer server.Stop()
+		defer listener.Close()
+		log.Info("IPC endpoint opened", "url", ipcPath)
+	}
+	if port != 0 {
+		server := rpc.NewServer()
+		defer server.Stop()
+		if err := server.RegisterName("freezer", api); err != nil {
+			return err
+		}
+		var origins []string
+		if o := ctx.String(wsOriginsFlag.Name); o != "" {
+			origins = strings.Split(o, ",")
+		}
+		wsHandler := server.WebsocketHandler(origins)
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isWebsocket(r) {
+				wsHandler.ServeHTTP(w, r)
+				return
+			}
+			server.ServeHTTP(w, r)
+		})
+		listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", ctx.String(httpAddrFlag.Name), port))
+		if err != nil {
+			return err
+		}
+		defer listener.Close()
+		log.Info("HTTP and WebSocket endpoint opened", "url", "http://"+listener.Addr().String())
+		go func() { errc <- http.Serve(listener, handler) }()
+	}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+
+	select {
+	case sig := <-sigc:
+		log.Info("Shutting down", "signal", sig)
+	case err := <-errc:
+		return err
+	}
+	return store.Sync()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ethereum/go-ethereum/params/confp"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	diffFormatInFlag = cli.StringFlag{
+		Name:  "inputf",
+		Usage: "Format type of the configuration to compare against (defaults to --inputf)",
+	}
+	diffDefaultValueFlag = cli.StringFlag{
+		Name:  "default",
+		Usage: "Compare against a default chainspec value",
+	}
+)
+
+var diffCommand = cli.Command{
+	Name:        "diff",
+	Usage:       "Show the differences between the configuration and another",
+	ArgsUsage:   "[<file>]",
+	Description: "Prints differing parameters by name as '<name>: <value>/<other value>', and the first block at which a chain would be incompatible. Exits 0 if there are no differences, 1 if there are.",
+	Flags: []cli.Flag{
+		diffFormatInFlag,
+		diffDefaultValueFlag,
+	},
+	Action: diff,
+}
+
+var (
+	errNoDiffValue = errors.New("diff requires a file argument or --default value to compare against")
+	errDiffers     = errors.New("configurations differ")
+)
+
+func diff(ctx *cli.Context) error {
+	other, err := diffChainspecValue(ctx)
+	if err != nil {
+		return err
+	}
+
+	diffs := confp.Diff(globalChainspecValue, other)
+	for _, d := range diffs {
+		fmt.Printf("%s: %s/%s\n", d.Field, diffPrintValue(d.A), diffPrintValue(d.B))
+	}
+
+	n, compatErr := confp.FirstIncompatibleBlock(globalChainspecValue, other)
+	if compatErr != nil {
+		fmt.Printf("Incompatible at block %d: %v\n", *n, compatErr)
+	}
+	if len(diffs) > 0 || compatErr != nil {
+		return errDiffers
+	}
+	return nil
+}
+
+func diffChainspecValue(ctx *cli.Context) (ctypes.Configurator, error) {
+	if name := ctx.String(diffDefaultValueFlag.Name); name != "" {
+		v, ok := defaultChainspecValues[name]
+		if !ok {
+			return nil, fmt.Errorf("error: %v, name: %s", errInvalidDefaultValue, name)
+		}
+		return v, nil
+	}
+	if !ctx.Args().Present() {
+		return nil, errNoDiffValue
+	}
+	data, err := ioutil.ReadFile(ctx.Args().First())
+	if err != nil {
+		return nil, err
+	}
+	format := ctx.String(diffFormatInFlag.Name)
+	if format == "" {
+		format = ctx.GlobalString(formatInFlag.Name)
+	}
+	return unmarshalChainSpec(format, data)
+}
+
+func diffPrintValue(v interface{}) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%v", v)
+}
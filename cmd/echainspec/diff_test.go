@@ -0,0 +1,53 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of core-geth.
+//
+// core-geth is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// core-geth is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with core-geth. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffIdentical(t *testing.T) {
+	// Same configuration, as builtin default.
+	diff := runEchainspec(t, "--default", "classic", "diff", "--default", "classic")
+	diff.ExpectExit()
+	if status := diff.ExitStatus(); status != 0 {
+		t.Errorf("exit status mismatch: have %d, want 0", status)
+	}
+
+	// Same configuration, in different formats.
+	testdata := filepath.Join("..", "..", "params", "confp", "testdata")
+	diff = runEchainspec(t, "--inputf", "parity", "--file", filepath.Join(testdata, "stureby_parity.json"),
+		"diff", "--inputf", "nethermind", filepath.Join(testdata, "stureby_nethermind.json"))
+	diff.ExpectExit()
+	if status := diff.ExitStatus(); status != 0 {
+		t.Errorf("exit status mismatch: have %d, want 0", status)
+	}
+}
+
+func TestDiffDifferent(t *testing.T) {
+	diff := runEchainspec(t, "--default", "classic", "diff", "--default", "mordor")
+	diff.ExpectRegexp(`(?s)^.*\nNetworkID: 1/7\n.*\nIncompatible at block 0: .*$`)
+	diff.ExpectExit()
+	if status := diff.ExitStatus(); status != 1 {
+		t.Errorf("exit status mismatch: have %d, want 1", status)
+	}
+	if stderr := diff.StderrText(); !strings.Contains(stderr, errDiffers.Error()) {
+		t.Errorf("stderr %q does not report %q", stderr, errDiffers)
+	}
+}
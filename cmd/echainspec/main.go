@@ -9,11 +9,13 @@ import (
 
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/params/confp"
+	"github.com/ethereum/go-ethereum/params/types/besu"
 	"github.com/ethereum/go-ethereum/params/types/coregeth"
 	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/params/types/genesisT"
 	"github.com/ethereum/go-ethereum/params/types/goethereum"
 	"github.com/ethereum/go-ethereum/params/types/multigeth"
+	"github.com/ethereum/go-ethereum/params/types/nethermind"
 	"github.com/ethereum/go-ethereum/params/types/parity"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -22,17 +24,26 @@ var gitCommit = "" // Git SHA1 commit hash of the release (set via linker flags)
 var gitDate = ""
 
 var (
-	chainspecFormatTypes = map[string]ctypes.Configurator{
-		"coregeth": &genesisT.Genesis{
-			Config: &coregeth.CoreGethChainConfig{},
+	// chainspecFormatTypes maps format names to constructors for the respective configuration data types.
+	chainspecFormatTypes = map[string]func() ctypes.Configurator{
+		"coregeth": func() ctypes.Configurator {
+			return &genesisT.Genesis{Config: &coregeth.CoreGethChainConfig{}}
 		},
-		"multigeth": &genesisT.Genesis{
-			Config: &multigeth.ChainConfig{},
+		"multigeth": func() ctypes.Configurator {
+			return &genesisT.Genesis{Config: &multigeth.ChainConfig{}}
 		},
-		"geth": &genesisT.Genesis{
-			Config: &goethereum.ChainConfig{},
+		"geth": func() ctypes.Configurator {
+			return &genesisT.Genesis{Config: &goethereum.ChainConfig{}}
+		},
+		"besu": func() ctypes.Configurator {
+			return &genesisT.Genesis{Config: &besu.ChainConfig{}}
+		},
+		"parity": func() ctypes.Configurator {
+			return &parity.ParityChainSpec{}
+		},
+		"nethermind": func() ctypes.Configurator {
+			return &nethermind.NethermindChainSpec{}
 		},
-		"parity": &parity.ParityChainSpec{},
 		// TODO
 		// "aleth"
 		// "retesteth"
@@ -130,7 +141,7 @@ func mustGetChainspecValue(ctx *cli.Context) error {
 }
 
 func convertf(ctx *cli.Context) error {
-	c, ok := chainspecFormatTypes[ctx.String(outputFormatFlag.Name)]
+	newc, ok := chainspecFormatTypes[ctx.String(outputFormatFlag.Name)]
 	if !ok && ctx.String(outputFormatFlag.Name) == "" {
 		b, err := jsonMarshalPretty(globalChainspecValue)
 		if err != nil {
@@ -141,6 +152,7 @@ func convertf(ctx *cli.Context) error {
 	} else if !ok {
 		return errInvalidOutputFlag
 	}
+	c := newc()
 	err := confp.Convert(globalChainspecValue, c)
 	if err != nil {
 		return err
@@ -200,10 +212,18 @@ EXAMPLES:
 
 		> {{.Name}} --inputf parity --file my-parity-spec.json --outputf [geth|coregeth]
 
+	Convert a Hyperledger Besu genesis file to coregeth format.
+
+		> {{.Name}} --inputf besu --file my-besu-genesis.json --outputf coregeth
+
 	Print a default Ethereum Classic network chain configuration in coregeth format:
 
 		> {{.Name}} --default classic --outputf coregeth
 
+	Show how a Besu genesis file differs from the default Ethereum Classic configuration:
+
+		> {{.Name}} --inputf besu --file my-besu-genesis.json diff --default classic
+
 	Validate a default Kotti network chain configuration for block #3000000:
 
 		> {{.Name}} --default kotti validate 3000000
@@ -231,6 +251,7 @@ GLOBAL OPTIONS:
 		validateCommand,
 		forksCommand,
 		ipsCommand,
+		diffCommand,
 	}
 	app.Before = mustGetChainspecValue
 	app.Action = convertf
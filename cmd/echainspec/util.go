@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/types/nethermind"
 	"github.com/ethereum/go-ethereum/params/types/parity"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -21,46 +21,41 @@ func readInputData(ctx *cli.Context) ([]byte, error) {
 }
 
 func unmarshalChainSpec(format string, data []byte) (conf ctypes.Configurator, err error) {
-	conf, ok := chainspecFormatTypes[format]
+	newConf, ok := chainspecFormatTypes[format]
 	if !ok {
 		return nil, errInvalidChainspecValue
 	}
-	err = json.Unmarshal(data, conf)
-	if err != nil {
-		return conf, err
-	}
-	if !strings.Contains(format, "geth") {
-		return
-	}
+	conf = newConf()
 	// Logic in params/types/gen_genesis.go already "auto-magically"
 	// handles genesis Config unmarshaling, and IT PREFERS COREGETH,
-	// and the two data types are not mutually exclusive (are overlapping).
+	// and the data types are not mutually exclusive (are overlapping).
 	// So we need to redo custom unmarshaling logic to enforce data type
 	// preference based on passed format value.
-	type dec struct {
-		Config ctypes.ChainConfigurator `json:"config"`
-	}
-	var d dec
-	configurator, ok := chainspecFormatTypes[format]
-	if !ok {
-		return nil, fmt.Errorf("unknown chainspec format type: %v", format)
-	}
-	switch t := configurator.(type) {
+	// The preferred Config type is captured here because unmarshaling
+	// the genesis replaces it.
+	var config ctypes.ChainConfigurator
+	switch t := conf.(type) {
 	case *genesisT.Genesis:
-		d.Config = t.Config
-	case *parity.ParityChainSpec:
-		// Don't need to do anything here; the Parity type already conforms to ChainConfigurator.
+		config = t.Config
+	case *parity.ParityChainSpec, *nethermind.NethermindChainSpec:
+		// Don't need to do anything here; these types already conform to ChainConfigurator.
 	default:
 		return nil, fmt.Errorf("unhandled chainspec type: %v %v", format, t)
 	}
-	t := chainspecFormatTypes[format].(*genesisT.Genesis)
+	err = json.Unmarshal(data, conf)
+	if err != nil || config == nil {
+		return conf, err
+	}
+	type dec struct {
+		Config ctypes.ChainConfigurator `json:"config"`
+	}
+	d := dec{Config: config}
 	err = json.Unmarshal(data, &d)
 	if err != nil {
 		return conf, err
 	}
-	t.Config = d.Config
-	conf = t
-	return
+	conf.(*genesisT.Genesis).Config = d.Config
+	return conf, nil
 }
 
 func jsonMarshalPretty(i interface{}) ([]byte, error) {
@@ -0,0 +1,169 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of core-geth.
+//
+// core-geth is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// core-geth is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with core-geth. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params/confp"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/tests"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	AnalyzeForkFlag = cli.StringFlag{
+		Name: "fork",
+		Usage: fmt.Sprintf("Name of the ruleset to analyze the code with, unless a prestate config is given."+
+			"\n\tAvailable forknames:"+
+			"\n\t    %v", strings.Join(tests.AvailableForks(), "\n\t    ")),
+		Value: "London",
+	}
+	AnalyzeBlockFlag = cli.Uint64Flag{
+		Name:  "block",
+		Usage: "Block number selecting the active forks of the chain config (default = latest fork)",
+	}
+)
+
+var analyzeCommand = cli.Command{
+	Action:    analyzeCmd,
+	Name:      "analyze",
+	Usage:     "statically analyzes evm binary",
+	ArgsUsage: "<code>",
+	Description: `The analyze command splits EVM code into basic blocks, using the instruction
+set of the fork selected either by --fork or by the chain config of --prestate at
+--block. It reports the invalid instructions, the unreachable code, the stack
+height bounds and the static gas of every block.`,
+	Flags: []cli.Flag{
+		AnalyzeForkFlag,
+		AnalyzeBlockFlag,
+	},
+}
+
+// analyzedInstruction is an invalid instruction, as reported in JSON.
+type analyzedInstruction struct {
+	PC        uint64 `json:"pc"`
+	Op        string `json:"op"`
+	Reachable bool   `json:"reachable"`
+}
+
+func analyzeCmd(ctx *cli.Context) error {
+	code, err := analyzedCode(ctx)
+	if err != nil {
+		return err
+	}
+	var (
+		name   string
+		config ctypes.ChainConfigurator
+	)
+	if path := ctx.GlobalString(GenesisFlag.Name); path != "" {
+		name, config = path, readGenesis(path).Config
+	} else {
+		name = ctx.String(AnalyzeForkFlag.Name)
+		if config = tests.Forks[name]; config == nil {
+			return fmt.Errorf("unsupported fork %q", name)
+		}
+	}
+	// Default to the rules of the latest fork scheduled in the config
+	var number uint64
+	if ctx.IsSet(AnalyzeBlockFlag.Name) {
+		number = ctx.Uint64(AnalyzeBlockFlag.Name)
+	} else if forks := confp.Forks(config); len(forks) > 0 {
+		number = forks[len(forks)-1]
+	}
+	analysis := vm.AnalyzeCode(code, config, new(big.Int).SetUint64(number))
+
+	if ctx.GlobalBool(MachineFlag.Name) {
+		invalid := make([]analyzedInstruction, len(analysis.Invalid))
+		for i, instr := range analysis.Invalid {
+			invalid[i] = analyzedInstruction{PC: instr.PC, Op: fmt.Sprintf("%#x", byte(instr.Op)), Reachable: instr.Reachable}
+		}
+		out, _ := json.MarshalIndent(map[string]interface{}{
+			"block":   number,
+			"blocks":  analysis.Blocks,
+			"invalid": invalid,
+		}, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Printf("Analyzed %d bytes with the rules of %s at block %d\n\n", len(code), name, number)
+	fmt.Printf("%8s %8s %9s %7s %8s %6s %10s\n", "start", "end", "reachable", "stackIn", "stackMax", "delta", "gas")
+	for _, block := range analysis.Blocks {
+		gas := fmt.Sprint(block.Gas)
+		if block.DynamicGas {
+			gas += "+"
+		}
+		fmt.Printf("%8d %8d %9v %7d %8d %6d %10s\n", block.Start, block.End, block.Reachable, block.StackIn, block.StackMax, block.StackDelta, gas)
+	}
+	fmt.Println("\n(+: dynamic gas charged on top of the static gas)")
+
+	if len(analysis.Invalid) > 0 {
+		fmt.Println("\nInvalid instructions:")
+		for _, instr := range analysis.Invalid {
+			reach := "reachable"
+			if !instr.Reachable {
+				reach = "unreachable"
+			}
+			fmt.Printf("  pc %d: %v (%s)\n", instr.PC, instr.Op, reach)
+		}
+	}
+	if unreachable := analysis.Unreachable(); len(unreachable) > 0 {
+		fmt.Println("\nUnreachable code:")
+		for _, block := range unreachable {
+			fmt.Printf("  %d-%d (%d bytes)\n", block.Start, block.End, block.End-block.Start)
+		}
+	}
+	return nil
+}
+
+// analyzedCode reads the hex encoded code to analyze from the arguments, or the
+// --code and --codefile flags.
+func analyzedCode(ctx *cli.Context) ([]byte, error) {
+	var (
+		hexcode []byte
+		err     error
+	)
+	switch {
+	case ctx.Args().First() != "":
+		hexcode = []byte(ctx.Args().First())
+	case ctx.GlobalString(CodeFlag.Name) != "":
+		hexcode = []byte(ctx.GlobalString(CodeFlag.Name))
+	case ctx.GlobalString(CodeFileFlag.Name) == "-":
+		hexcode, err = ioutil.ReadAll(os.Stdin)
+	case ctx.GlobalString(CodeFileFlag.Name) != "":
+		hexcode, err = ioutil.ReadFile(ctx.GlobalString(CodeFileFlag.Name))
+	default:
+		return nil, errors.New("missing code, or --code or --codefile value")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not load code: %v", err)
+	}
+	code, err := hex.DecodeString(string(bytes.TrimPrefix(bytes.TrimSpace(hexcode), []byte("0x"))))
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex code: %v", err)
+	}
+	return code, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of core-geth.
+//
+// core-geth is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// core-geth is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with core-geth. If not, see <http://www.gnu.org/licenses/>.
+
+package t8ntool
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/confp"
+	"github.com/ethereum/go-ethereum/params/confp/generic"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/tests"
+)
+
+// networkConfigs are the chain configs which can be selected by network name
+// in place of a fork name, keyed by the lowercase name. They are shared with the
+// rest of the process, so only copies of them are handed out.
+var networkConfigs = map[string]ctypes.ChainConfigurator{
+	"classic": params.ClassicChainConfig,
+	"mordor":  params.MordorChainConfig,
+	"kotti":   params.KottiChainConfig,
+	"mintme":  params.MintMeChainConfig,
+}
+
+// availableNetworks returns the network names accepted in place of a fork name.
+func availableNetworks() []string {
+	var names []string
+	for name := range networkConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getChainConfig returns the chain configuration and the extra EIPs defined by
+// the fork definition, which can be
+// - a state test fork, with a list of EIPs to enable; e.g. `Byzantium+1884+1283`,
+// - a network name, with a list of EIPs to enable; e.g. `Mordor` or `classic+2929`,
+// - the path to a chainspec or genesis file in any format supported by confp.
+// Network configs and chainspecs describe a whole chain, whose chain id and
+// block rewards are used rather than the ones given on the command line.
+func getChainConfig(fork string) (config ctypes.ChainConfigurator, eips []int, chainspec bool, err error) {
+	if info, err := os.Stat(fork); err == nil && !info.IsDir() {
+		config, err := readChainspec(fork)
+		return config, nil, true, err
+	}
+	parts := strings.Split(fork, "+")
+	if network, ok := networkConfigs[strings.ToLower(parts[0])]; ok {
+		config := new(coregeth.CoreGethChainConfig)
+		if err := confp.Convert(network, config); err != nil {
+			return nil, nil, false, fmt.Errorf("failed copying %s config: %v", parts[0], err)
+		}
+		for _, eip := range parts[1:] {
+			num, err := strconv.Atoi(eip)
+			if err != nil || !vm.ValidEip(num) {
+				return nil, nil, false, fmt.Errorf("syntax error, invalid eip number %v", eip)
+			}
+			eips = append(eips, num)
+		}
+		return config, eips, true, nil
+	}
+	config, eips, err = tests.GetChainConfig(fork)
+	return config, eips, false, err
+}
+
+// readChainspec reads a chain configuration from a chainspec file, or from the
+// config of a genesis file.
+func readChainspec(path string) (ctypes.ChainConfigurator, error) {
+	blob, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading chainspec: %v", err)
+	}
+	var genesis struct {
+		Config json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(blob, &genesis); err != nil {
+		return nil, fmt.Errorf("failed unmarshaling chainspec: %v", err)
+	}
+	if len(genesis.Config) > 0 {
+		blob = genesis.Config
+	}
+	config, err := generic.UnmarshalChainConfigurator(blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed unmarshaling chainspec: %v", err)
+	}
+	return config, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of core-geth.
+//
+// core-geth is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// core-geth is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with core-geth. If not, see <http://www.gnu.org/licenses/>.
+
+package t8ntool
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+)
+
+// Tests that network configs are copied, so that setting the chain id of the
+// transition leaves the config of the network untouched.
+func TestGetChainConfigNetworkCopy(t *testing.T) {
+	config, _, chainspec, err := getChainConfig("Mordor+2929")
+	if err != nil {
+		t.Fatalf("failed to get chain config: %v", err)
+	}
+	if !chainspec {
+		t.Errorf("network not reported as a chainspec")
+	}
+	want := params.MordorChainConfig.GetChainID().Uint64()
+	if err := config.SetChainID(big.NewInt(1)); err != nil {
+		t.Fatalf("failed to set chain id: %v", err)
+	}
+	if have := params.MordorChainConfig.GetChainID().Uint64(); have != want {
+		t.Errorf("network chain id mutated: have %d, want %d", have, want)
+	}
+}
+
+// Tests that the era rewards of a network are applied, using the transition
+// in testdata/9.
+func TestApplyNetworkRewards(t *testing.T) {
+	dir := filepath.Join("..", "..", "testdata", "9")
+	read := func(name string, v interface{}) {
+		t.Helper()
+		blob, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if err := json.Unmarshal(blob, v); err != nil {
+			t.Fatalf("failed to decode %s: %v", name, err)
+		}
+	}
+	var (
+		prestate Prestate
+		want     genesisT.GenesisAlloc
+	)
+	read("alloc.json", &prestate.Pre)
+	read("env.json", &prestate.Env)
+	read("exp.json", &want)
+
+	config, _, _, err := getChainConfig("mordor")
+	if err != nil {
+		t.Fatalf("failed to get chain config: %v", err)
+	}
+	for _, test := range []struct {
+		reward       int64
+		chainRewards bool
+		want         genesisT.GenesisAlloc
+	}{
+		{0, true, want},
+		{0, false, genesisT.GenesisAlloc{}}, // Touched only, and so removed
+	} {
+		statedb, _, err := prestate.Apply(vm.Config{}, config, nil, test.reward, test.chainRewards, func(int, common.Hash) (vm.Tracer, error) { return nil, nil })
+		if err != nil {
+			t.Fatalf("failed to apply transition: %v", err)
+		}
+		collector := make(Alloc)
+		statedb.DumpToCollector(collector, false, false, false, nil, -1)
+		if len(collector) != len(test.want) {
+			t.Errorf("chain rewards %v: account count mismatch: have %d, want %d", test.chainRewards, len(collector), len(test.want))
+		}
+		for addr, account := range test.want {
+			if have := collector[addr].Balance; have == nil || have.Cmp(account.Balance) != 0 {
+				t.Errorf("chain rewards %v: balance mismatch for %x: have %v, want %v", test.chainRewards, addr, have, account.Balance)
+			}
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/lyra2"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -70,6 +71,7 @@ type stEnv struct {
 	Timestamp   uint64                              `json:"currentTimestamp"  gencodec:"required"`
 	BlockHashes map[math.HexOrDecimal64]common.Hash `json:"blockHashes,omitempty"`
 	Ommers      []ommer                             `json:"ommers,omitempty"`
+	BaseFee     *big.Int                            `json:"currentBaseFee,omitempty"`
 }
 
 type stEnvMarshaling struct {
@@ -78,11 +80,13 @@ type stEnvMarshaling struct {
 	GasLimit   math.HexOrDecimal64
 	Number     math.HexOrDecimal64
 	Timestamp  math.HexOrDecimal64
+	BaseFee    *math.HexOrDecimal256
 }
 
-// Apply applies a set of transactions to a pre-state
+// Apply applies a set of transactions to a pre-state. If chainRewards is set, the
+// block and ommer rewards of the chain config are applied instead of miningReward.
 func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig ctypes.ChainConfigurator,
-	txs types.Transactions, miningReward int64,
+	txs types.Transactions, miningReward int64, chainRewards bool,
 	getTracerFn func(txIndex int, txHash common.Hash) (tracer vm.Tracer, err error)) (*state.StateDB, *ExecutionResult, error) {
 
 	// Capture errors for BLOCKHASH operation, if we haven't been supplied the
@@ -121,6 +125,10 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig ctypes.ChainConfigura
 		GasLimit:    pre.Env.GasLimit,
 		GetHash:     getHash,
 	}
+	// If currentBaseFee is defined, add it to the vmContext.
+	if pre.Env.BaseFee != nil {
+		vmContext.BaseFee = new(big.Int).Set(pre.Env.BaseFee)
+	}
 	// If DAO is supported/enabled, we need to handle it here. In geth 'proper', it's
 	// done in StateProcessor.Process(block, ...), right before transactions are applied.
 	isDAOSupport := chainConfig.IsEnabled(chainConfig.GetEthashEIP779Transition, new(big.Int).SetUint64(pre.Env.Number))
@@ -131,7 +139,7 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig ctypes.ChainConfigura
 	}
 
 	for i, tx := range txs {
-		msg, err := tx.AsMessage(signer)
+		msg, err := tx.AsMessage(signer, pre.Env.BaseFee)
 		if err != nil {
 			log.Info("rejected tx", "index", i, "hash", tx.Hash(), "error", err)
 			rejectedTxs = append(rejectedTxs, i)
@@ -226,6 +234,23 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig ctypes.ChainConfigura
 			statedb.AddBalance(ommer.Address, reward)
 		}
 		statedb.AddBalance(pre.Env.Coinbase, minerReward)
+	} else if chainRewards {
+		// Pay the rewards the consensus engine of the chain would, ommers being
+		// rewarded by their number rather than by their delta
+		header := &types.Header{Number: vmContext.BlockNumber, Coinbase: pre.Env.Coinbase}
+		ommers := make([]*types.Header, len(pre.Env.Ommers))
+		for i, ommer := range pre.Env.Ommers {
+			ommers[i] = &types.Header{
+				Number:   new(big.Int).Sub(vmContext.BlockNumber, new(big.Int).SetUint64(ommer.Delta)),
+				Coinbase: ommer.Address,
+			}
+		}
+		switch engine := chainConfig.GetConsensusEngineType(); {
+		case engine.IsEthash():
+			mutations.AccumulateRewards(chainConfig, statedb, header, ommers)
+		case engine.IsLyra2():
+			lyra2.AccumulateRewards(chainConfig, statedb, header, ommers)
+		}
 	}
 	// Commit block
 	root, err := statedb.Commit(chainConfig.IsEnabled(chainConfig.GetEIP161dTransition, vmContext.BlockNumber))
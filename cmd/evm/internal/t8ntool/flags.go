@@ -85,7 +85,7 @@ var (
 	}
 	RewardFlag = cli.Int64Flag{
 		Name:  "state.reward",
-		Usage: "Mining reward. Set to -1 to disable",
+		Usage: "Mining reward. Set to -1 to disable. Networks and chainspecs given as state.fork pay their own rewards unless set",
 		Value: 0,
 	}
 	ChainIDFlag = cli.Int64Flag{
@@ -95,13 +95,16 @@ var (
 	}
 	ForknameFlag = cli.StringFlag{
 		Name: "state.fork",
-		Usage: fmt.Sprintf("Name of ruleset to use."+
+		Usage: fmt.Sprintf("Name of ruleset to use, network name or path to a chainspec file."+
 			"\n\tAvailable forknames:"+
 			"\n\t    %v"+
+			"\n\tAvailable networks:"+
+			"\n\t    %v"+
 			"\n\tAvailable extra eips:"+
 			"\n\t    %v"+
-			"\n\tSyntax <forkname>(+ExtraEip)",
+			"\n\tSyntax <forkname|network>(+ExtraEip) or <chainspec file>",
 			strings.Join(tests.AvailableForks(), "\n\t    "),
+			strings.Join(availableNetworks(), ", "),
 			strings.Join(vm.ActivateableEips(), ", ")),
 		Value: "Istanbul",
 	}
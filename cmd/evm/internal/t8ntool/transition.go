@@ -19,6 +19,7 @@ package t8ntool
 import (
 	"crypto/ecdsa"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -32,10 +33,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/params/types/genesisT"
 	"github.com/ethereum/go-ethereum/rlp"
-	"github.com/ethereum/go-ethereum/tests"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -178,16 +177,23 @@ func Main(ctx *cli.Context) error {
 		Debug:  (tracer != nil),
 	}
 	// Construct the chainconfig
-	var chainConfig ctypes.ChainConfigurator
-	if cConf, extraEips, err := tests.GetChainConfig(ctx.String(ForknameFlag.Name)); err != nil {
+	chainConfig, extraEips, chainspec, err := getChainConfig(ctx.String(ForknameFlag.Name))
+	if err != nil {
 		return NewError(ErrorVMConfig, fmt.Errorf("Failed constructing chain configuration: %v", err))
-	} else {
-		chainConfig = cConf
-		vmConfig.ExtraEips = extraEips
 	}
-	// Set the chain id
-	if err := chainConfig.SetChainID(big.NewInt(ctx.Int64(ChainIDFlag.Name))); err != nil {
-		return err
+	vmConfig.ExtraEips = extraEips
+
+	// Set the chain id, chainspecs keeping their own unless overridden
+	if !chainspec || ctx.IsSet(ChainIDFlag.Name) {
+		if err := chainConfig.SetChainID(big.NewInt(ctx.Int64(ChainIDFlag.Name))); err != nil {
+			return err
+		}
+	}
+	// Sanity check, to not `panic` in state_transition
+	if chainConfig.IsEnabled(chainConfig.GetEIP1559Transition, big.NewInt(int64(prestate.Env.Number))) {
+		if prestate.Env.BaseFee == nil {
+			return NewError(ErrorVMConfig, errors.New("EIP-1559 config but missing 'currentBaseFee' in env section"))
+		}
 	}
 
 	var txsWithKeys []*txWithKey
@@ -214,7 +220,9 @@ func Main(ctx *cli.Context) error {
 	// Iterate over all the tests, run them and aggregate the results
 
 	// Run the test and aggregate the result
-	state, result, err := prestate.Apply(vmConfig, chainConfig, txs, ctx.Int64(RewardFlag.Name), getTracer)
+	// Chainspecs pay their scheduled rewards, unless a reward is set explicitly
+	chainRewards := chainspec && !ctx.IsSet(RewardFlag.Name)
+	state, result, err := prestate.Apply(vmConfig, chainConfig, txs, ctx.Int64(RewardFlag.Name), chainRewards, getTracer)
 	if err != nil {
 		return err
 	}
@@ -188,6 +188,7 @@ func init() {
 		EVMInterpreterFlag,
 	}
 	app.Commands = []cli.Command{
+		analyzeCommand,
 		compileCommand,
 		disasmCommand,
 		runCommand,
@@ -17,11 +17,16 @@
 package clique
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -175,3 +180,179 @@ func (api *API) Status() (*status, error) {
 		NumBlocks:     numBlocks,
 	}, nil
 }
+
+const (
+	// maxVoteHistory is the maximum number of blocks a vote history can be
+	// retrieved for at once.
+	maxVoteHistory = 65536
+
+	// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
+	chainHeadChanSize = 10
+)
+
+// SignerChange is a change of the signer set, caused by a passed vote.
+type SignerChange struct {
+	Block      uint64         `json:"block"`      // Block number the signer set changed in
+	Address    common.Address `json:"address"`    // Account whose authorization changed
+	Authorized bool           `json:"authorized"` // Whether the account was authorized or deauthorized
+}
+
+// VoteHistory is the governance history of a range of blocks.
+type VoteHistory struct {
+	From    uint64                   `json:"from"`
+	To      uint64                   `json:"to"`
+	Votes   []*Vote                  `json:"votes"`   // Every vote cast in the range, including the ones without effect
+	Changes []*SignerChange          `json:"changes"` // Every change of the signer set in the range
+	Sealed  map[common.Address]int   `json:"sealed"`  // Number of blocks sealed by each signer in the range
+	Signers []common.Address         `json:"signers"` // Signer set at the end of the range
+	Tally   map[common.Address]Tally `json:"tally"`   // Pending vote tally at the end of the range
+}
+
+// GetVoteHistory retrieves every vote cast and every change of the signer set
+// in the given range of blocks, along with the number of blocks sealed by each
+// signer. The range defaults to the current block if no end is given.
+func (api *API) GetVoteHistory(from rpc.BlockNumber, to *rpc.BlockNumber) (*VoteHistory, error) {
+	head := api.chain.CurrentHeader().Number.Uint64()
+	end := head
+	if to != nil && *to >= 0 {
+		end = uint64(to.Int64())
+	}
+	start := head
+	if from >= 0 {
+		start = uint64(from.Int64())
+	}
+	if start == 0 {
+		start = 1 // The genesis block casts no votes
+	}
+	if end > head {
+		return nil, errUnknownBlock
+	}
+	if start > end {
+		return nil, fmt.Errorf("invalid vote history range %d-%d", start, end)
+	}
+	if end-start >= maxVoteHistory {
+		return nil, fmt.Errorf("vote history range %d-%d too large, maximum is %d blocks", start, end, maxVoteHistory)
+	}
+	parent := api.chain.GetHeaderByNumber(start - 1)
+	if parent == nil {
+		return nil, errUnknownBlock
+	}
+	snap, err := api.clique.snapshot(api.chain, parent.Number.Uint64(), parent.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	history := &VoteHistory{
+		From:    start,
+		To:      end,
+		Votes:   []*Vote{},
+		Changes: []*SignerChange{},
+		Sealed:  make(map[common.Address]int),
+	}
+	for n := start; n <= end; n++ {
+		header := api.chain.GetHeaderByNumber(n)
+		if header == nil {
+			return nil, fmt.Errorf("missing block %d", n)
+		}
+		signer, err := ecrecover(header, api.clique.signatures)
+		if err != nil {
+			return nil, err
+		}
+		history.Sealed[signer]++
+		if vote := api.clique.vote(header, signer); vote != nil {
+			history.Votes = append(history.Votes, vote)
+		}
+		next, err := snap.apply([]*types.Header{header})
+		if err != nil {
+			return nil, err
+		}
+		for address := range next.Signers {
+			if _, ok := snap.Signers[address]; !ok {
+				history.Changes = append(history.Changes, &SignerChange{Block: n, Address: address, Authorized: true})
+			}
+		}
+		for address := range snap.Signers {
+			if _, ok := next.Signers[address]; !ok {
+				history.Changes = append(history.Changes, &SignerChange{Block: n, Address: address, Authorized: false})
+			}
+		}
+		snap = next
+	}
+	history.Signers = snap.signers()
+	history.Tally = snap.Tally
+	return history, nil
+}
+
+// chainHeadSubscriber is a chain announcing its new head blocks.
+type chainHeadSubscriber interface {
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Votes creates a subscription that is fired with every vote cast in the blocks
+// becoming part of the canonical chain.
+func (api *API) Votes(ctx context.Context) (*rpc.Subscription, error) {
+	chain, ok := api.chain.(chainHeadSubscriber)
+	if !ok {
+		return nil, errors.New("chain events not supported")
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	heads := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	sub := chain.SubscribeChainHeadEvent(heads)
+	last := api.chain.CurrentHeader().Number.Uint64()
+
+	go func() {
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-heads:
+				// Gather the blocks added since the last notified one, including
+				// the new head in case of a reorg to a shorter chain
+				number := ev.Block.NumberU64()
+				if last >= number {
+					last = number - 1
+				}
+				var headers []*types.Header
+				for header := ev.Block.Header(); header != nil && header.Number.Uint64() > last; {
+					headers = append(headers, header)
+					header = api.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+				}
+				last = number
+
+				for i := len(headers) - 1; i >= 0; i-- {
+					signer, err := ecrecover(headers[i], api.clique.signatures)
+					if err != nil {
+						continue
+					}
+					if vote := api.clique.vote(headers[i], signer); vote != nil {
+						notifier.Notify(rpcSub.ID, vote)
+					}
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// vote returns the vote cast by the signer of a header, if any. Checkpoint
+// blocks cast no votes.
+func (c *Clique) vote(header *types.Header, signer common.Address) *Vote {
+	number := header.Number.Uint64()
+	if number%c.config.Epoch == 0 || header.Coinbase == (common.Address{}) {
+		return nil
+	}
+	return &Vote{
+		Signer:    signer,
+		Block:     number,
+		Address:   header.Coinbase,
+		Authorize: bytes.Equal(header.Nonce[:], nonceAuthVote),
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package clique
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newVotingChain creates a chain signed by A and B, and the blocks of a voting
+// round adding C and dropping B afterwards.
+func newVotingChain(t *testing.T, accounts *testerAccountPool) (*core.BlockChain, *Clique, []*types.Block) {
+	votes := []testerVote{
+		{signer: "A", voted: "C", auth: true},
+		{signer: "B", voted: "C", auth: true}, // C authorized
+		{signer: "C"},
+		{signer: "A", voted: "B"},
+		{signer: "C", voted: "B"}, // B deauthorized
+	}
+	signers := []common.Address{accounts.address("A"), accounts.address("B")}
+	sort.Sort(signersAscending(signers))
+
+	genesis := &genesisT.Genesis{
+		ExtraData: make([]byte, extraVanity+common.AddressLength*len(signers)+extraSeal),
+	}
+	for i, signer := range signers {
+		copy(genesis.ExtraData[extraVanity+i*common.AddressLength:], signer[:])
+	}
+	db := rawdb.NewMemoryDatabase()
+	core.MustCommitGenesis(db, genesis)
+
+	config := *params.TestChainConfig
+	config.Clique = &ctypes.CliqueConfig{Period: 1}
+	engine := New(config.Clique, db)
+	engine.fakeDiff = true
+
+	blocks, _ := core.GenerateChain(&config, core.GenesisToBlock(genesis, db), engine, db, len(votes), func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(accounts.address(votes[i].voted))
+		if votes[i].auth {
+			var nonce types.BlockNonce
+			copy(nonce[:], nonceAuthVote)
+			gen.SetNonce(nonce)
+		}
+	})
+	for i, block := range blocks {
+		header := block.Header()
+		if i > 0 {
+			header.ParentHash = blocks[i-1].Hash()
+		}
+		header.Extra = make([]byte, extraVanity+extraSeal)
+		header.Difficulty = diffInTurn
+
+		accounts.sign(header, votes[i].signer)
+		blocks[i] = block.WithSeal(header)
+	}
+	chain, err := core.NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create test chain: %v", err)
+	}
+	return chain, engine, blocks
+}
+
+// Tests that the vote history reports the votes, signer changes and sealing
+// counts of a range of blocks.
+func TestGetVoteHistory(t *testing.T) {
+	accounts := newTesterAccountPool()
+	chain, engine, blocks := newVotingChain(t, accounts)
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	api := &API{chain: chain, clique: engine}
+
+	from, to := rpc.BlockNumber(1), rpc.LatestBlockNumber
+	history, err := api.GetVoteHistory(from, &to)
+	if err != nil {
+		t.Fatalf("failed to retrieve vote history: %v", err)
+	}
+	a, b, c := accounts.address("A"), accounts.address("B"), accounts.address("C")
+	wantVotes := []*Vote{
+		{Signer: a, Block: 1, Address: c, Authorize: true},
+		{Signer: b, Block: 2, Address: c, Authorize: true},
+		{Signer: a, Block: 4, Address: b, Authorize: false},
+		{Signer: c, Block: 5, Address: b, Authorize: false},
+	}
+	if !reflect.DeepEqual(history.Votes, wantVotes) {
+		t.Errorf("votes mismatch: have %v, want %v", history.Votes, wantVotes)
+	}
+	wantChanges := []*SignerChange{
+		{Block: 2, Address: c, Authorized: true},
+		{Block: 5, Address: b, Authorized: false},
+	}
+	if !reflect.DeepEqual(history.Changes, wantChanges) {
+		t.Errorf("signer changes mismatch: have %v, want %v", history.Changes, wantChanges)
+	}
+	wantSealed := map[common.Address]int{a: 2, b: 1, c: 2}
+	if !reflect.DeepEqual(history.Sealed, wantSealed) {
+		t.Errorf("sealing counts mismatch: have %v, want %v", history.Sealed, wantSealed)
+	}
+	wantSigners := []common.Address{a, c}
+	sort.Sort(signersAscending(wantSigners))
+	if !reflect.DeepEqual(history.Signers, wantSigners) {
+		t.Errorf("signers mismatch: have %x, want %x", history.Signers, wantSigners)
+	}
+	// Check that a partial range starts from the right signer set
+	from, to = 3, 4
+	if history, err = api.GetVoteHistory(from, &to); err != nil {
+		t.Fatalf("failed to retrieve partial vote history: %v", err)
+	}
+	if len(history.Votes) != 1 || len(history.Changes) != 0 {
+		t.Errorf("partial history mismatch: have %d votes and %d changes, want 1 and 0", len(history.Votes), len(history.Changes))
+	}
+	if history.Tally[b] != (Tally{Authorize: false, Votes: 1}) {
+		t.Errorf("tally mismatch: have %v, want 1 vote to drop", history.Tally[b])
+	}
+	// Check that ranges beyond the head are rejected
+	to = rpc.BlockNumber(len(blocks) + 1)
+	if _, err := api.GetVoteHistory(from, &to); err != errUnknownBlock {
+		t.Errorf("error mismatch: have %v, want %v", err, errUnknownBlock)
+	}
+}
+
+// Tests that votes are streamed to subscribers as blocks are imported.
+func TestVotesSubscription(t *testing.T) {
+	accounts := newTesterAccountPool()
+	chain, engine, blocks := newVotingChain(t, accounts)
+	defer chain.Stop()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("clique", &API{chain: chain, clique: engine}); err != nil {
+		t.Fatalf("failed to register api: %v", err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	votes := make(chan *Vote)
+	sub, err := client.Subscribe(context.Background(), "clique", votes, "votes")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	for _, want := range []uint64{1, 2, 4, 5} {
+		select {
+		case vote := <-votes:
+			if vote.Block != want {
+				t.Errorf("vote block mismatch: have %d, want %d", vote.Block, want)
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("vote of block %d not received", want)
+		}
+	}
+}
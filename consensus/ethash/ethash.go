@@ -547,6 +547,10 @@ type Config struct {
 	// be block header JSON objects instead of work package arrays.
 	NotifyFull bool
 
+	// StratumAddr is the TCP address the Stratum server for remote miners
+	// listens on, the server being disabled if empty.
+	StratumAddr string
+
 	Log log.Logger `toml:"-"`
 	// ECIP-1099
 	ECIP1099Block *uint64 `toml:"-"`
@@ -693,6 +697,15 @@ func (ethash *Ethash) Close() error {
 	return nil
 }
 
+// StratumError returns the error the Stratum server for remote miners failed
+// to start with, if it was configured.
+func (ethash *Ethash) StratumError() error {
+	if ethash.remote == nil {
+		return nil
+	}
+	return ethash.remote.stratumErr
+}
+
 // cache tries to retrieve a verification cache for the specified block number
 // by first checking against a list of in-memory caches, then against caches
 // stored on disk, and finally generating one if none can be found.
@@ -22,6 +22,7 @@ import (
 	crand "crypto/rand"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"math/rand"
@@ -253,6 +254,8 @@ type remoteSealer struct {
 	submitWorkCh chan *mineResult // Channel used for remote sealer to submit their mining result
 	fetchRateCh  chan chan uint64 // Channel used to gather submitted hash rate for local or remote sealer.
 	submitRateCh chan *hashrate   // Channel used for remote sealer to submit their mining hashrate
+	stratum      *stratumServer   // Stratum server for remote miners, if enabled
+	stratumErr   error            // Error the Stratum server failed to start with
 	requestExit  chan struct{}
 	exitCh       chan struct{}
 }
@@ -305,6 +308,13 @@ func startRemoteSealer(ethash *Ethash, urls []string, noverify bool) *remoteSeal
 		requestExit:  make(chan struct{}),
 		exitCh:       make(chan struct{}),
 	}
+	if addr := ethash.config.StratumAddr; addr != "" {
+		stratum, err := startStratumServer(s, addr)
+		if err != nil {
+			err = fmt.Errorf("failed to start Stratum server on %s: %v", addr, err)
+		}
+		s.stratum, s.stratumErr = stratum, err
+	}
 	go s.loop()
 	return s
 }
@@ -314,6 +324,9 @@ func (s *remoteSealer) loop() {
 		s.ethash.config.Log.Trace("Ethash remote sealer is exiting")
 		s.cancelNotify()
 		s.reqWG.Wait()
+		if s.stratum != nil {
+			s.stratum.close()
+		}
 		close(s.exitCh)
 	}()
 
@@ -328,6 +341,9 @@ func (s *remoteSealer) loop() {
 			s.results = work.results
 			s.makeWork(work.block)
 			s.notifyWork()
+			if s.stratum != nil {
+				s.stratum.notify(s.currentWork, s.currentBlock)
+			}
 
 		case work := <-s.fetchWorkCh:
 			// Return current mining work to remote miner.
@@ -0,0 +1,582 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// stratumProtocol is the protocol spoken by the Stratum server, as specified
+	// at https://github.com/nicehash/Specifications/blob/master/EthereumStratum_NiceHash_v1.0.0.txt
+	stratumProtocol = "EthereumStratum/1.0.0"
+
+	stratumExtranonceSize = 2                // Bytes of the nonce assigned to a session
+	stratumReadTimeout    = 10 * time.Minute // Time a session can stay idle before being dropped
+	stratumWriteTimeout   = 10 * time.Second
+	stratumSendQueue      = 16 // Messages queued for a session before it's considered stalled
+	stratumMaxRequest     = 16 * 1024
+)
+
+var (
+	// stratumDiffOne is the share target of difficulty 1 in Stratum, which is
+	// 2^32 times lower than the one of the ethash difficulty 1.
+	stratumDiffOne = new(big.Int).Lsh(big.NewInt(0xffff), 208)
+
+	errStratumNotSubscribed = errors.New("not subscribed")
+	errStratumNotAuthorized = errors.New("unauthorized worker")
+	errStratumUnknownJob    = errors.New("job not found")
+	errStratumInvalidNonce  = errors.New("invalid nonce")
+)
+
+// Error codes of the Stratum protocol.
+const (
+	stratumErrOther         = 20
+	stratumErrUnknownJob    = 21
+	stratumErrInvalidShare  = 23
+	stratumErrUnauthorized  = 24
+	stratumErrNotSubscribed = 25
+)
+
+// stratumRequest is a request sent by a miner.
+type stratumRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Worker string          `json:"worker,omitempty"`
+}
+
+// stratumResponse is the response to a request of a miner.
+type stratumResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  interface{}     `json:"error"`
+}
+
+// stratumNotification is a message pushed to a miner.
+type stratumNotification struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params interface{}     `json:"params"`
+}
+
+// stratumJob is a work package announced to the miners.
+type stratumJob struct {
+	id         string
+	number     uint64
+	seedHash   string
+	headerHash string
+	difficulty float64
+}
+
+// stratumServer serves the work packages of the remote sealer to miners over
+// the EthereumStratum/1.0.0 protocol, submitting their solutions and hash rates
+// to the remote sealer the same way the ethash API does.
+type stratumServer struct {
+	ethash   *Ethash
+	sealer   *remoteSealer
+	listener net.Listener
+
+	lock        sync.Mutex
+	sessions    map[*stratumSession]struct{}
+	jobs        map[string]*stratumJob // Announced jobs by ID, pruned once stale
+	current     *stratumJob
+	extranonce  uint16              // Extranonce to try assigning to the next session
+	extranonces map[uint16]struct{} // Extranonces assigned to live sessions
+	closed      bool                // Whether the server is closing, rejecting new sessions
+
+	wg sync.WaitGroup
+}
+
+// startStratumServer starts listening for miners on the given address.
+func startStratumServer(sealer *remoteSealer, addr string) (*stratumServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := &stratumServer{
+		ethash:      sealer.ethash,
+		sealer:      sealer,
+		listener:    listener,
+		sessions:    make(map[*stratumSession]struct{}),
+		jobs:        make(map[string]*stratumJob),
+		extranonces: make(map[uint16]struct{}),
+	}
+	s.ethash.config.Log.Info("Stratum server started", "addr", listener.Addr(), "protocol", stratumProtocol)
+
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// serve accepts the connections of the miners until the listener is closed.
+func (s *stratumServer) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return
+		}
+		s.lock.Lock()
+		if s.closed {
+			s.lock.Unlock()
+			conn.Close()
+			return
+		}
+		extranonce, ok := s.allocExtranonce()
+		if !ok {
+			s.lock.Unlock()
+			s.ethash.config.Log.Warn("Stratum session rejected, extranonces exhausted", "remote", conn.RemoteAddr())
+			conn.Close()
+			continue
+		}
+		session := newStratumSession(s, conn, extranonce)
+		s.sessions[session] = struct{}{}
+		s.lock.Unlock()
+
+		s.wg.Add(2)
+		go session.readLoop()
+		go session.writeLoop()
+	}
+}
+
+// allocExtranonce assigns an extranonce not used by any live session, so that
+// miners never search the same nonces. It must be called with the lock held.
+func (s *stratumServer) allocExtranonce() (uint16, bool) {
+	if len(s.extranonces) > math.MaxUint16 {
+		return 0, false
+	}
+	for {
+		extranonce := s.extranonce
+		s.extranonce++
+		if _, ok := s.extranonces[extranonce]; !ok {
+			s.extranonces[extranonce] = struct{}{}
+			return extranonce, true
+		}
+	}
+}
+
+// close stops accepting miners and disconnects all the sessions.
+func (s *stratumServer) close() {
+	s.listener.Close()
+
+	s.lock.Lock()
+	s.closed = true
+	for session := range s.sessions {
+		session.close()
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+	s.ethash.config.Log.Info("Stratum server stopped")
+}
+
+// notify announces a new work package to the authorized miners.
+func (s *stratumServer) notify(work [4]string, block *types.Block) {
+	job := &stratumJob{
+		id:         strings.TrimPrefix(work[0], "0x"),
+		number:     block.NumberU64(),
+		seedHash:   strings.TrimPrefix(work[1], "0x"),
+		headerHash: strings.TrimPrefix(work[0], "0x"),
+		difficulty: stratumDifficulty(block.Difficulty()),
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.jobs[job.id] = job
+	s.current = job
+	for id, old := range s.jobs {
+		if old.number+staleThreshold <= job.number {
+			delete(s.jobs, id)
+		}
+	}
+	for session := range s.sessions {
+		session.sendJob(job)
+	}
+}
+
+// job returns the announced job with the given ID, if not stale yet.
+func (s *stratumServer) job(id string) *stratumJob {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.jobs[strings.TrimPrefix(id, "0x")]
+}
+
+// currentJob returns the last announced job, if any.
+func (s *stratumServer) currentJob() *stratumJob {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.current
+}
+
+// remove forgets a closed session.
+func (s *stratumServer) remove(session *stratumSession) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.sessions[session]; ok {
+		delete(s.sessions, session)
+		delete(s.extranonces, session.nonceID)
+	}
+}
+
+// submitWork submits a solution to the remote sealer, returning whether it was
+// accepted.
+func (s *stratumServer) submitWork(nonce types.BlockNonce, hash, digest common.Hash) bool {
+	errc := make(chan error, 1)
+	select {
+	case s.sealer.submitWorkCh <- &mineResult{nonce: nonce, mixDigest: digest, hash: hash, errc: errc}:
+	case <-s.sealer.requestExit:
+		return false
+	}
+	return <-errc == nil
+}
+
+// submitHashrate submits the hash rate of a miner to the remote sealer.
+func (s *stratumServer) submitHashrate(rate uint64, id common.Hash) bool {
+	done := make(chan struct{})
+	select {
+	case s.sealer.submitRateCh <- &hashrate{done: done, rate: rate, id: id}:
+	case <-s.sealer.requestExit:
+		return false
+	}
+	<-done
+	return true
+}
+
+// mixDigest computes the mix digest of a solution, which miners don't submit in
+// the Stratum protocol.
+func (s *stratumServer) mixDigest(number uint64, hash common.Hash, nonce uint64) common.Hash {
+	// Fake seals aren't verified, don't bother generating a cache for them
+	switch s.ethash.config.PowMode {
+	case ModeFake, ModeFullFake, ModePoissonFake:
+		return common.Hash{}
+	}
+	cache := s.ethash.cache(number)
+	epochLength := calcEpochLength(number, s.ethash.config.ECIP1099Block)
+	size := datasetSize(calcEpoch(number, epochLength))
+	if s.ethash.config.PowMode == ModeTest {
+		size = 32 * 1024
+	}
+	digest, _ := hashimotoLight(size, cache.cache, hash.Bytes(), nonce)
+
+	// Caches are unmapped in a finalizer. Ensure that the cache stays alive
+	// until after the call to hashimotoLight so it's not unmapped while being used.
+	runtime.KeepAlive(cache)
+	return common.BytesToHash(digest)
+}
+
+// stratumDifficulty converts a block difficulty into a Stratum share difficulty.
+func stratumDifficulty(difficulty *big.Int) float64 {
+	target := new(big.Int).Div(two256, difficulty)
+	if target.Sign() == 0 {
+		target.SetUint64(1)
+	}
+	diff, _ := new(big.Float).Quo(new(big.Float).SetInt(stratumDiffOne), new(big.Float).SetInt(target)).Float64()
+	return diff
+}
+
+// stratumSession is the connection of a single miner, which may run any number
+// of workers.
+type stratumSession struct {
+	server     *stratumServer
+	conn       net.Conn
+	nonceID    uint16 // High order bytes of the nonces searched by the miner
+	extranonce string // Hex encoded nonceID, as sent to the miner
+
+	lock       sync.Mutex
+	subscribed bool
+	workers    map[string]struct{} // Authorized workers
+	difficulty float64             // Last difficulty sent to the miner
+
+	out       chan interface{}
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newStratumSession(server *stratumServer, conn net.Conn, extranonce uint16) *stratumSession {
+	var blob [stratumExtranonceSize]byte
+	binary.BigEndian.PutUint16(blob[:], extranonce)
+
+	return &stratumSession{
+		server:     server,
+		conn:       conn,
+		nonceID:    extranonce,
+		extranonce: hex.EncodeToString(blob[:]),
+		workers:    make(map[string]struct{}),
+		out:        make(chan interface{}, stratumSendQueue),
+		closed:     make(chan struct{}),
+	}
+}
+
+// close disconnects the miner.
+func (s *stratumSession) close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		s.conn.Close()
+	})
+}
+
+// readLoop handles the requests of the miner until the connection is closed.
+func (s *stratumSession) readLoop() {
+	defer s.server.wg.Done()
+	defer s.server.remove(s)
+	defer s.close()
+
+	log := s.server.ethash.config.Log.New("remote", s.conn.RemoteAddr())
+	log.Debug("Stratum session opened", "extranonce", s.extranonce)
+
+	reader := bufio.NewReaderSize(s.conn, stratumMaxRequest)
+	for {
+		s.conn.SetReadDeadline(time.Now().Add(stratumReadTimeout))
+
+		line, err := reader.ReadSlice('\n')
+		if err != nil {
+			log.Debug("Stratum session closed", "err", err)
+			return
+		}
+		var req stratumRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			log.Debug("Stratum session sent invalid request", "err", err)
+			return
+		}
+		result, code, err := s.handle(&req)
+		res := &stratumResponse{ID: req.ID, Result: result}
+		if err != nil {
+			log.Debug("Stratum request failed", "method", req.Method, "err", err)
+			res.Result, res.Error = false, []interface{}{code, err.Error(), nil}
+		}
+		if !s.send(res) {
+			return
+		}
+		// Newly authorized workers need to know what to work on
+		if req.Method == "mining.authorize" && err == nil {
+			if job := s.server.currentJob(); job != nil {
+				s.sendJob(job)
+			}
+		}
+	}
+}
+
+// writeLoop writes the queued messages to the miner until the connection is closed.
+func (s *stratumSession) writeLoop() {
+	defer s.server.wg.Done()
+	defer s.close()
+
+	enc := json.NewEncoder(s.conn)
+	for {
+		select {
+		case msg := <-s.out:
+			s.conn.SetWriteDeadline(time.Now().Add(stratumWriteTimeout))
+			if err := enc.Encode(msg); err != nil {
+				return
+			}
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+// send queues a response to the miner, returning false if the connection is closed.
+func (s *stratumSession) send(msg interface{}) bool {
+	select {
+	case s.out <- msg:
+		return true
+	case <-s.closed:
+		return false
+	}
+}
+
+// sendJob announces a job to the miner if any of its workers is authorized,
+// preceded by its share difficulty if it changed. Miners not keeping up with
+// the announcements are disconnected, as they'd be mining stale work anyway.
+func (s *stratumSession) sendJob(job *stratumJob) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.workers) == 0 {
+		return
+	}
+	var msgs []interface{}
+	if job.difficulty != s.difficulty {
+		s.difficulty = job.difficulty
+		msgs = append(msgs, &stratumNotification{
+			ID:     json.RawMessage("null"),
+			Method: "mining.set_difficulty",
+			Params: []interface{}{job.difficulty},
+		})
+	}
+	msgs = append(msgs, &stratumNotification{
+		ID:     json.RawMessage("null"),
+		Method: "mining.notify",
+		Params: []interface{}{job.id, job.seedHash, job.headerHash, true},
+	})
+	for _, msg := range msgs {
+		select {
+		case s.out <- msg:
+		case <-s.closed:
+			return
+		default:
+			s.server.ethash.config.Log.Warn("Dropping stalled Stratum miner", "remote", s.conn.RemoteAddr())
+			s.close()
+			return
+		}
+	}
+}
+
+// handle executes a request of the miner, returning its result or the error
+// code and error to respond with.
+func (s *stratumSession) handle(req *stratumRequest) (interface{}, int, error) {
+	var params []json.RawMessage
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, stratumErrOther, err
+		}
+	}
+	strParam := func(i int) (string, error) {
+		var str string
+		if i >= len(params) {
+			return "", fmt.Errorf("missing parameter %d", i)
+		}
+		err := json.Unmarshal(params[i], &str)
+		return str, err
+	}
+	switch req.Method {
+	case "mining.subscribe":
+		if len(params) > 1 {
+			if protocol, err := strParam(1); err != nil || !strings.HasPrefix(protocol, "EthereumStratum/") {
+				return nil, stratumErrOther, fmt.Errorf("unsupported protocol, want %s", stratumProtocol)
+			}
+		}
+		s.lock.Lock()
+		s.subscribed = true
+		s.lock.Unlock()
+		return []interface{}{[]string{"mining.notify", s.extranonce, stratumProtocol}, s.extranonce}, 0, nil
+
+	case "mining.extranonce.subscribe":
+		// The extranonce of a session never changes
+		return true, 0, nil
+
+	case "mining.authorize":
+		worker, err := strParam(0)
+		if err != nil {
+			return nil, stratumErrOther, err
+		}
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		if !s.subscribed {
+			return nil, stratumErrNotSubscribed, errStratumNotSubscribed
+		}
+		s.workers[worker] = struct{}{}
+		s.server.ethash.config.Log.Info("Stratum worker authorized", "worker", worker, "remote", s.conn.RemoteAddr())
+		return true, 0, nil
+
+	case "mining.submit":
+		var args [3]string
+		for i := range args {
+			arg, err := strParam(i)
+			if err != nil {
+				return nil, stratumErrOther, err
+			}
+			args[i] = arg
+		}
+		if !s.authorized(args[0]) {
+			return nil, stratumErrUnauthorized, errStratumNotAuthorized
+		}
+		job := s.server.job(args[1])
+		if job == nil {
+			return nil, stratumErrUnknownJob, errStratumUnknownJob
+		}
+		nonce, err := s.nonce(args[2])
+		if err != nil {
+			return nil, stratumErrInvalidShare, err
+		}
+		hash := common.HexToHash(job.headerHash)
+		digest := s.server.mixDigest(job.number, hash, nonce)
+		if !s.server.submitWork(types.EncodeNonce(nonce), hash, digest) {
+			return nil, stratumErrInvalidShare, errInvalidSealResult
+		}
+		s.server.ethash.config.Log.Info("Stratum solution accepted", "worker", args[0], "number", job.number, "nonce", nonce)
+		return true, 0, nil
+
+	case "eth_submitHashrate":
+		rate, err := strParam(0)
+		if err != nil {
+			return nil, stratumErrOther, err
+		}
+		value, err := hexutil.DecodeUint64(rate)
+		if err != nil {
+			return nil, stratumErrOther, err
+		}
+		// Account the hash rate of each worker separately, as they may share
+		// the identifier reported by the mining software.
+		worker := req.Worker
+		if worker == "" {
+			worker = s.extranonce
+		}
+		id := crypto.Keccak256Hash([]byte(s.extranonce), []byte(worker))
+		return s.server.submitHashrate(value, id), 0, nil
+	}
+	return nil, stratumErrOther, fmt.Errorf("method %q not found", req.Method)
+}
+
+// authorized returns whether the worker has been authorized in this session.
+func (s *stratumSession) authorized(worker string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, ok := s.workers[worker]
+	return ok
+}
+
+// nonce assembles the full nonce of a solution from the extranonce of the session
+// and the remaining bytes submitted by the miner.
+func (s *stratumSession) nonce(suffix string) (uint64, error) {
+	suffix = strings.TrimPrefix(suffix, "0x")
+	if len(s.extranonce)+len(suffix) != 2*len(types.BlockNonce{}) {
+		return 0, fmt.Errorf("%w: %s", errStratumInvalidNonce, suffix)
+	}
+	blob, err := hex.DecodeString(s.extranonce + suffix)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", errStratumInvalidNonce, suffix)
+	}
+	return binary.BigEndian.Uint64(blob), nil
+}
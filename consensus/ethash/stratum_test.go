@@ -0,0 +1,256 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// stratumTestClient is a miner speaking the Stratum protocol.
+type stratumTestClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+	id     int
+}
+
+func newStratumTestClient(t *testing.T, ethash *Ethash) *stratumTestClient {
+	conn, err := net.Dial("tcp", ethash.remote.stratum.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect to stratum server: %v", err)
+	}
+	return &stratumTestClient{t: t, conn: conn, reader: bufio.NewReader(conn)}
+}
+
+// call sends a request and returns the response, which must be the next message
+// received from the server.
+func (c *stratumTestClient) call(method string, params ...interface{}) (result json.RawMessage, err json.RawMessage) {
+	c.id++
+	blob, _ := json.Marshal(map[string]interface{}{"id": c.id, "method": method, "params": params})
+	if _, err := c.conn.Write(append(blob, '\n')); err != nil {
+		c.t.Fatalf("failed to send %s: %v", method, err)
+	}
+	var res struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	c.read(&res)
+	if res.ID != c.id {
+		c.t.Fatalf("response id mismatch: have %d, want %d", res.ID, c.id)
+	}
+	return res.Result, res.Error
+}
+
+// notification waits for a notification of the given method and returns its params.
+func (c *stratumTestClient) notification(method string) []interface{} {
+	var msg struct {
+		Method string        `json:"method"`
+		Params []interface{} `json:"params"`
+	}
+	c.read(&msg)
+	if msg.Method != method {
+		c.t.Fatalf("notification mismatch: have %s, want %s", msg.Method, method)
+	}
+	return msg.Params
+}
+
+func (c *stratumTestClient) read(v interface{}) {
+	c.conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		c.t.Fatalf("failed to read from stratum server: %v", err)
+	}
+	if err := json.Unmarshal(line, v); err != nil {
+		c.t.Fatalf("failed to decode %s: %v", line, err)
+	}
+}
+
+// Tests that miners are notified of new work over Stratum, and that their
+// solutions are verified and submitted as sealed blocks.
+func TestStratumSubmit(t *testing.T) {
+	ethash := New(Config{PowMode: ModeTest, StratumAddr: "127.0.0.1:0"}, nil, false)
+	ethash.SetThreads(-1) // Only mine through the stratum client
+	defer ethash.Close()
+
+	client := newStratumTestClient(t, ethash)
+	defer client.conn.Close()
+
+	// Subscribe and authorize a worker
+	result, _ := client.call("mining.subscribe", "test/1.0.0", stratumProtocol)
+	var subscription []json.RawMessage
+	if err := json.Unmarshal(result, &subscription); err != nil || len(subscription) != 2 {
+		t.Fatalf("invalid subscription: %s", result)
+	}
+	var extranonce string
+	json.Unmarshal(subscription[1], &extranonce)
+	if len(extranonce) != 2*stratumExtranonceSize {
+		t.Fatalf("invalid extranonce: %s", extranonce)
+	}
+	if _, err := client.call("mining.submit", "worker", "00", "000000000000"); string(err) == "null" {
+		t.Fatalf("unauthorized submission accepted")
+	}
+	if result, _ := client.call("mining.authorize", "0x0000000000000000000000000000000000000000.worker", "x"); string(result) != "true" {
+		t.Fatalf("authorization failed: %s", result)
+	}
+	// Seal a block and wait for the work to be announced
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+	block := types.NewBlockWithHeader(header)
+	results := make(chan *types.Block, 1)
+	ethash.Seal(nil, block, results, nil)
+
+	if diff := client.notification("mining.set_difficulty"); diff[0].(float64) != stratumDifficulty(header.Difficulty) {
+		t.Errorf("difficulty mismatch: have %v, want %v", diff[0], stratumDifficulty(header.Difficulty))
+	}
+	job := client.notification("mining.notify")
+	sealhash := ethash.SealHash(header)
+	if job[2] != hex.EncodeToString(sealhash[:]) {
+		t.Fatalf("header hash mismatch: have %v, want %x", job[2], sealhash)
+	}
+	// Search a valid and an invalid solution within the extranonce
+	prefix, _ := hex.DecodeString(extranonce)
+	var (
+		cache       = ethash.cache(1)
+		target      = new(big.Int).Div(two256, header.Difficulty)
+		valid       = -1
+		invalid     = -1
+		nonceSuffix = func(i int) string {
+			return fmt.Sprintf("%012x", i)
+		}
+	)
+	for i := 0; valid < 0 || invalid < 0; i++ {
+		nonce := uint64(binary.BigEndian.Uint16(prefix))<<48 | uint64(i)
+		_, result := hashimotoLight(32*1024, cache.cache, sealhash.Bytes(), nonce)
+		if new(big.Int).SetBytes(result).Cmp(target) <= 0 {
+			if valid < 0 {
+				valid = i
+			}
+		} else if invalid < 0 {
+			invalid = i
+		}
+	}
+	worker := "0x0000000000000000000000000000000000000000.worker"
+	if result, _ := client.call("mining.submit", worker, job[0], nonceSuffix(invalid)); string(result) != "false" {
+		t.Errorf("invalid solution accepted: %s", result)
+	}
+	if result, err := client.call("mining.submit", worker, job[0], nonceSuffix(valid)); string(result) != "true" {
+		t.Fatalf("valid solution rejected: %s", err)
+	}
+	select {
+	case sealed := <-results:
+		want := uint64(binary.BigEndian.Uint16(prefix))<<48 | uint64(valid)
+		if sealed.Nonce() != want {
+			t.Errorf("sealed nonce mismatch: have %x, want %x", sealed.Nonce(), want)
+		}
+		if err := ethash.verifySeal(nil, sealed.Header(), false); err != nil {
+			t.Errorf("sealed block invalid: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("sealed block not delivered")
+	}
+}
+
+// Tests that the hash rates of the Stratum workers are accounted separately.
+func TestStratumHashrate(t *testing.T) {
+	ethash := New(Config{PowMode: ModeTest, StratumAddr: "127.0.0.1:0"}, nil, false)
+	defer ethash.Close()
+
+	client := newStratumTestClient(t, ethash)
+	defer client.conn.Close()
+
+	id := common.HexToHash("0x01").Hex()
+	for i, worker := range []string{"rig1", "rig2"} {
+		client.id++
+		blob, _ := json.Marshal(map[string]interface{}{"id": client.id, "method": "eth_submitHashrate", "params": []string{fmt.Sprintf("%#x", 100*(i+1)), id}, "worker": worker})
+		client.conn.Write(append(blob, '\n'))
+
+		var res struct {
+			Result bool `json:"result"`
+		}
+		client.read(&res)
+		if !res.Result {
+			t.Fatalf("hashrate of %s rejected", worker)
+		}
+	}
+	if rate := ethash.Hashrate(); rate != 300 {
+		t.Errorf("hashrate mismatch: have %v, want %v", rate, 300)
+	}
+}
+
+// Tests that a Stratum server failing to listen is reported by the engine.
+func TestStratumListenFailure(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	ethash := New(Config{PowMode: ModeTest, StratumAddr: listener.Addr().String()}, nil, false)
+	defer ethash.Close()
+
+	if err := ethash.StratumError(); err == nil {
+		t.Fatalf("stratum server started on a used address")
+	}
+	if ethash.remote.stratum != nil {
+		t.Fatalf("stratum server running despite the failure")
+	}
+}
+
+// Tests that the extranonces of live sessions are never assigned twice, even once
+// the counter wraps around, and that those of closed sessions are reused.
+func TestStratumExtranonceAllocation(t *testing.T) {
+	s := &stratumServer{
+		sessions:    make(map[*stratumSession]struct{}),
+		extranonces: make(map[uint16]struct{}),
+		extranonce:  math.MaxUint16,
+	}
+	live := &stratumSession{nonceID: 0}
+	s.sessions[live] = struct{}{}
+	s.extranonces[live.nonceID] = struct{}{}
+
+	for _, want := range []uint16{math.MaxUint16, 1, 2} {
+		if have, ok := s.allocExtranonce(); !ok || have != want {
+			t.Fatalf("extranonce mismatch: have %d (%v), want %d", have, ok, want)
+		}
+	}
+	// Exhaust the extranonces, failing any further session
+	for len(s.extranonces) <= math.MaxUint16 {
+		if _, ok := s.allocExtranonce(); !ok {
+			t.Fatalf("extranonce not assigned with %d in use", len(s.extranonces))
+		}
+	}
+	if have, ok := s.allocExtranonce(); ok {
+		t.Fatalf("extranonce %d assigned while exhausted", have)
+	}
+	// Closing a session frees its extranonce
+	s.remove(live)
+	if have, ok := s.allocExtranonce(); !ok || have != live.nonceID {
+		t.Fatalf("extranonce mismatch: have %d (%v), want %d", have, ok, live.nonceID)
+	}
+}
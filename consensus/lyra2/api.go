@@ -5,11 +5,33 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var errLyra2Stopped = errors.New("lyra2 stopped")
 
+// APIs implements consensus.Engine, returning the user facing RPC APIs.
+func (lyra2 *Lyra2) APIs(chain consensus.ChainHeaderReader) []rpc.API {
+	// Like ethash, the remote sealing APIs are exposed to both the eth
+	// namespace, where mining software expects them, and the lyra2 namespace.
+	return []rpc.API{
+		{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   &API{lyra2},
+			Public:    true,
+		},
+		{
+			Namespace: "lyra2",
+			Version:   "1.0",
+			Service:   &API{lyra2},
+			Public:    true,
+		},
+	}
+}
+
 // API exposes lyra2 related methods for the RPC interface.
 type API struct {
 	lyra2 *Lyra2
@@ -73,7 +95,7 @@ func (api *API) SubmitWork(nonce types.BlockNonce, hash, digest common.Hash) boo
 //
 // It accepts the miner hash rate and an identifier which must be unique
 // between nodes.
-func (api *API) SubmitHashRate(rate hexutil.Uint64, id common.Hash) bool {
+func (api *API) SubmitHashrate(rate hexutil.Uint64, id common.Hash) bool {
 	if api.lyra2.remote == nil {
 		return false
 	}
@@ -1,32 +1,32 @@
 package lyra2
 
-/*
-#cgo CFLAGS: -std=gnu99
-#include "Lyra2.h"
-#include <stdlib.h>
-*/
-import "C"
 import (
     "encoding/binary"
-    "github.com/ethereum/go-ethereum/common"
-    "github.com/ethereum/go-ethereum/consensus"
     "github.com/ethereum/go-ethereum/log"
     "github.com/ethereum/go-ethereum/metrics"
-    "github.com/ethereum/go-ethereum/rpc"
     "math/big"
     "math/rand"
     "sync"
     "time"
-    "unsafe"
 )
 
+// Config are the configuration parameters of the lyra2 engine.
+type Config struct {
+    // When set, notifications sent by the remote sealer will
+    // be block header JSON objects instead of work package arrays.
+    NotifyFull bool
+}
+
 type Lyra2 struct {
+    config    Config
+
     fakeMode  bool
     fakeFail  uint64
     fakeDelay time.Duration
 
     log       log.Logger
     lock      sync.Mutex
+    closeOnce sync.Once // Ensures exit channel will not be closed twice.
 
     rand      *rand.Rand
     hashrate  metrics.Meter
@@ -35,8 +35,11 @@ type Lyra2 struct {
     remote    *remoteSealer
 }
 
-func New(notify []string, noverify bool) *Lyra2 {
+// New creates a lyra2 proof-of-work engine, notifying the given URLs of
+// new remote sealing work.
+func New(config Config, notify []string, noverify bool) *Lyra2 {
     lyra2 := &Lyra2{
+        config: config,
         fakeMode: false,
         fakeFail: 0,
         fakeDelay: 0,
@@ -48,6 +51,7 @@ func New(notify []string, noverify bool) *Lyra2 {
     return lyra2
 }
 
+// NewTester creates a lyra2 engine for testing purposes.
 func NewTester(notify []string, noverify bool) *Lyra2 {
     lyra2 := &Lyra2{
         fakeMode: false,
@@ -61,52 +65,44 @@ func NewTester(notify []string, noverify bool) *Lyra2 {
     return lyra2
 }
 
+// calcHash computes the Lyra2 hash of the header bytes, with the nonce
+// written into their last 8 bytes. The C implementation is used if cgo is
+// available, and a bit-identical pure Go implementation otherwise.
 func (lyra2 *Lyra2) calcHash(headerBytes []byte, nonce uint64, tcost int) *big.Int {
-    var lyra2_ctx unsafe.Pointer = C.LYRA2_create()
-    result := lyra2.compute(lyra2_ctx, headerBytes, nonce, tcost)
-    C.LYRA2_destroy(lyra2_ctx)
-
-    return result.Big()
-}
+    binary.BigEndian.PutUint64(headerBytes[len(headerBytes)-8:], nonce)
 
-func bytesToHash(in unsafe.Pointer) common.Hash {
-    return *(*common.Hash)(in)
-}
-
-func (lyra2 *Lyra2) compute(ctx unsafe.Pointer, blockBytes []byte, nonce uint64, tcost int) common.Hash {
-    binary.BigEndian.PutUint64(blockBytes[len(blockBytes)-8:], nonce)
-
-    var in unsafe.Pointer = C.CBytes(blockBytes)
-    var out unsafe.Pointer = C.malloc(common.HashLength)
-
-    C.LYRA2(ctx, out, common.HashLength, in, C.int32_t(len(blockBytes)), C.int32_t(tcost))
-
-    hash := bytesToHash(out)
-
-    C.free(in)
-    C.free(out)
-
-    return hash
+    return lyra2Hash(headerBytes, tcost).Big()
 }
 
+// Close closes the exit channel to notify all backend threads exiting.
 func (lyra2 *Lyra2) Close() error {
+    lyra2.closeOnce.Do(func() {
+        // Short circuit if the exit channel is not allocated.
+        if lyra2.remote == nil {
+            return
+        }
+        close(lyra2.remote.requestExit)
+        <-lyra2.remote.exitCh
+    })
     return nil
 }
 
-// APIs implements consensus.Engine, returning the user facing RPC APIs.
-func (lyra2 *Lyra2) APIs(chain consensus.ChainHeaderReader) []rpc.API {
-    return []rpc.API{
-        {
-            Namespace: "eth",
-            Version:   "1.0",
-            Service:   &API{lyra2},
-            Public:    true,
-        },
+// Hashrate implements PoW, returning the measured rate of the search invocations
+// per second over the last minute, including the hash rate submitted by remote sealers.
+func (lyra2 *Lyra2) Hashrate() float64 {
+    if lyra2.remote == nil {
+        return lyra2.hashrate.Rate1()
     }
-}
+    var res = make(chan uint64, 1)
 
-func (lyra2 *Lyra2) Hashrate() float64 {
-    return lyra2.hashrate.Rate1()
+    select {
+    case lyra2.remote.fetchRateCh <- res:
+    case <-lyra2.remote.exitCh:
+        // Return local hashrate only if lyra2 is stopped.
+        return lyra2.hashrate.Rate1()
+    }
+    // Gather total submitted hash rate of remote sealers.
+    return lyra2.hashrate.Rate1() + float64(<-res)
 }
 
 // Threads returns the number of mining threads currently enabled. This doesn't
@@ -0,0 +1,47 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build cgo
+
+package lyra2
+
+/*
+#cgo CFLAGS: -std=gnu99
+#include "Lyra2.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"unsafe"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// lyra2Hash computes the 32 byte Lyra2 hash of the input with the given time cost,
+// using the C implementation.
+func lyra2Hash(in []byte, tcost int) common.Hash {
+	var ctx unsafe.Pointer = C.LYRA2_create()
+	defer C.LYRA2_destroy(ctx)
+
+	var cin unsafe.Pointer = C.CBytes(in)
+	defer C.free(cin)
+	var cout unsafe.Pointer = C.malloc(common.HashLength)
+	defer C.free(cout)
+
+	C.LYRA2(ctx, cout, common.HashLength, cin, C.int32_t(len(in)), C.int32_t(tcost))
+
+	return *(*common.Hash)(cout)
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build cgo
+
+package lyra2
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that the pure Go implementation produces the same hashes as the C
+// implementation, over the headers used by TestStaleSubmission and random ones.
+func TestLyra2Differential(t *testing.T) {
+	headers := []*types.Header{
+		{ParentHash: common.BytesToHash([]byte{0xa}), Number: big.NewInt(1), Difficulty: big.NewInt(100000000)},
+		{ParentHash: common.BytesToHash([]byte{0xb}), Number: big.NewInt(2), Difficulty: big.NewInt(100000000)},
+		{ParentHash: common.BytesToHash([]byte{0xb}), Number: big.NewInt(2), Difficulty: big.NewInt(100000001)},
+		{ParentHash: common.BytesToHash([]byte{0xc}), Number: big.NewInt(3), Difficulty: big.NewInt(100000000)},
+		{ParentHash: common.BytesToHash([]byte{0xd}), Number: big.NewInt(9), Difficulty: big.NewInt(100000000)},
+		{ParentHash: common.BytesToHash([]byte{0xe}), Number: big.NewInt(10), Difficulty: big.NewInt(100000000)},
+		{ParentHash: common.BytesToHash([]byte{0xf}), Number: big.NewInt(17), Difficulty: big.NewInt(100000000)},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 32; i++ {
+		header := &types.Header{
+			Number:     new(big.Int).SetUint64(rng.Uint64()),
+			Difficulty: new(big.Int).SetUint64(rng.Uint64()),
+			GasLimit:   rng.Uint64(),
+			GasUsed:    rng.Uint64(),
+			Time:       rng.Uint64(),
+			Extra:      make([]byte, rng.Intn(64)),
+			Nonce:      types.EncodeNonce(rng.Uint64()),
+		}
+		rng.Read(header.ParentHash[:])
+		rng.Read(header.Coinbase[:])
+		rng.Read(header.Root[:])
+		rng.Read(header.Bloom[:rng.Intn(types.BloomByteLength)])
+		rng.Read(header.Extra)
+		if i%2 == 0 {
+			header.BaseFee = new(big.Int).SetUint64(rng.Uint64())
+		}
+		headers = append(headers, header)
+	}
+	lyra2 := NewTester(nil, true)
+	defer lyra2.Close()
+
+	for i, header := range headers {
+		headerBytes, err := lyra2.headerBytes(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		binary.BigEndian.PutUint64(headerBytes[len(headerBytes)-8:], rng.Uint64())
+		for tcost := 1; tcost <= 2; tcost++ {
+			if have, want := lyra2HashGo(headerBytes, tcost), lyra2Hash(headerBytes, tcost); have != want {
+				t.Fatalf("header %d, tcost %d: hash mismatch: have %x, want %x", i, tcost, have, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build !cgo
+
+package lyra2
+
+import "github.com/ethereum/go-ethereum/common"
+
+// lyra2Hash computes the 32 byte Lyra2 hash of the input with the given time cost,
+// using the pure Go implementation as cgo is unavailable.
+func lyra2Hash(in []byte, tcost int) common.Hash {
+	return lyra2HashGo(in, tcost)
+}
@@ -0,0 +1,278 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package lyra2
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// This file is a pure Go port of Lyra2.c and Sponge.c, producing output
+// identical to the C implementation for the same parameters.
+
+const (
+	blockLenInt64           = 12                // Sponge bitrate: 768 bits (=96 bytes, =12 uint64)
+	blockLenBytes           = blockLenInt64 * 8 // Sponge bitrate, in bytes
+	blockLenBlake2SafeInt64 = 8                 // Input block length not overwriting Blake2's IV: 512 bits
+	blockLenBlake2SafeBytes = blockLenBlake2SafeInt64 * 8
+	lyra2Rows               = 16384 // Number of rows of the memory matrix (R)
+	lyra2Cols               = 4     // Number of columns of the memory matrix (C)
+	rowLenInt64             = blockLenInt64 * lyra2Cols
+	lyra2MatrixLenInt64     = rowLenInt64 * lyra2Rows
+)
+
+// blake2bIV is the Blake2b initialization vector.
+var blake2bIV = [8]uint64{
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b,
+	0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f,
+	0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+// matrixPool recycles the memory matrices (6MB each) across hash computations.
+var matrixPool = sync.Pool{
+	New: func() interface{} {
+		matrix := make([]uint64, lyra2MatrixLenInt64)
+		return &matrix
+	},
+}
+
+// lyra2HashGo computes the 32 byte Lyra2 hash of the input with the given time cost.
+func lyra2HashGo(in []byte, tcost int) common.Hash {
+	var hash common.Hash
+	lyra2Go(hash[:], in, tcost)
+	return hash
+}
+
+// lyra2Go executes Lyra2 based on the G function from Blake2b, filling the
+// key with the derived key of the password. The salt is empty.
+func lyra2Go(key []byte, pwd []byte, tcost int) {
+	ptr := matrixPool.Get().(*[]uint64)
+	defer matrixPool.Put(ptr)
+
+	matrix := *ptr
+	for i := range matrix {
+		matrix[i] = 0
+	}
+	row := func(i int64) []uint64 {
+		return matrix[i*rowLenInt64 : (i+1)*rowLenInt64]
+	}
+	// Place the password and the basil, padded with 10*1, at the start of
+	// the memory matrix.
+	nBlocksInput := (len(pwd)+6*8)/blockLenBlake2SafeBytes + 1
+
+	input := make([]byte, nBlocksInput*blockLenBlake2SafeBytes)
+	copy(input, pwd)
+	basil := input[len(pwd):]
+	binary.LittleEndian.PutUint64(basil[0:], uint64(len(key)))
+	binary.LittleEndian.PutUint64(basil[8:], uint64(len(pwd)))
+	binary.LittleEndian.PutUint64(basil[16:], 0) // salt length
+	binary.LittleEndian.PutUint64(basil[24:], uint64(tcost))
+	binary.LittleEndian.PutUint64(basil[32:], lyra2Rows)
+	binary.LittleEndian.PutUint64(basil[40:], lyra2Cols)
+	basil[48] = 0x80
+	input[len(input)-1] ^= 0x01
+
+	for i := 0; i < len(input)/8; i++ {
+		matrix[i] = binary.LittleEndian.Uint64(input[i*8:])
+	}
+	// Initialize the sponge state: 512 bits of zeros followed by Blake2b's IV
+	var state [16]uint64
+	copy(state[8:], blake2bIV[:])
+
+	// Setup phase: absorb the padded password and basil, then fill the matrix
+	for i := 0; i < nBlocksInput; i++ {
+		absorbBlockBlake2Safe(&state, matrix[i*blockLenBlake2SafeInt64:])
+	}
+	reducedSqueezeRow0(&state, row(0))
+	reducedDuplexRow1(&state, row(0), row(1))
+
+	var (
+		rowa   int64 = 0 // row*, picked deterministically during setup and pseudorandomly while wandering
+		prev   int64 = 1 // last row ever computed
+		next   int64 = 2 // row to be computed
+		step   int64 = 1 // visitation step
+		window int64 = 2 // visitation window during setup
+		gap    int64 = 1 // modifier to the step, 1 or -1
+	)
+	for next < lyra2Rows {
+		reducedDuplexRowSetup(&state, row(prev), row(rowa), row(next))
+
+		rowa = (rowa + step) & (window - 1)
+		prev = next
+		next++
+
+		// Once all rows in the window were visited, double the window
+		if rowa == 0 {
+			step = window + gap
+			window *= 2
+			gap = -gap
+		}
+	}
+	// Wandering phase: revisit rows pseudorandomly
+	next = 0
+	for tau := int64(1); tau <= int64(tcost); tau++ {
+		step = lyra2Rows/2 - 1
+		if tau%2 == 0 {
+			step = -1
+		}
+		for {
+			rowa = int64(state[0] & (lyra2Rows - 1))
+			reducedDuplexRow(&state, row(prev), row(rowa), row(next))
+
+			prev = next
+			next = (next + step) & (lyra2Rows - 1)
+			if next == 0 {
+				break
+			}
+		}
+	}
+	// Wrap-up phase: absorb the last visited row* and squeeze the key
+	absorbBlock(&state, row(rowa))
+	squeeze(&state, key)
+}
+
+// g is Blake2b's G function.
+func g(v *[16]uint64, a, b, c, d int) {
+	v[a] += v[b]
+	v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+	v[c] += v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+	v[a] += v[b]
+	v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+	v[c] += v[d]
+	v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+}
+
+// roundLyra is one round of Blake2b's compression function.
+func roundLyra(v *[16]uint64) {
+	g(v, 0, 4, 8, 12)
+	g(v, 1, 5, 9, 13)
+	g(v, 2, 6, 10, 14)
+	g(v, 3, 7, 11, 15)
+	g(v, 0, 5, 10, 15)
+	g(v, 1, 6, 11, 12)
+	g(v, 2, 7, 8, 13)
+	g(v, 3, 4, 9, 14)
+}
+
+// blake2bLyra executes Blake2b's G function with all 12 rounds.
+func blake2bLyra(v *[16]uint64) {
+	for i := 0; i < 12; i++ {
+		roundLyra(v)
+	}
+}
+
+// squeeze fills out with data squeezed from the sponge.
+func squeeze(state *[16]uint64, out []byte) {
+	var block [blockLenBytes]byte
+	for len(out) > 0 {
+		for i := 0; i < blockLenInt64; i++ {
+			binary.LittleEndian.PutUint64(block[i*8:], state[i])
+		}
+		n := copy(out, block[:])
+		out = out[n:]
+		if n == blockLenBytes {
+			blake2bLyra(state)
+		}
+	}
+}
+
+// absorbBlock absorbs a single block of blockLenInt64 words.
+func absorbBlock(state *[16]uint64, in []uint64) {
+	for i := 0; i < blockLenInt64; i++ {
+		state[i] ^= in[i]
+	}
+	blake2bLyra(state)
+}
+
+// absorbBlockBlake2Safe absorbs a single block of blockLenBlake2SafeInt64 words.
+func absorbBlockBlake2Safe(state *[16]uint64, in []uint64) {
+	for i := 0; i < blockLenBlake2SafeInt64; i++ {
+		state[i] ^= in[i]
+	}
+	blake2bLyra(state)
+}
+
+// reducedSqueezeRow0 squeezes a single row, from the highest to the lowest column,
+// using the reduced-round permutation.
+func reducedSqueezeRow0(state *[16]uint64, rowOut []uint64) {
+	for col := lyra2Cols - 1; col >= 0; col-- {
+		copy(rowOut[col*blockLenInt64:(col+1)*blockLenInt64], state[:blockLenInt64])
+		roundLyra(state)
+	}
+}
+
+// reducedDuplexRow1 duplexes a single row: M[rowOut][C-1-col] = M[rowIn][col] XOR rand.
+func reducedDuplexRow1(state *[16]uint64, rowIn, rowOut []uint64) {
+	for col := 0; col < lyra2Cols; col++ {
+		in := rowIn[col*blockLenInt64:]
+		out := rowOut[(lyra2Cols-1-col)*blockLenInt64:]
+		for i := 0; i < blockLenInt64; i++ {
+			state[i] ^= in[i]
+		}
+		roundLyra(state)
+		for i := 0; i < blockLenInt64; i++ {
+			out[i] = in[i] ^ state[i]
+		}
+	}
+}
+
+// reducedDuplexRowSetup duplexes M[rowInOut][col] [+] M[rowIn][col], making
+// M[rowOut][C-1-col] = M[rowIn][col] XOR rand and M[rowInOut][col] ^= rotW(rand).
+// The rows may alias, so the order of updates follows Sponge.c exactly.
+func reducedDuplexRowSetup(state *[16]uint64, rowIn, rowInOut, rowOut []uint64) {
+	for col := 0; col < lyra2Cols; col++ {
+		in := rowIn[col*blockLenInt64:]
+		inOut := rowInOut[col*blockLenInt64:]
+		out := rowOut[(lyra2Cols-1-col)*blockLenInt64:]
+		for i := 0; i < blockLenInt64; i++ {
+			state[i] ^= in[i] + inOut[i]
+		}
+		roundLyra(state)
+		for i := 0; i < blockLenInt64; i++ {
+			out[i] = in[i] ^ state[i]
+		}
+		for i := 0; i < blockLenInt64; i++ {
+			inOut[i] ^= state[(i+blockLenInt64-1)%blockLenInt64]
+		}
+	}
+}
+
+// reducedDuplexRow duplexes M[rowInOut][col] [+] M[rowIn][col], making
+// M[rowOut][col] ^= rand and M[rowInOut][col] ^= rotW(rand).
+// The rows may alias, so the order of updates follows Sponge.c exactly.
+func reducedDuplexRow(state *[16]uint64, rowIn, rowInOut, rowOut []uint64) {
+	for col := 0; col < lyra2Cols; col++ {
+		in := rowIn[col*blockLenInt64:]
+		inOut := rowInOut[col*blockLenInt64:]
+		out := rowOut[col*blockLenInt64:]
+		for i := 0; i < blockLenInt64; i++ {
+			state[i] ^= in[i] + inOut[i]
+		}
+		roundLyra(state)
+		for i := 0; i < blockLenInt64; i++ {
+			out[i] ^= state[i]
+		}
+		for i := 0; i < blockLenInt64; i++ {
+			inOut[i] ^= state[(i+blockLenInt64-1)%blockLenInt64]
+		}
+	}
+}
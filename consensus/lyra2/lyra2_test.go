@@ -0,0 +1,48 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package lyra2
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that the Lyra2 hash, with or without cgo, matches known answers
+// generated by the C implementation.
+func TestLyra2Hash(t *testing.T) {
+	tests := []struct {
+		input []byte
+		tcost int
+		want  common.Hash
+	}{
+		{nil, 0, common.HexToHash("0x61c6c2dfb9eee0f49f8da84aeac5bdc3ec1134c284d32b0fc6d50ed6366a9bc2")},
+		{nil, 1, common.HexToHash("0xc575dba05b662c7e994bc566d3e64518534b9320203d7ff4da3bf188e39d1929")},
+		{[]byte("abc"), 1, common.HexToHash("0xe50397a5c02af1eb48eddfc093f5edc9b30f99d5bf0226193765c2204f66badf")},
+		{[]byte("abc"), 2, common.HexToHash("0x46b0dcf2283d76da5168a2dfae643ff4f7871c858b9eaf8724037843c8c7e98a")},
+		{[]byte("The quick brown fox jumps over the lazy dog"), 1, common.HexToHash("0xcad84b73d8854fcb635960e3b9a0f816b8971932c4c2f61eda173ec256584167")},
+		{make([]byte, 600), 1, common.HexToHash("0x1c1e95be737cdb95cb47db04d5c1e9a047f5802fa49c20affae49610c94f9ba9")},
+	}
+	for i, tt := range tests {
+		if have := lyra2HashGo(tt.input, tt.tcost); have != tt.want {
+			t.Errorf("test %d: pure Go hash mismatch: have %x, want %x", i, have, tt.want)
+		}
+		if have := lyra2Hash(tt.input, tt.tcost); have != tt.want {
+			t.Errorf("test %d: hash mismatch: have %x, want %x", i, have, tt.want)
+		}
+	}
+}
@@ -110,7 +110,7 @@ func (lyra2 *Lyra2) Seal(chain consensus.ChainHeaderReader, block *types.Block,
 }
 
 func (lyra2 *Lyra2) headerBytes(header *types.Header) ([]byte, error) {
-	ret, err := rlp.EncodeToBytes([]interface{}{
+	enc := []interface{}{
 		header.ParentHash,
 		header.UncleHash,
 		header.Coinbase,
@@ -125,8 +125,11 @@ func (lyra2 *Lyra2) headerBytes(header *types.Header) ([]byte, error) {
 		header.Time,
 		header.Extra,
 		header.Nonce,
-	})
-
+	}
+	if header.BaseFee != nil {
+		enc = append(enc, header.BaseFee)
+	}
+	ret, err := rlp.EncodeToBytes(enc)
 	if err != nil {
 		return nil, err
 	}
@@ -365,7 +368,15 @@ func (s *remoteSealer) makeWork(block *types.Block) {
 // new work to be processed.
 func (s *remoteSealer) notifyWork() {
 	work := s.currentWork
-	blob, _ := json.Marshal(work)
+
+	// Encode the JSON payload of the notification. When NotifyFull is set,
+	// this is the complete block header, otherwise it is a JSON array.
+	var blob []byte
+	if s.lyra2.config.NotifyFull {
+		blob, _ = json.Marshal(s.currentBlock.Header())
+	} else {
+		blob, _ = json.Marshal(work)
+	}
 	s.reqWG.Add(len(s.notifyURLs))
 	for _, url := range s.notifyURLs {
 		go s.sendNotification(s.notifyCtx, url, blob, work)
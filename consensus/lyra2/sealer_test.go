@@ -1,14 +1,195 @@
 package lyra2
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
+// Tests whether remote HTTP servers are correctly notified of new work.
+func TestRemoteNotify(t *testing.T) {
+	// Start a simple web server to capture notifications.
+	sink := make(chan [4]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		blob, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("failed to read miner notification: %v", err)
+		}
+		var work [4]string
+		if err := json.Unmarshal(blob, &work); err != nil {
+			t.Errorf("failed to unmarshal miner notification: %v", err)
+		}
+		sink <- work
+	}))
+	defer server.Close()
+
+	// Create the custom lyra2 engine, without local mining threads.
+	lyra2 := New(Config{}, []string{server.URL}, false)
+	defer lyra2.Close()
+	lyra2.SetThreads(-1)
+
+	// Stream a work task and ensure the notification bubbles out.
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+	block := types.NewBlockWithHeader(header)
+
+	lyra2.Seal(nil, block, nil, nil)
+	select {
+	case work := <-sink:
+		if want := lyra2.SealHash(header).Hex(); work[0] != want {
+			t.Errorf("work packet hash mismatch: have %s, want %s", work[0], want)
+		}
+		headerBytes, _ := lyra2.headerBytes(header)
+		if want := hex.EncodeToString(headerBytes); work[1] != want {
+			t.Errorf("work packet header mismatch: have %s, want %s", work[1], want)
+		}
+		target := new(big.Int).Div(new(big.Int).Lsh(big.NewInt(1), 256), header.Difficulty)
+		if want := common.BytesToHash(target.Bytes()).Hex(); work[2] != want {
+			t.Errorf("work packet target mismatch: have %s, want %s", work[2], want)
+		}
+		if want := hexutil.EncodeBig(header.Number); work[3] != want {
+			t.Errorf("work packet number mismatch: have %s, want %s", work[3], want)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("notification timed out")
+	}
+}
+
+// Tests whether remote HTTP servers are correctly notified of new work. (Full pending block body / --miner.notify.full)
+func TestRemoteNotifyFull(t *testing.T) {
+	// Start a simple web server to capture notifications.
+	sink := make(chan map[string]interface{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		blob, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("failed to read miner notification: %v", err)
+		}
+		var work map[string]interface{}
+		if err := json.Unmarshal(blob, &work); err != nil {
+			t.Errorf("failed to unmarshal miner notification: %v", err)
+		}
+		sink <- work
+	}))
+	defer server.Close()
+
+	// Create the custom lyra2 engine, without local mining threads.
+	lyra2 := New(Config{NotifyFull: true}, []string{server.URL}, false)
+	defer lyra2.Close()
+	lyra2.SetThreads(-1)
+
+	// Stream a work task and ensure the notification bubbles out.
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+	block := types.NewBlockWithHeader(header)
+
+	lyra2.Seal(nil, block, nil, nil)
+	select {
+	case work := <-sink:
+		if want := "0x" + strconv.FormatUint(header.Number.Uint64(), 16); work["number"] != want {
+			t.Errorf("pending block number mismatch: have %v, want %v", work["number"], want)
+		}
+		if want := "0x" + header.Difficulty.Text(16); work["difficulty"] != want {
+			t.Errorf("pending block difficulty mismatch: have %s, want %s", work["difficulty"], want)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("notification timed out")
+	}
+}
+
+// Tests that remote work can be fetched and solved through the API.
+func TestRemoteSealer(t *testing.T) {
+	lyra2 := NewTester(nil, false)
+	defer lyra2.Close()
+	lyra2.SetThreads(-1)
+
+	api := &API{lyra2}
+	if _, err := api.GetWork(); err != errNoMiningWork {
+		t.Error("expect to return an error indicate there is no mining work")
+	}
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(2)}
+	block := types.NewBlockWithHeader(header)
+	sealhash := lyra2.SealHash(header)
+
+	results := make(chan *types.Block, 1)
+	lyra2.Seal(nil, block, results, nil)
+
+	work, err := api.GetWork()
+	if err != nil || work[0] != sealhash.Hex() {
+		t.Fatalf("expect to return a mining work has same hash: have %v (%v), want %s", work[0], err, sealhash.Hex())
+	}
+	// Search for a valid nonce like an external miner would, from the work package.
+	target := new(big.Int).Div(two256, header.Difficulty)
+	headerBytes, _ := hex.DecodeString(work[1])
+	for nonce := uint64(0); ; nonce++ {
+		if lyra2.calcHash(headerBytes, nonce, 1).Cmp(target) <= 0 {
+			if !api.SubmitWork(types.EncodeNonce(nonce), sealhash, common.Hash{}) {
+				t.Fatalf("expect valid solution with nonce %d to be accepted", nonce)
+			}
+			break
+		}
+	}
+	select {
+	case res := <-results:
+		if err := lyra2.verifySeal(nil, res.Header(), false); err != nil {
+			t.Errorf("sealed block failed verification: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("sealing result timed out")
+	}
+	// Invalid solutions are rejected.
+	if api.SubmitWork(types.BlockNonce{}, common.Hash{0x01}, common.Hash{}) {
+		t.Error("expect to return false when submit a fake solution")
+	}
+}
+
+func TestHashrate(t *testing.T) {
+	var (
+		hashrate = []hexutil.Uint64{100, 200, 300}
+		expect   uint64
+		ids      = []common.Hash{common.HexToHash("a"), common.HexToHash("b"), common.HexToHash("c")}
+	)
+	lyra2 := NewTester(nil, false)
+	defer lyra2.Close()
+
+	if tot := lyra2.Hashrate(); tot != 0 {
+		t.Error("expect the result should be zero")
+	}
+
+	api := &API{lyra2}
+	for i := 0; i < len(hashrate); i += 1 {
+		if res := api.SubmitHashrate(hashrate[i], ids[i]); !res {
+			t.Error("remote miner submit hashrate failed")
+		}
+		expect += uint64(hashrate[i])
+	}
+	if tot := lyra2.Hashrate(); tot != float64(expect) {
+		t.Error("expect total hashrate should be same")
+	}
+}
+
+func TestClosedRemoteSealer(t *testing.T) {
+	lyra2 := NewTester(nil, false)
+	time.Sleep(1 * time.Second) // ensure exit channel is listening
+	lyra2.Close()
+
+	api := &API{lyra2}
+	if _, err := api.GetWork(); err != errLyra2Stopped {
+		t.Error("expect to return an error to indicate lyra2 is stopped")
+	}
+
+	if res := api.SubmitHashrate(hexutil.Uint64(100), common.HexToHash("a")); res {
+		t.Error("expect to return false when submit hashrate to a stopped lyra2")
+	}
+}
+
 // Tests whether stale solutions are correctly processed.
 func TestStaleSubmission(t *testing.T) {
 	lyra2 := NewTester(nil, true)
@@ -180,8 +180,10 @@ type BlockChain struct {
 	chainHeadFeed event.Feed
 	logsFeed      event.Feed
 	blockProcFeed event.Feed
-	scope         event.SubscriptionScope
-	genesisBlock  *types.Block
+
+	ecbp1100RejectFeed event.Feed
+	scope              event.SubscriptionScope
+	genesisBlock       *types.Block
 
 	chainmu sync.RWMutex // blockchain insertion lock
 
@@ -210,8 +212,9 @@ type BlockChain struct {
 	shouldPreserve  func(*types.Block) bool        // Function used to determine whether should preserve the given block.
 	terminateInsert func(common.Hash, uint64) bool // Testing hook used to terminate ancient receipt chain insertion.
 
-	artificialFinalityNoDisable     *int32 // manual override prevents disabling artificial finality feature activation
-	artificialFinalityEnabledStatus int32  // toggles artificial finality features; will be always 1 if artificialFinalityForce=1
+	artificialFinalityNoDisable     *int32       // manual override prevents disabling artificial finality feature activation
+	artificialFinalityEnabledStatus int32        // toggles artificial finality features; will be always 1 if artificialFinalityForce=1
+	finalityPolicy                  atomic.Value // Artificial finality policy consulted on reorganizations (*FinalityPolicy)
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -253,7 +256,12 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig ctyp
 	bc.prefetcher = newStatePrefetcher(chainConfig, bc, engine)
 	bc.processor = NewStateProcessor(chainConfig, bc, engine)
 
-	var err error
+	policy, err := NewFinalityPolicy(chainConfig.GetECBP1100Policy())
+	if err != nil {
+		return nil, err
+	}
+	bc.finalityPolicy.Store(&policy)
+
 	bc.hc, err = NewHeaderChain(db, chainConfig, engine, bc.insertStopped)
 	if err != nil {
 		return nil, err
@@ -1575,15 +1583,16 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 				if bc.IsArtificialFinalityEnabled() &&
 					bc.chainConfig.IsEnabled(bc.chainConfig.GetECBP1100Transition, currentBlock.Number()) {
 
-					if err := bc.ecbp1100(d.commonBlock.Header(), currentBlock.Header(), block.Header()); err != nil {
+					if err := bc.artificialFinality(d.commonBlock.Header(), currentBlock.Header(), block.Header()); err != nil {
 
 						canonicalDisallowed = true
 						log.Warn("Reorg disallowed", "error", err)
 
 					} else if len(d.oldChain) > 2 {
 
-						// Reorg is allowed, only log the MESS line if old chain is longer than normal.
-						log.Info("ECBP1100-MESS 🔓",
+						// Reorg is allowed, only log the AF line if old chain is longer than normal.
+						log.Info("Artificial finality 🔓",
+							"policy", bc.FinalityPolicy().Name(),
 							"status", "accepted",
 							"age", common.PrettyAge(time.Unix(int64(d.commonBlock.Time()), 0)),
 							"current.span", common.PrettyDuration(time.Duration(currentBlock.Time()-d.commonBlock.Time())*time.Second),
@@ -1794,7 +1803,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 						if bc.IsArtificialFinalityEnabled() &&
 							bc.chainConfig.IsEnabled(bc.chainConfig.GetECBP1100Transition, current.Number()) {
 
-							if err := bc.ecbp1100(reorgData.commonBlock.Header(), current.Header(), block.Header()); err != nil {
+							if err := bc.artificialFinality(reorgData.commonBlock.Header(), current.Header(), block.Header()); err != nil {
 
 								canonicalDisallowed = true
 								log.Trace("Reorg disallowed", "error", err)
@@ -2648,6 +2657,12 @@ func (bc *BlockChain) GetHeaderByNumber(number uint64) *types.Header {
 	return bc.hc.GetHeaderByNumber(number)
 }
 
+// GetHeaderRangeRLP retrieves the RLP encodings of up to count consecutive
+// canonical headers in ascending order, starting at the given number.
+func (bc *BlockChain) GetHeaderRangeRLP(number, count uint64) []rlp.RawValue {
+	return bc.hc.GetHeaderRangeRLP(number, count)
+}
+
 // GetTransactionLookup retrieves the lookup associate with the given transaction
 // hash from the cache or database.
 func (bc *BlockChain) GetTransactionLookup(hash common.Hash) *rawdb.LegacyTxLookupEntry {
@@ -2695,6 +2710,11 @@ func (bc *BlockChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscript
 	return bc.scope.Track(bc.logsFeed.Subscribe(ch))
 }
 
+// SubscribeECBP1100RejectionEvent registers a subscription of ECBP1100RejectionEvent.
+func (bc *BlockChain) SubscribeECBP1100RejectionEvent(ch chan<- ECBP1100RejectionEvent) event.Subscription {
+	return bc.scope.Track(bc.ecbp1100RejectFeed.Subscribe(ch))
+}
+
 // SubscribeBlockProcessingEvent registers a subscription of bool where true means
 // block processing has started while false means it has stopped.
 func (bc *BlockChain) SubscribeBlockProcessingEvent(ch chan<- bool) event.Subscription {
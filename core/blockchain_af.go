@@ -16,6 +16,10 @@ import (
 // errReorgFinality represents an error caused by artificial finality mechanisms.
 var errReorgFinality = errors.New("finality-enforced invalid new chain")
 
+// errNoLocalSubchainTD is returned when the current head adds no total difficulty
+// over the common ancestor, leaving the total difficulty ratio undefined.
+var errNoLocalSubchainTD = errors.New("current head has no total difficulty over common ancestor")
+
 // ArtificialFinalityNoDisable overrides toggling of AF features, forcing it on.
 // n  = 1 : ON
 // n != 1 : OFF
@@ -70,7 +74,7 @@ func (bc *BlockChain) IsArtificialFinalityEnabled() bool {
 
 // getTDRatio is a helper function returning the total difficulty ratio of
 // proposed over current chain segments.
-func (bc *BlockChain) getTDRatio(commonAncestor, current, proposed *types.Header) float64 {
+func (bc *BlockChain) getTDRatio(commonAncestor, current, proposed *types.Header) (float64, error) {
 	// Get the total difficulty ratio of the proposed chain segment over the existing one.
 	commonAncestorTD := bc.GetTd(commonAncestor.Hash(), commonAncestor.Number.Uint64())
 
@@ -78,52 +82,186 @@ func (bc *BlockChain) getTDRatio(commonAncestor, current, proposed *types.Header
 	proposedTD := new(big.Int).Add(proposed.Difficulty, proposedParentTD)
 
 	localTD := bc.GetTd(current.Hash(), current.Number.Uint64())
+	localSubchainTD := new(big.Int).Sub(localTD, commonAncestorTD)
+	if localSubchainTD.Sign() <= 0 {
+		return 0, errNoLocalSubchainTD
+	}
 
 	tdRatio, _ := new(big.Float).Quo(
 		new(big.Float).SetInt(new(big.Int).Sub(proposedTD, commonAncestorTD)),
-		new(big.Float).SetInt(new(big.Int).Sub(localTD, commonAncestorTD)),
+		new(big.Float).SetInt(localSubchainTD),
 	).Float64()
-	return tdRatio
+	return tdRatio, nil
 }
 
-// ecbp1100 implements the "MESS" artificial finality mechanism
-// "Modified Exponential Subjective Scoring" used to prefer known chain segments
-// over later-to-come counterparts, especially proposed segments stretching far into the past.
-func (bc *BlockChain) ecbp1100(commonAncestor, current, proposed *types.Header) error {
+// ECBP1100Evaluation holds the values computed by an evaluation of the ECBP1100 (MESS)
+// artificial finality condition for a proposed chain segment.
+type ECBP1100Evaluation struct {
+	CommonAncestor *types.Header
+	Current        *types.Header
+	Proposed       *types.Header
+
+	LocalSubchainTD    *big.Int // Total difficulty of the current chain segment since the common ancestor
+	ProposedSubchainTD *big.Int // Total difficulty of the proposed chain segment since the common ancestor
+
+	// TDRatio is the ratio of the proposed over the current subchain total difficulties.
+	TDRatio float64
+	// PolynomialV is the antigravity numerator yielded by ecbp1100PolynomialV
+	// for the current segment's timespan, over the curve function denominator (128).
+	PolynomialV *big.Int
+	// AntiGravity is the minimum TD ratio required for the proposed segment to be accepted.
+	AntiGravity float64
+
+	Accepted bool
+}
+
+// GravityRatio returns the TD ratio over the antigravity value, where values
+// below 1 yield rejection.
+func (e *ECBP1100Evaluation) GravityRatio() float64 {
+	return e.TDRatio / e.AntiGravity
+}
+
+// Err returns an error describing the rejection of the proposed segment,
+// or nil if it was accepted.
+func (e *ECBP1100Evaluation) Err() error {
+	if e.Accepted {
+		return nil
+	}
+	return &ecbp1100Error{e}
+}
+
+// ecbp1100Error is the error returned for ECBP1100 (MESS) rejections.
+// It wraps errReorgFinality.
+type ecbp1100Error struct {
+	eval *ECBP1100Evaluation
+}
+
+func (err *ecbp1100Error) Unwrap() error {
+	return errReorgFinality
+}
 
+func (err *ecbp1100Error) Error() string {
+	e := err.eval
+	return fmt.Sprintf(`%v: ECBP1100-MESS 🔒 status=rejected age=%v current.span=%v proposed.span=%v tdr/gravity=%0.6f common.bno=%d common.hash=%s current.bno=%d current.hash=%s proposed.bno=%d proposed.hash=%s`,
+		errReorgFinality,
+		common.PrettyAge(time.Unix(int64(e.CommonAncestor.Time), 0)),
+		common.PrettyDuration(time.Duration(e.Current.Time-e.CommonAncestor.Time)*time.Second),
+		common.PrettyDuration(time.Duration(e.Proposed.Time-e.CommonAncestor.Time)*time.Second),
+		e.GravityRatio(),
+		e.CommonAncestor.Number.Uint64(), e.CommonAncestor.Hash().Hex(),
+		e.Current.Number.Uint64(), e.Current.Hash().Hex(),
+		e.Proposed.Number.Uint64(), e.Proposed.Hash().Hex(),
+	)
+}
+
+// ECBP1100Evaluate evaluates the ECBP1100 (MESS) artificial finality condition for
+// a proposed chain segment, without regard to whether the feature is enabled or activated.
+// The common ancestor must be an ancestor of both the current head and the proposed head,
+// and the current head must be its descendant. The total difficulties of the common ancestor,
+// the current head and the proposed head's parent must be known.
+func (bc *BlockChain) ECBP1100Evaluate(commonAncestor, current, proposed *types.Header) (*ECBP1100Evaluation, error) {
+	if !bc.isAncestor(commonAncestor, current.Hash(), current.Number.Uint64()) {
+		return nil, fmt.Errorf("common ancestor %d (%x) is not an ancestor of current %d (%x)", commonAncestor.Number, commonAncestor.Hash(), current.Number, current.Hash())
+	}
+	if proposed.Number.Cmp(commonAncestor.Number) <= 0 || !bc.isAncestor(commonAncestor, proposed.ParentHash, proposed.Number.Uint64()-1) {
+		return nil, fmt.Errorf("common ancestor %d (%x) is not an ancestor of proposed %d (%x)", commonAncestor.Number, commonAncestor.Hash(), proposed.Number, proposed.Hash())
+	}
+	return ecbp1100Evaluate(bc, commonAncestor, current, proposed)
+}
+
+// isAncestor reports whether the ancestor header is the given block or one of its ancestors.
+func (bc *BlockChain) isAncestor(ancestor *types.Header, hash common.Hash, number uint64) bool {
+	if number < ancestor.Number.Uint64() {
+		return false
+	}
+	maxNonCanonical := uint64(math.MaxUint64)
+	ancestorHash, _ := bc.GetAncestor(hash, number, number-ancestor.Number.Uint64(), &maxNonCanonical)
+	return ancestorHash == ancestor.Hash()
+}
+
+func ecbp1100Evaluate(chain FinalityChainReader, commonAncestor, current, proposed *types.Header) (*ECBP1100Evaluation, error) {
 	// Get the total difficulties of the proposed chain segment and the existing one.
-	commonAncestorTD := bc.GetTd(commonAncestor.Hash(), commonAncestor.Number.Uint64())
-	proposedParentTD := bc.GetTd(proposed.ParentHash, proposed.Number.Uint64()-1)
+	commonAncestorTD := chain.GetTd(commonAncestor.Hash(), commonAncestor.Number.Uint64())
+	if commonAncestorTD == nil {
+		return nil, fmt.Errorf("unknown total difficulty for common ancestor %d (%x)", commonAncestor.Number, commonAncestor.Hash())
+	}
+	if proposed.Number.Sign() == 0 {
+		return nil, errors.New("proposed block cannot be genesis")
+	}
+	proposedParentTD := chain.GetTd(proposed.ParentHash, proposed.Number.Uint64()-1)
+	if proposedParentTD == nil {
+		return nil, fmt.Errorf("unknown total difficulty for proposed parent %d (%x)", proposed.Number.Uint64()-1, proposed.ParentHash)
+	}
+	localTD := chain.GetTd(current.Hash(), current.Number.Uint64())
+	if localTD == nil {
+		return nil, fmt.Errorf("unknown total difficulty for current %d (%x)", current.Number, current.Hash())
+	}
+	if current.Time < commonAncestor.Time || proposed.Time < commonAncestor.Time {
+		return nil, errors.New("common ancestor is newer than segment heads")
+	}
 	proposedTD := new(big.Int).Add(proposed.Difficulty, proposedParentTD)
-	localTD := bc.GetTd(current.Hash(), current.Number.Uint64())
 
 	// if proposed_subchain_td * CURVE_FUNCTION_DENOMINATOR < get_curve_function_numerator(proposed.Time - commonAncestor.Time) * local_subchain_td.
 	proposedSubchainTD := new(big.Int).Sub(proposedTD, commonAncestorTD)
 	localSubchainTD := new(big.Int).Sub(localTD, commonAncestorTD)
+	if localSubchainTD.Sign() <= 0 {
+		return nil, errNoLocalSubchainTD
+	}
 
-	xBig := big.NewInt(int64(current.Time - commonAncestor.Time))
+	xBig := new(big.Int).SetUint64(current.Time - commonAncestor.Time)
 	eq := ecbp1100PolynomialV(xBig)
+	polynomialV := new(big.Int).Set(eq)
 	want := eq.Mul(eq, localSubchainTD)
 
 	got := new(big.Int).Mul(proposedSubchainTD, ecbp1100PolynomialVCurveFunctionDenominator)
 
-	if got.Cmp(want) < 0 {
-		prettyRatio, _ := new(big.Float).Quo(
-			new(big.Float).SetInt(got),
-			new(big.Float).SetInt(want),
-		).Float64()
-		return fmt.Errorf(`%w: ECBP1100-MESS 🔒 status=rejected age=%v current.span=%v proposed.span=%v tdr/gravity=%0.6f common.bno=%d common.hash=%s current.bno=%d current.hash=%s proposed.bno=%d proposed.hash=%s`,
-			errReorgFinality,
-			common.PrettyAge(time.Unix(int64(commonAncestor.Time), 0)),
-			common.PrettyDuration(time.Duration(current.Time-commonAncestor.Time)*time.Second),
-			common.PrettyDuration(time.Duration(int32(xBig.Uint64()))*time.Second),
-			prettyRatio,
-			commonAncestor.Number.Uint64(), commonAncestor.Hash().Hex(),
-			current.Number.Uint64(), current.Hash().Hex(),
-			proposed.Number.Uint64(), proposed.Hash().Hex(),
-		)
+	tdRatio, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(proposedSubchainTD),
+		new(big.Float).SetInt(localSubchainTD),
+	).Float64()
+	antiGravity, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(polynomialV),
+		new(big.Float).SetInt(ecbp1100PolynomialVCurveFunctionDenominator),
+	).Float64()
+
+	return &ECBP1100Evaluation{
+		CommonAncestor:     commonAncestor,
+		Current:            current,
+		Proposed:           proposed,
+		LocalSubchainTD:    localSubchainTD,
+		ProposedSubchainTD: proposedSubchainTD,
+		TDRatio:            tdRatio,
+		PolynomialV:        polynomialV,
+		AntiGravity:        antiGravity,
+		Accepted:           got.Cmp(want) >= 0,
+	}, nil
+}
+
+// FinalityPolicy returns the artificial finality policy consulted on chain reorganizations.
+func (bc *BlockChain) FinalityPolicy() FinalityPolicy {
+	return *bc.finalityPolicy.Load().(*FinalityPolicy)
+}
+
+// SetFinalityPolicy overrides the artificial finality policy established by the chain configuration.
+// It does not affect whether artificial finality is enabled or activated.
+func (bc *BlockChain) SetFinalityPolicy(policy FinalityPolicy) {
+	log.Info("Set artificial finality policy", "policy", policy.Name())
+	bc.finalityPolicy.Store(&policy)
+}
+
+// artificialFinality consults the artificial finality policy for a proposed chain reorganization,
+// returning a non-nil error if it should be disallowed.
+// By default, this is the ECBP1100 "MESS" artificial finality mechanism,
+// "Modified Exponential Subjective Scoring", used to prefer known chain segments
+// over later-to-come counterparts, especially proposed segments stretching far into the past.
+// ECBP1100 rejections are posted to ECBP1100 rejection event subscribers.
+func (bc *BlockChain) artificialFinality(commonAncestor, current, proposed *types.Header) error {
+	err := bc.FinalityPolicy().Evaluate(bc, commonAncestor, current, proposed)
+	var messErr *ecbp1100Error
+	if errors.As(err, &messErr) {
+		bc.ecbp1100RejectFeed.Send(ECBP1100RejectionEvent{Evaluation: messErr.eval})
 	}
-	return nil
+	return err
 }
 
 /*
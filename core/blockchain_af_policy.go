@@ -0,0 +1,140 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+)
+
+// FinalityChainReader defines the chain access required by finality policies.
+type FinalityChainReader interface {
+	// GetTd retrieves the total difficulty of a block from the database by hash and number.
+	GetTd(hash common.Hash, number uint64) *big.Int
+}
+
+// FinalityPolicy is an artificial finality policy, consulted on chain reorganizations
+// once artificial finality is enabled and activated.
+type FinalityPolicy interface {
+	// Name returns the name of the policy, used for logging.
+	Name() string
+
+	// Evaluate returns an error wrapping errReorgFinality if the reorganization
+	// from the current head to the proposed head, which share the given common ancestor,
+	// should be disallowed.
+	Evaluate(chain FinalityChainReader, commonAncestor, current, proposed *types.Header) error
+}
+
+// NewFinalityPolicy returns the finality policy described by the given configuration.
+// A nil configuration yields the ECBP1100 (MESS) policy.
+func NewFinalityPolicy(config *ctypes.ArtificialFinalityPolicy) (FinalityPolicy, error) {
+	if config == nil {
+		return MESSFinalityPolicy{}, nil
+	}
+	switch config.Type {
+	case ctypes.ArtificialFinalityPolicyT_MESS:
+		return MESSFinalityPolicy{}, nil
+	case ctypes.ArtificialFinalityPolicyT_Checkpoint:
+		if config.Depth == 0 {
+			return nil, errors.New("checkpoint finality policy requires nonzero depth")
+		}
+		return CheckpointFinalityPolicy{Depth: config.Depth}, nil
+	case ctypes.ArtificialFinalityPolicyT_MaxAge:
+		if config.MaxAge == 0 {
+			return nil, errors.New("maxage finality policy requires nonzero max age")
+		}
+		return MaxAgeFinalityPolicy{MaxAge: config.MaxAge}, nil
+	}
+	return nil, fmt.Errorf("unknown finality policy: %q", config.Type)
+}
+
+// MESSFinalityPolicy implements the ECBP1100 "Modified Exponential Subjective Scoring"
+// policy, requiring proposed segments to exceed the current segment's total difficulty
+// by a polynomial function of the current segment's timespan.
+type MESSFinalityPolicy struct{}
+
+func (MESSFinalityPolicy) Name() string { return string(ctypes.ArtificialFinalityPolicyT_MESS) }
+
+func (MESSFinalityPolicy) Evaluate(chain FinalityChainReader, commonAncestor, current, proposed *types.Header) error {
+	// The proposed segment extends the current head, so nothing is reorganized away.
+	if current.Hash() == commonAncestor.Hash() {
+		return nil
+	}
+	eval, err := ecbp1100Evaluate(chain, commonAncestor, current, proposed)
+	if err != nil {
+		return err
+	}
+	return eval.Err()
+}
+
+// CheckpointFinalityPolicy disallows reorganizations whose common ancestor
+// is Depth or more blocks below the current head.
+type CheckpointFinalityPolicy struct {
+	Depth uint64
+}
+
+func (p CheckpointFinalityPolicy) Name() string {
+	return fmt.Sprintf("%s(depth=%d)", ctypes.ArtificialFinalityPolicyT_Checkpoint, p.Depth)
+}
+
+func (p CheckpointFinalityPolicy) Evaluate(chain FinalityChainReader, commonAncestor, current, proposed *types.Header) error {
+	depth := current.Number.Uint64() - commonAncestor.Number.Uint64()
+	if depth < p.Depth {
+		return nil
+	}
+	return fmt.Errorf(`%w: checkpoint 🔒 status=rejected depth=%d limit=%d common.bno=%d common.hash=%s current.bno=%d current.hash=%s proposed.bno=%d proposed.hash=%s`,
+		errReorgFinality,
+		depth, p.Depth,
+		commonAncestor.Number.Uint64(), commonAncestor.Hash().Hex(),
+		current.Number.Uint64(), current.Hash().Hex(),
+		proposed.Number.Uint64(), proposed.Hash().Hex(),
+	)
+}
+
+// MaxAgeFinalityPolicy disallows reorganizations replacing a current chain segment
+// spanning MaxAge seconds or more, measured between the common ancestor and the current head.
+type MaxAgeFinalityPolicy struct {
+	MaxAge uint64
+}
+
+func (p MaxAgeFinalityPolicy) Name() string {
+	return fmt.Sprintf("%s(maxAge=%ds)", ctypes.ArtificialFinalityPolicyT_MaxAge, p.MaxAge)
+}
+
+func (p MaxAgeFinalityPolicy) Evaluate(chain FinalityChainReader, commonAncestor, current, proposed *types.Header) error {
+	var span uint64
+	if current.Time > commonAncestor.Time {
+		span = current.Time - commonAncestor.Time
+	}
+	if span < p.MaxAge {
+		return nil
+	}
+	return fmt.Errorf(`%w: maxage 🔒 status=rejected current.span=%v limit=%v common.bno=%d common.hash=%s current.bno=%d current.hash=%s proposed.bno=%d proposed.hash=%s`,
+		errReorgFinality,
+		common.PrettyDuration(time.Duration(span)*time.Second),
+		common.PrettyDuration(time.Duration(p.MaxAge)*time.Second),
+		commonAncestor.Number.Uint64(), commonAncestor.Hash().Hex(),
+		current.Number.Uint64(), current.Hash().Hex(),
+		proposed.Number.Uint64(), proposed.Hash().Hex(),
+	)
+}
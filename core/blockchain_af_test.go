@@ -16,6 +16,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"gonum.org/v1/plot"
 	"gonum.org/v1/plot/plotter"
 	"gonum.org/v1/plot/vg"
@@ -242,6 +243,179 @@ func TestAFKnownBlock(t *testing.T) {
 	}
 }
 
+// TestECBP1100Evaluate tests that evaluations of hypothetical segments agree with
+// the rejections made on chain insertion, and that rejections are posted to subscribers.
+func TestECBP1100Evaluate(t *testing.T) {
+	engine := ethash.NewFaker()
+
+	db := rawdb.NewMemoryDatabase()
+	genesis := params.DefaultMessNetGenesisBlock()
+	genesisB := MustCommitGenesis(db, genesis)
+
+	chain, err := NewBlockChain(db, nil, genesis.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Stop()
+	chain.EnableArtificialFinality(true)
+
+	easy, _ := GenerateChain(genesis.Config, genesisB, engine, db, 1000, func(i int, gen *BlockGen) {
+		gen.OffsetTime(0)
+	})
+	if _, err := chain.InsertChain(easy); err != nil {
+		t.Fatal(err)
+	}
+	commonAncestor := easy[699]
+	hard, _ := GenerateChain(genesis.Config, commonAncestor, engine, db, 300, func(i int, gen *BlockGen) {
+		gen.OffsetTime(-7)
+	})
+
+	// Each proposed block may be rejected.
+	rejections := make(chan ECBP1100RejectionEvent, len(hard))
+	sub := chain.SubscribeECBP1100RejectionEvent(rejections)
+	defer sub.Unsubscribe()
+	if _, err := chain.InsertChain(hard); err != nil {
+		t.Fatal(err)
+	}
+	if chain.CurrentBlock().Hash() != easy[len(easy)-1].Hash() {
+		t.Fatal("hard block got chain head, should be side")
+	}
+
+	var ev ECBP1100RejectionEvent
+	select {
+	case ev = <-rejections:
+	default:
+		t.Fatal("missing rejection event")
+	}
+	if ev.Evaluation.Accepted {
+		t.Error("rejection event evaluation accepted")
+	}
+	if ev.Evaluation.CommonAncestor.Hash() != commonAncestor.Hash() {
+		t.Errorf("rejection common ancestor: got %x, want %x", ev.Evaluation.CommonAncestor.Hash(), commonAncestor.Hash())
+	}
+
+	// Evaluate the whole proposed segment against the current head.
+	eval, err := chain.ECBP1100Evaluate(commonAncestor.Header(), chain.CurrentHeader(), hard[len(hard)-1].Header())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eval.Accepted {
+		t.Error("evaluation accepted, want rejected")
+	}
+	if eval.TDRatio <= 1 {
+		t.Errorf("td ratio: got %v, want > 1", eval.TDRatio)
+	}
+	if eval.TDRatio >= eval.AntiGravity || eval.GravityRatio() >= 1 {
+		t.Errorf("td ratio %v above antigravity %v", eval.TDRatio, eval.AntiGravity)
+	}
+	if want := ecbp1100PolynomialV(new(big.Int).SetUint64(chain.CurrentHeader().Time - commonAncestor.Time())); eval.PolynomialV.Cmp(want) != 0 {
+		t.Errorf("polynomial: got %v, want %v", eval.PolynomialV, want)
+	}
+	if eval.Err() == nil {
+		t.Error("missing evaluation error")
+	}
+
+	// A proposed segment equivalent to the current one is accepted.
+	eval, err = chain.ECBP1100Evaluate(easy[998].Header(), chain.CurrentHeader(), easy[len(easy)-1].Header())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !eval.Accepted || eval.Err() != nil {
+		t.Errorf("evaluation rejected, want accepted: %v", eval.Err())
+	}
+
+	// Segments whose common ancestor is the current head, or isn't an ancestor
+	// of both heads, can't be evaluated.
+	if _, err := chain.ECBP1100Evaluate(chain.CurrentHeader(), chain.CurrentHeader(), easy[len(easy)-1].Header()); err == nil {
+		t.Error("evaluation from the current head succeeded")
+	}
+	if _, err := chain.ECBP1100Evaluate(easy[998].Header(), easy[998].Header(), easy[len(easy)-1].Header()); err != errNoLocalSubchainTD {
+		t.Errorf("evaluation without local subchain: got %v, want %v", err, errNoLocalSubchainTD)
+	}
+	if _, err := chain.ECBP1100Evaluate(easy[800].Header(), chain.CurrentHeader(), hard[len(hard)-1].Header()); err == nil {
+		t.Error("evaluation with a non-ancestor of the proposed head succeeded")
+	}
+	if _, err := chain.ECBP1100Evaluate(hard[0].Header(), chain.CurrentHeader(), hard[len(hard)-1].Header()); err == nil {
+		t.Error("evaluation with a non-ancestor of the current head succeeded")
+	}
+}
+
+// TestFinalityPolicies tests that the configured artificial finality policy
+// is consulted on chain reorganizations.
+func TestFinalityPolicies(t *testing.T) {
+	cases := []struct {
+		policy        *ctypes.ArtificialFinalityPolicy
+		override      FinalityPolicy
+		forkDepth     int
+		hardGetsHead  bool
+		wantRejection bool // ECBP1100 rejection event
+	}{
+		{nil, nil, 5, true, false},
+		{nil, nil, 300, false, true},
+		{&ctypes.ArtificialFinalityPolicy{Type: ctypes.ArtificialFinalityPolicyT_Checkpoint, Depth: 10}, nil, 5, true, false},
+		{&ctypes.ArtificialFinalityPolicy{Type: ctypes.ArtificialFinalityPolicyT_Checkpoint, Depth: 10}, nil, 20, false, false},
+		{&ctypes.ArtificialFinalityPolicy{Type: ctypes.ArtificialFinalityPolicyT_MaxAge, MaxAge: 100}, nil, 5, true, false},
+		{&ctypes.ArtificialFinalityPolicy{Type: ctypes.ArtificialFinalityPolicyT_MaxAge, MaxAge: 100}, nil, 20, false, false},
+		{&ctypes.ArtificialFinalityPolicy{Type: ctypes.ArtificialFinalityPolicyT_Checkpoint, Depth: 10}, MESSFinalityPolicy{}, 20, true, false},
+	}
+	for i, c := range cases {
+		engine := ethash.NewFaker()
+
+		db := rawdb.NewMemoryDatabase()
+		genesis := params.DefaultMessNetGenesisBlock()
+		config := *params.MessNetConfig
+		genesis.Config = &config
+		if err := genesis.SetECBP1100Policy(c.policy); err != nil {
+			t.Fatal(err)
+		}
+		genesisB := MustCommitGenesis(db, genesis)
+
+		chain, err := NewBlockChain(db, nil, genesis.Config, engine, vm.Config{}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chain.EnableArtificialFinality(true)
+		if c.override != nil {
+			chain.SetFinalityPolicy(c.override)
+		}
+		rejections := make(chan ECBP1100RejectionEvent, c.forkDepth)
+		sub := chain.SubscribeECBP1100RejectionEvent(rejections)
+
+		easy, _ := GenerateChain(genesis.Config, genesisB, engine, db, 400, func(i int, gen *BlockGen) {
+			gen.OffsetTime(0)
+		})
+		if _, err := chain.InsertChain(easy); err != nil {
+			t.Fatal(err)
+		}
+		hard, _ := GenerateChain(genesis.Config, easy[len(easy)-1-c.forkDepth], engine, db, c.forkDepth, func(i int, gen *BlockGen) {
+			gen.OffsetTime(-7)
+		})
+		if _, err := chain.InsertChain(hard); err != nil {
+			t.Fatal(err)
+		}
+		if got := chain.CurrentBlock().Hash() == hard[len(hard)-1].Hash(); got != c.hardGetsHead {
+			t.Errorf("case %d: policy %s: hard head: got %v, want %v", i, chain.FinalityPolicy().Name(), got, c.hardGetsHead)
+		}
+		if got := len(rejections) > 0; got != c.wantRejection {
+			t.Errorf("case %d: policy %s: rejection event: got %v, want %v", i, chain.FinalityPolicy().Name(), got, c.wantRejection)
+		}
+		sub.Unsubscribe()
+		chain.Stop()
+	}
+}
+
+func TestNewFinalityPolicy(t *testing.T) {
+	for _, c := range []*ctypes.ArtificialFinalityPolicy{
+		{Type: ctypes.ArtificialFinalityPolicyT_Checkpoint},
+		{Type: ctypes.ArtificialFinalityPolicyT_MaxAge},
+		{Type: "unknown"},
+	} {
+		if _, err := NewFinalityPolicy(c); err == nil {
+			t.Errorf("policy %v: expected error", c)
+		}
+	}
+}
+
 // TestEcbp1100PolynomialV tests the general shape and return values of the ECBP1100 polynomial curve.
 // It makes sure domain values above the 'cap' do indeed get limited, as well
 // as sanity check some normal domain values.
@@ -990,7 +1164,10 @@ func TestBlockChain_AF_Difficulty_Develop(t *testing.T) {
 
 			// y := chain.getTDRatio(commonAncestor.Header(), easy[c.easyLen-1].Header(), hardHeader)
 
-			y := chain.getTDRatio(commonAncestor.Header(), chain.CurrentHeader(), hardHeader)
+			y, err := chain.getTDRatio(commonAncestor.Header(), chain.CurrentHeader(), hardHeader)
+			if err != nil {
+				t.Fatal(err)
+			}
 
 			if j == 0 {
 				t.Logf("case=%d first.hard.tdr=%v", i, y)
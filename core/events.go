@@ -41,3 +41,7 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// ECBP1100RejectionEvent is posted when the ECBP1100 (MESS) artificial finality
+// mechanism rejects a proposed chain segment.
+type ECBP1100RejectionEvent struct{ Evaluation *ECBP1100Evaluation }
@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/rlp"
 	lru "github.com/hashicorp/golang-lru"
 )
 
@@ -534,6 +535,20 @@ func (hc *HeaderChain) GetHeaderByNumber(number uint64) *types.Header {
 	return hc.GetHeader(hash, number)
 }
 
+// GetHeaderRangeRLP retrieves the RLP encodings of up to count consecutive canonical
+// headers in ascending order, starting at the given number and ending at the
+// current head at the latest. Frozen headers are read from the ancient store in bulk.
+func (hc *HeaderChain) GetHeaderRangeRLP(number, count uint64) []rlp.RawValue {
+	head := hc.CurrentHeader().Number.Uint64()
+	if number > head {
+		return nil
+	}
+	if head-number < count {
+		count = head - number + 1
+	}
+	return rawdb.ReadHeaderRange(hc.chainDb, number, count)
+}
+
 func (hc *HeaderChain) GetCanonicalHash(number uint64) common.Hash {
 	return rawdb.ReadCanonicalHash(hc.chainDb, number)
 }
@@ -294,6 +294,46 @@ func ReadHeaderRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.RawValu
 	return nil // Can't find the data anywhere.
 }
 
+// ReadHeaderRange retrieves the RLP encodings of up to count consecutive
+// canonical headers in ascending order, starting at the given number.
+// The range ends early at the first header which is not found.
+func ReadHeaderRange(db ethdb.Reader, number, count uint64) []rlp.RawValue {
+	return readCanonicalRange(db, freezerHeaderTable, number, count, func(number uint64) rlp.RawValue {
+		hash := ReadCanonicalHash(db, number)
+		if hash == (common.Hash{}) {
+			return nil
+		}
+		return ReadHeaderRLP(db, hash, number)
+	})
+}
+
+// readCanonicalRange retrieves up to count consecutive canonical items of an
+// ancient kind, starting at the given number. The frozen part of the range is
+// read from the ancient store in bulk, the remainder item by item using read.
+func readCanonicalRange(db ethdb.Reader, kind string, number, count uint64, read func(number uint64) rlp.RawValue) []rlp.RawValue {
+	var items []rlp.RawValue
+	for count > 0 {
+		blobs, err := db.AncientRange(kind, number, count, 0)
+		if err != nil || len(blobs) == 0 {
+			break
+		}
+		for _, blob := range blobs {
+			items = append(items, blob)
+		}
+		number += uint64(len(blobs))
+		count -= uint64(len(blobs))
+	}
+	for ; count > 0; count-- {
+		data := read(number)
+		if len(data) == 0 {
+			break
+		}
+		items = append(items, data)
+		number++
+	}
+	return items
+}
+
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db ethdb.Reader, hash common.Hash, number uint64) bool {
 	if has, err := db.Ancient(freezerHashTable, number); err == nil && common.BytesToHash(has) == hash {
@@ -406,6 +446,15 @@ func ReadCanonicalBodyRLP(db ethdb.Reader, number uint64) rlp.RawValue {
 	return data
 }
 
+// ReadCanonicalBodyRange retrieves the RLP encodings of up to count consecutive
+// canonical block bodies in ascending order, starting at the given number.
+// The range ends early at the first body which is not found.
+func ReadCanonicalBodyRange(db ethdb.Reader, number, count uint64) []rlp.RawValue {
+	return readCanonicalRange(db, freezerBodiesTable, number, count, func(number uint64) rlp.RawValue {
+		return ReadCanonicalBodyRLP(db, number)
+	})
+}
+
 // WriteBodyRLP stores an RLP encoded block body into the database.
 func WriteBodyRLP(db ethdb.KeyValueWriter, hash common.Hash, number uint64, rlp rlp.RawValue) {
 	if err := db.Put(blockBodyKey(number, hash), rlp); err != nil {
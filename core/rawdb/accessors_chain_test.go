@@ -29,6 +29,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"golang.org/x/crypto/sha3"
@@ -496,6 +497,47 @@ func TestAncientStorage(t *testing.T) {
 	}
 }
 
+func TestHeaderRange(t *testing.T) {
+	frdir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("failed to create temp freezer dir: %v", err)
+	}
+	defer os.RemoveAll(frdir)
+
+	db, err := NewDatabaseWithFreezer(NewMemoryDatabase(), frdir, "", false)
+	if err != nil {
+		t.Fatalf("failed to create database with ancient backend")
+	}
+	// Freeze the first half of the chain, keep the rest in the key-value store
+	var hashes []common.Hash
+	for i := uint64(0); i < 20; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), Extra: []byte("test header")}
+		if i < 10 {
+			WriteAncientBlock(db, types.NewBlockWithHeader(header), nil, big.NewInt(100))
+		} else {
+			WriteHeader(db, header)
+			WriteCanonicalHash(db, header.Hash(), i)
+		}
+		hashes = append(hashes, header.Hash())
+	}
+	for _, tt := range []struct{ from, count, want uint64 }{
+		{0, 5, 5},
+		{5, 10, 10},
+		{15, 10, 5},
+		{20, 10, 0},
+	} {
+		headers := ReadHeaderRange(db, tt.from, tt.count)
+		if uint64(len(headers)) != tt.want {
+			t.Fatalf("range %d+%d: have %d headers, want %d", tt.from, tt.count, len(headers), tt.want)
+		}
+		for i, header := range headers {
+			if hash := crypto.Keccak256Hash(header); hash != hashes[tt.from+uint64(i)] {
+				t.Fatalf("range %d+%d: header %d hash mismatch: have %x, want %x", tt.from, tt.count, i, hash, hashes[tt.from+uint64(i)])
+			}
+		}
+	}
+}
+
 func TestCanonicalHashIteration(t *testing.T) {
 	var cases = []struct {
 		from, to uint64
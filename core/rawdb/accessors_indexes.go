@@ -175,3 +175,23 @@ func DeleteBloombits(db ethdb.Database, bit uint, from uint64, to uint64) {
 		log.Crit("Failed to delete bloom bits", "err", it.Error())
 	}
 }
+
+// ReadTraceIndexBits retrieves the compressed bit vector of the blocks touching
+// the given address within the section.
+func ReadTraceIndexBits(db ethdb.KeyValueReader, address common.Address, section uint64, head common.Hash) ([]byte, error) {
+	return db.Get(traceIndexBitsKey(address, section, head))
+}
+
+// HasTraceIndexBits reports whether the bit vector of the blocks touching the
+// given address within the section is stored.
+func HasTraceIndexBits(db ethdb.KeyValueReader, address common.Address, section uint64, head common.Hash) (bool, error) {
+	return db.Has(traceIndexBitsKey(address, section, head))
+}
+
+// WriteTraceIndexBits stores the compressed bit vector of the blocks touching
+// the given address within the section.
+func WriteTraceIndexBits(db ethdb.KeyValueWriter, address common.Address, section uint64, head common.Hash, bits []byte) {
+	if err := db.Put(traceIndexBitsKey(address, section, head), bits); err != nil {
+		log.Crit("Failed to store trace index bits", "err", err)
+	}
+}
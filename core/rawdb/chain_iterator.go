@@ -78,6 +78,10 @@ func InitDatabaseFromFreezer(db ethdb.Database) {
 	log.Info("Initialized database from freezer", "blocks", frozen, "elapsed", common.PrettyDuration(time.Since(start)))
 }
 
+// iterateBodiesBatch is the number of consecutive block bodies read at once
+// while iterating transactions.
+const iterateBodiesBatch = 128
+
 type blockTxHashes struct {
 	number uint64
 	hashes []common.Hash
@@ -106,24 +110,37 @@ func iterateTransactions(db ethdb.Database, from uint64, to uint64, reverse bool
 	)
 	// lookup runs in one instance
 	lookup := func() {
-		n, end := from, to
-		if reverse {
-			n, end = to-1, from-1
-		}
 		defer close(rlpCh)
-		for n != end {
-			data := ReadCanonicalBodyRLP(db, n)
-			// Feed the block to the aggregator, or abort on interrupt
-			select {
-			case rlpCh <- &numberRlp{n, data}:
-			case <-interrupt:
-				return
+		for done := uint64(0); done < to-from; {
+			// Read the bodies in batches, to make use of bulk ancient reads
+			count := to - from - done
+			if count > iterateBodiesBatch {
+				count = iterateBodiesBatch
 			}
+			first := from + done
 			if reverse {
-				n--
-			} else {
-				n++
+				first = to - done - count
+			}
+			bodies := ReadCanonicalBodyRange(db, first, count)
+			for i := uint64(0); i < count; i++ {
+				idx := i
+				if reverse {
+					idx = count - 1 - i
+				}
+				var data rlp.RawValue
+				if idx < uint64(len(bodies)) {
+					data = bodies[idx]
+				} else {
+					data = ReadCanonicalBodyRLP(db, first+idx)
+				}
+				// Feed the block to the aggregator, or abort on interrupt
+				select {
+				case rlpCh <- &numberRlp{first + idx, data}:
+				case <-interrupt:
+					return
+				}
 			}
+			done += count
 		}
 	}
 	// process runs in parallel
@@ -1,10 +1,13 @@
 package rawdb
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params/vars"
@@ -14,8 +17,14 @@ import (
 // FreezerRemoteClient is an RPC client implementing the interface of ethdb.AncientStore.
 // The struct's methods delegate the business logic to an external server
 // that is responsible for managing an actual ancient store.
+//
+// The remote freezer may be served over any transport supported by rpc.Dial,
+// ie. IPC, HTTP(S) or WS(S). Calls failing for transport reasons are retried,
+// redialing the endpoint in between.
 type FreezerRemoteClient struct {
 	client    *rpc.Client
+	clientMu  sync.RWMutex // Protects the client during reconnection
+	endpoint  string       // Endpoint to redial on transport failures; if empty, calls are not retried
 	quit      chan struct{}
 	threshold uint64             // Number of recent blocks not to freeze (params.FullImmutabilityThreshold apart from tests)
 	trigger   chan chan struct{} // Manual blocking freeze trigger, test determinism
@@ -27,13 +36,56 @@ const (
 	FreezerMethodClose            = "freezer_close"
 	FreezerMethodHasAncient       = "freezer_hasAncient"
 	FreezerMethodAncient          = "freezer_ancient"
+	FreezerMethodAncientRange     = "freezer_ancientRange"
 	FreezerMethodAncients         = "freezer_ancients"
 	FreezerMethodAncientSize      = "freezer_ancientSize"
 	FreezerMethodAppendAncient    = "freezer_appendAncient"
+	FreezerMethodAppendAncients   = "freezer_appendAncients"
 	FreezerMethodTruncateAncients = "freezer_truncateAncients"
 	FreezerMethodSync             = "freezer_sync"
 )
 
+const (
+	// freezerRemoteCallTimeout is the maximum duration of a single call to the remote freezer.
+	freezerRemoteCallTimeout = 2 * time.Minute
+
+	// freezerRemoteRetries is the number of times a call failing for transport reasons
+	// is retried before the error is returned.
+	freezerRemoteRetries = 5
+
+	// freezerRemoteRetryDelay is the initial delay before redialing the remote freezer.
+	// It is doubled with each consecutive failed attempt.
+	freezerRemoteRetryDelay = 500 * time.Millisecond
+
+	// freezerRemoteBatchItems is the initial and maximum number of blocks appended
+	// to the remote freezer in one call.
+	freezerRemoteBatchItems = 2048
+
+	// freezerRemoteBatchBytes is the approximate maximum size of the blocks
+	// appended to the remote freezer in one call. Hex encoded, batches must fit
+	// the RPC request size limits (5MB over HTTP).
+	freezerRemoteBatchBytes = 2 * 1024 * 1024
+
+	// freezerRemoteBatchTarget is the targeted duration of a batched append call.
+	// Batches are shrunk when the remote freezer is slower, and grown when it is faster.
+	freezerRemoteBatchTarget = 2 * time.Second
+)
+
+// FreezerRemoteItem holds the ancient data of a single block, as appended
+// to the remote freezer in batches.
+type FreezerRemoteItem struct {
+	Number   uint64        `json:"number"`
+	Hash     hexutil.Bytes `json:"hash"`
+	Header   hexutil.Bytes `json:"header"`
+	Body     hexutil.Bytes `json:"body"`
+	Receipts hexutil.Bytes `json:"receipts"`
+	Td       hexutil.Bytes `json:"td"`
+}
+
+func (item *FreezerRemoteItem) size() int {
+	return len(item.Hash) + len(item.Header) + len(item.Body) + len(item.Receipts) + len(item.Td)
+}
+
 // newFreezerRemoteClient constructs a rpc client to connect to a remote freezer
 func newFreezerRemoteClient(endpoint string, readonly bool) (*FreezerRemoteClient, error) {
 	client, err := rpc.Dial(endpoint)
@@ -42,6 +94,7 @@ func newFreezerRemoteClient(endpoint string, readonly bool) (*FreezerRemoteClien
 	}
 	return &FreezerRemoteClient{
 		client:    client,
+		endpoint:  endpoint,
 		threshold: vars.FullImmutabilityThreshold,
 		quit:      make(chan struct{}),
 		trigger:   make(chan chan struct{}),
@@ -49,39 +102,122 @@ func newFreezerRemoteClient(endpoint string, readonly bool) (*FreezerRemoteClien
 	}, nil
 }
 
-// Close terminates the chain freezer, unmapping all the data files.
+// isFreezerRemoteTransportError returns true if the error was not returned by the remote
+// freezer itself, in which case the call may be retried.
+func isFreezerRemoteTransportError(err error) bool {
+	var rpcErr rpc.Error
+	return err != nil && !errors.As(err, &rpcErr) && err != rpc.ErrClientQuit
+}
+
+func (api *FreezerRemoteClient) rpcClient() *rpc.Client {
+	api.clientMu.RLock()
+	defer api.clientMu.RUnlock()
+	return api.client
+}
+
+// redial replaces the client connection if it is still the given one.
+func (api *FreezerRemoteClient) redial(stale *rpc.Client) error {
+	api.clientMu.Lock()
+	defer api.clientMu.Unlock()
+	if api.client != stale {
+		return nil // Already reconnected by a concurrent call
+	}
+	client, err := rpc.Dial(api.endpoint)
+	if err != nil {
+		return err
+	}
+	api.client.Close()
+	api.client = client
+	log.Info("Reconnected to remote freezer", "endpoint", api.endpoint)
+	return nil
+}
+
+// call performs a remote freezer call, retrying and reconnecting on transport failures.
+func (api *FreezerRemoteClient) call(result interface{}, method string, args ...interface{}) error {
+	delay := freezerRemoteRetryDelay
+	for attempt := 0; ; attempt++ {
+		client := api.rpcClient()
+		ctx, cancel := context.WithTimeout(context.Background(), freezerRemoteCallTimeout)
+		err := client.CallContext(ctx, result, method, args...)
+		cancel()
+		if !isFreezerRemoteTransportError(err) || api.endpoint == "" || attempt == freezerRemoteRetries {
+			return err
+		}
+		log.Warn("Remote freezer call failed, reconnecting", "method", method, "attempt", attempt+1, "delay", delay, "err", err)
+		select {
+		case <-time.After(delay):
+		case <-api.quit:
+			return err
+		}
+		delay *= 2
+		if err := api.redial(client); err != nil {
+			log.Warn("Failed to reconnect to remote freezer", "endpoint", api.endpoint, "err", err)
+		}
+	}
+}
+
+// Close stops the remote freezer client's background freezing, and
+// terminates the remote chain freezer.
 func (api *FreezerRemoteClient) Close() error {
-	return api.client.Call(nil, FreezerMethodClose)
+	var err error
+	api.closeOnce.Do(func() {
+		if api.quit != nil {
+			close(api.quit)
+		}
+		client := api.rpcClient()
+		ctx, cancel := context.WithTimeout(context.Background(), freezerRemoteCallTimeout)
+		defer cancel()
+		err = client.CallContext(ctx, nil, FreezerMethodClose)
+		if api.endpoint != "" {
+			client.Close()
+		}
+	})
+	return err
 }
 
 // HasAncient returns an indicator whether the specified ancient data exists
 // in the freezer.
 func (api *FreezerRemoteClient) HasAncient(kind string, number uint64) (bool, error) {
 	var res bool
-	err := api.client.Call(&res, FreezerMethodHasAncient, kind, number)
+	err := api.call(&res, FreezerMethodHasAncient, kind, number)
 	return res, err
 }
 
 // Ancient retrieves an ancient binary blob from the append-only immutable files.
 func (api *FreezerRemoteClient) Ancient(kind string, number uint64) ([]byte, error) {
 	res := []byte{}
-	if err := api.client.Call(&res, FreezerMethodAncient, kind, number); err != nil {
+	if err := api.call(&res, FreezerMethodAncient, kind, number); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
+// AncientRange retrieves up to count consecutive ancient binary blobs of a kind,
+// starting at the given number. Items are returned until the cumulative size
+// would exceed maxBytes, but at least one item is returned if available.
+func (api *FreezerRemoteClient) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	var res []hexutil.Bytes
+	if err := api.call(&res, FreezerMethodAncientRange, kind, start, count, maxBytes); err != nil {
+		return nil, err
+	}
+	items := make([][]byte, len(res))
+	for i := range res {
+		items[i] = res[i]
+	}
+	return items, nil
+}
+
 // Ancients returns the length of the frozen items.
 func (api *FreezerRemoteClient) Ancients() (uint64, error) {
 	var res uint64
-	err := api.client.Call(&res, FreezerMethodAncients)
+	err := api.call(&res, FreezerMethodAncients)
 	return res, err
 }
 
 // AncientSize returns the ancient size of the specified category.
 func (api *FreezerRemoteClient) AncientSize(kind string) (uint64, error) {
 	var res uint64
-	err := api.client.Call(&res, FreezerMethodAncientSize, kind)
+	err := api.call(&res, FreezerMethodAncientSize, kind)
 	return res, err
 }
 
@@ -94,17 +230,25 @@ func (api *FreezerRemoteClient) AncientSize(kind string) (uint64, error) {
 //
 // Note that the frozen marker is updated outside of the service calls.
 func (api *FreezerRemoteClient) AppendAncient(number uint64, hash, header, body, receipts, td []byte) (err error) {
-	return api.client.Call(nil, FreezerMethodAppendAncient, number, hash, header, body, receipts, td)
+	return api.call(nil, FreezerMethodAppendAncient, number, hash, header, body, receipts, td)
+}
+
+// AppendAncients injects the ancient data of a batch of consecutive blocks at the end
+// of the append-only immutable table files, in a single call.
+// Items already appended with the same hash are skipped by the remote freezer,
+// allowing the call to be retried.
+func (api *FreezerRemoteClient) AppendAncients(items []FreezerRemoteItem) error {
+	return api.call(nil, FreezerMethodAppendAncients, items)
 }
 
 // TruncateAncients discards any recent data above the provided threshold number.
 func (api *FreezerRemoteClient) TruncateAncients(items uint64) error {
-	return api.client.Call(nil, FreezerMethodTruncateAncients, items)
+	return api.call(nil, FreezerMethodTruncateAncients, items)
 }
 
 // Sync flushes all data tables to disk.
 func (api *FreezerRemoteClient) Sync() error {
-	return api.client.Call(nil, FreezerMethodSync)
+	return api.call(nil, FreezerMethodSync)
 }
 
 // freezeRemote is a background thread that periodically checks the blockchain for any
@@ -119,12 +263,17 @@ func (api *FreezerRemoteClient) Sync() error {
 // to exist unmodified and untouched by the remote freezer client, which demands
 // a slightly different signature, and uses the freezer.Ancients() method instead
 // of direct access to the atomic freezer.frozen field.
-func freezeRemote(db ethdb.KeyValueStore, f ethdb.AncientStore, threshold uint64, quitChan chan struct{}, triggerChanChan chan chan struct{}) {
+//
+// Blocks are appended to the remote freezer in batches, sized adaptively so that
+// each call takes about freezerRemoteBatchTarget. Remote freezer failures cause the
+// thread to back off and retry later, rather than to stall or crash the node.
+func freezeRemote(db ethdb.KeyValueStore, f *FreezerRemoteClient, threshold uint64, quitChan chan struct{}, triggerChanChan chan chan struct{}) {
 	nfdb := &nofreezedb{KeyValueStore: db}
 
 	var (
 		backoff   bool
 		triggered chan struct{} // Used in tests
+		batchSize = freezerRemoteBatchItems
 	)
 	for {
 		select {
@@ -157,7 +306,9 @@ func freezeRemote(db ethdb.KeyValueStore, f ethdb.AncientStore, threshold uint64
 		}
 		numFrozen, err := f.Ancients()
 		if err != nil {
-			log.Crit("ancient db freeze", "error", err)
+			log.Error("Failed to retrieve remote freezer progress", "err", err)
+			backoff = true
+			continue
 		}
 		number := ReadHeaderNumber(nfdb, hash)
 		// threshold := atomic.LoadUint64(&f.threshold)
@@ -190,47 +341,100 @@ func freezeRemote(db ethdb.KeyValueStore, f ethdb.AncientStore, threshold uint64
 			limit = numFrozen + freezerBatchLimit
 		}
 		var (
-			start    = time.Now()
-			first    = numFrozen
-			ancients = make([]common.Hash, 0, limit-numFrozen)
+			start      = time.Now()
+			first      = numFrozen
+			ancients   = make([]common.Hash, 0, limit-numFrozen)
+			items      = make([]FreezerRemoteItem, 0, batchSize)
+			itemsBytes int
+			failed     bool
 		)
-		for numFrozen <= limit {
+		// flush appends the pending batch to the remote freezer, adapting the
+		// size of the next batches to the remote freezer's throughput.
+		flush := func() error {
+			if len(items) == 0 {
+				return nil
+			}
+			batchStart := time.Now()
+			if err := f.AppendAncients(items); err != nil {
+				return err
+			}
+			for _, item := range items {
+				ancients = append(ancients, common.BytesToHash(item.Hash))
+			}
+			numFrozen += uint64(len(items))
+			switch elapsed := time.Since(batchStart); {
+			case elapsed > freezerRemoteBatchTarget && batchSize > 1:
+				batchSize /= 2
+				log.Debug("Slow remote freezer, shrinking batches", "elapsed", common.PrettyDuration(elapsed), "size", batchSize)
+			case elapsed < freezerRemoteBatchTarget/2 && len(items) == batchSize && batchSize < freezerRemoteBatchItems:
+				batchSize *= 2
+			}
+			items, itemsBytes = items[:0], 0
+			return nil
+		}
+	collect:
+		for next := numFrozen; next <= limit; next++ {
+			select {
+			case <-quitChan:
+				break collect
+			default:
+			}
 			// Retrieves all the components of the canonical block
-			hash := ReadCanonicalHash(nfdb, numFrozen)
+			hash := ReadCanonicalHash(nfdb, next)
 			if hash == (common.Hash{}) {
-				log.Error("Canonical hash missing, can't freeze", "number", numFrozen)
+				log.Error("Canonical hash missing, can't freeze", "number", next)
 				break
 			}
-			header := ReadHeaderRLP(nfdb, hash, numFrozen)
+			header := ReadHeaderRLP(nfdb, hash, next)
 			if len(header) == 0 {
-				log.Error("Block header missing, can't freeze", "number", numFrozen, "hash", hash)
+				log.Error("Block header missing, can't freeze", "number", next, "hash", hash)
 				break
 			}
-			body := ReadBodyRLP(nfdb, hash, numFrozen)
+			body := ReadBodyRLP(nfdb, hash, next)
 			if len(body) == 0 {
-				log.Error("Block body missing, can't freeze", "number", numFrozen, "hash", hash)
+				log.Error("Block body missing, can't freeze", "number", next, "hash", hash)
 				break
 			}
-			receipts := ReadReceiptsRLP(nfdb, hash, numFrozen)
+			receipts := ReadReceiptsRLP(nfdb, hash, next)
 			if len(receipts) == 0 {
-				log.Error("Block receipts missing, can't freeze", "number", numFrozen, "hash", hash)
+				log.Error("Block receipts missing, can't freeze", "number", next, "hash", hash)
 				break
 			}
-			td := ReadTdRLP(nfdb, hash, numFrozen)
+			td := ReadTdRLP(nfdb, hash, next)
 			if len(td) == 0 {
-				log.Error("Total difficulty missing, can't freeze", "number", numFrozen, "hash", hash)
+				log.Error("Total difficulty missing, can't freeze", "number", next, "hash", hash)
 				break
 			}
-			log.Trace("Deep froze ancient block", "number", numFrozen, "hash", hash)
-			// Inject all the components into the relevant data tables
-			if err := f.AppendAncient(numFrozen, hash[:], header, body, receipts, td); err != nil {
-				break
+			log.Trace("Deep froze ancient block", "number", next, "hash", hash)
+			item := FreezerRemoteItem{
+				Number:   next,
+				Hash:     hash[:],
+				Header:   hexutil.Bytes(header),
+				Body:     hexutil.Bytes(body),
+				Receipts: hexutil.Bytes(receipts),
+				Td:       hexutil.Bytes(td),
+			}
+			items = append(items, item)
+			itemsBytes += item.size()
+			if len(items) >= batchSize || itemsBytes >= freezerRemoteBatchBytes {
+				if err := flush(); err != nil {
+					log.Error("Failed to append to remote freezer", "number", next, "err", err)
+					failed = true
+					break
+				}
+			}
+		}
+		if !failed {
+			if err := flush(); err != nil {
+				log.Error("Failed to append to remote freezer", "number", numFrozen, "err", err)
+				failed = true
 			}
-			ancients = append(ancients, hash)
 		}
 		// Batch of blocks have been frozen, flush them before wiping from leveldb
 		if err := f.Sync(); err != nil {
-			log.Crit("Failed to flush frozen tables", "err", err)
+			log.Error("Failed to flush remote frozen tables", "err", err)
+			backoff = true
+			continue
 		}
 		// Wipe out all data from the active database
 		batch := db.NewBatch()
@@ -305,8 +509,8 @@ func freezeRemote(db ethdb.KeyValueStore, f ethdb.AncientStore, threshold uint64
 		}
 		log.Info("Deep froze chain segment", context...)
 
-		// Avoid database thrashing with tiny writes
-		if numFrozen-first < freezerBatchLimit {
+		// Avoid database thrashing with tiny writes, and back off from a failing remote freezer
+		if numFrozen-first < freezerBatchLimit || failed {
 			backoff = true
 		}
 	}
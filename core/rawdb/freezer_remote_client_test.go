@@ -2,16 +2,20 @@ package rawdb
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
 	"testing"
 
-	"github.com/ethereum/go-ethereum/cmd/ancient-store-mem/lib"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-func newTestServer(t *testing.T) *rpc.Server {
+func newTestServer(t *testing.T, datadir string) *rpc.Server {
+	store, err := NewFreezerAncientStore(datadir, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
 	server := rpc.NewServer()
-	mockFreezerServer := lib.NewMemFreezerRemoteServerAPI()
-	err := server.RegisterName("freezer", mockFreezerServer)
+	err = server.RegisterName("freezer", NewFreezerRemoteServerAPI(store))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -19,7 +23,14 @@ func newTestServer(t *testing.T) *rpc.Server {
 }
 
 func TestClient1(t *testing.T) {
-	server := newTestServer(t)
+	datadir, err := ioutil.TempDir("", "freezer-remote-client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+
+	server := newTestServer(t, datadir)
+	defer server.Stop()
 	client := rpc.DialInProc(server)
 
 	frClient := &FreezerRemoteClient{
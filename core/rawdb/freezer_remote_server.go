@@ -0,0 +1,147 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// NewFreezerAncientStore opens the append-only flat file ancient store in the given directory,
+// as used by the builtin freezer, without moving any data into it.
+// It is intended to back remote freezer servers.
+func NewFreezerAncientStore(datadir string, namespace string, readonly bool) (ethdb.AncientStore, error) {
+	return newFreezer(datadir, namespace, readonly)
+}
+
+// FreezerRemoteServerAPI serves an ancient store to FreezerRemoteClients,
+// and is intended to be registered with an RPC server under the "freezer" namespace.
+//
+// The lifetime of the ancient store is managed by the server, and
+// clients closing the remote freezer only cause it to be synced.
+type FreezerRemoteServerAPI struct {
+	store ethdb.AncientStore
+	mu    sync.Mutex // Serializes mutations of the store
+}
+
+// NewFreezerRemoteServerAPI creates a remote freezer server API backed by the given ancient store.
+func NewFreezerRemoteServerAPI(store ethdb.AncientStore) *FreezerRemoteServerAPI {
+	return &FreezerRemoteServerAPI{store: store}
+}
+
+// Close flushes the ancient store to disk. The store remains open.
+func (api *FreezerRemoteServerAPI) Close() error {
+	return api.Sync()
+}
+
+// HasAncient returns an indicator whether the specified ancient data exists in the store.
+func (api *FreezerRemoteServerAPI) HasAncient(kind string, number uint64) (bool, error) {
+	return api.store.HasAncient(kind, number)
+}
+
+// Ancient retrieves an ancient binary blob from the store.
+func (api *FreezerRemoteServerAPI) Ancient(kind string, number uint64) ([]byte, error) {
+	return api.store.Ancient(kind, number)
+}
+
+// AncientRange retrieves up to count consecutive ancient binary blobs of a kind,
+// starting at the given number. Items are returned until the cumulative size
+// would exceed maxBytes, but at least one item is returned if available.
+func (api *FreezerRemoteServerAPI) AncientRange(kind string, start, count, maxBytes uint64) ([]hexutil.Bytes, error) {
+	blobs, err := api.store.AncientRange(kind, start, count, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]hexutil.Bytes, len(blobs))
+	for i, blob := range blobs {
+		items[i] = blob
+	}
+	return items, nil
+}
+
+// Ancients returns the number of items in the store.
+func (api *FreezerRemoteServerAPI) Ancients() (uint64, error) {
+	return api.store.Ancients()
+}
+
+// AncientSize returns the ancient size of the specified category.
+func (api *FreezerRemoteServerAPI) AncientSize(kind string) (uint64, error) {
+	return api.store.AncientSize(kind)
+}
+
+// AppendAncient injects all binary blobs belonging to a block at the end of the store.
+// A block which has already been appended with the same hash is skipped, so that
+// clients can safely retry appends.
+func (api *FreezerRemoteServerAPI) AppendAncient(number uint64, hash, header, body, receipts, td []byte) error {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return api.appendAncient(number, hash, header, body, receipts, td)
+}
+
+// AppendAncients injects the ancient data of a batch of consecutive blocks at the end of the store.
+// Blocks which have already been appended with the same hash are skipped, so that
+// clients can safely retry appends.
+func (api *FreezerRemoteServerAPI) AppendAncients(items []FreezerRemoteItem) error {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	for _, item := range items {
+		if err := api.appendAncient(item.Number, item.Hash, item.Header, item.Body, item.Receipts, item.Td); err != nil {
+			return fmt.Errorf("block %d: %w", item.Number, err)
+		}
+	}
+	return nil
+}
+
+func (api *FreezerRemoteServerAPI) appendAncient(number uint64, hash, header, body, receipts, td []byte) error {
+	frozen, err := api.store.Ancients()
+	if err != nil {
+		return err
+	}
+	if number < frozen {
+		existing, err := api.store.Ancient(freezerHashTable, number)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(existing, hash) {
+			return nil
+		}
+		return errOutOrderInsertion
+	}
+	return api.store.AppendAncient(number, hash, header, body, receipts, td)
+}
+
+// TruncateAncients discards any recent data above the provided threshold number.
+func (api *FreezerRemoteServerAPI) TruncateAncients(items uint64) error {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return api.store.TruncateAncients(items)
+}
+
+// Sync flushes all data tables to disk.
+func (api *FreezerRemoteServerAPI) Sync() error {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return api.store.Sync()
+}
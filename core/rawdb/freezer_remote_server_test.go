@@ -0,0 +1,268 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newTestFreezerRemoteServer serves a flat file remote freezer over HTTP and WS on the given listener.
+func newTestFreezerRemoteServer(t *testing.T, datadir string, listener net.Listener) (*http.Server, func()) {
+	store, err := NewFreezerAncientStore(datadir, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName("freezer", NewFreezerRemoteServerAPI(store)); err != nil {
+		t.Fatal(err)
+	}
+	ws := server.WebsocketHandler([]string{"*"})
+	httpServer := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.ToLower(r.Header.Get("Upgrade")) == "websocket" {
+			ws.ServeHTTP(w, r)
+			return
+		}
+		server.ServeHTTP(w, r)
+	})}
+	go httpServer.Serve(listener)
+	return httpServer, func() {
+		httpServer.Close()
+		server.Stop()
+		store.Close()
+	}
+}
+
+func testFreezerRemoteItems(first, n uint64) []FreezerRemoteItem {
+	items := make([]FreezerRemoteItem, n)
+	for i := range items {
+		number := first + uint64(i)
+		item := bytes.Repeat([]byte{byte(number)}, int(number%7)+1)
+		items[i] = FreezerRemoteItem{
+			Number:   number,
+			Hash:     common.BigToHash(new(big.Int).SetUint64(number)).Bytes(),
+			Header:   item,
+			Body:     item,
+			Receipts: item,
+			Td:       item,
+		}
+	}
+	return items
+}
+
+func TestFreezerRemoteServer(t *testing.T) {
+	for _, scheme := range []string{"http", "ws"} {
+		t.Run(scheme, func(t *testing.T) {
+			datadir, err := ioutil.TempDir("", "freezer-remote")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(datadir)
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, stop := newTestFreezerRemoteServer(t, datadir, listener)
+			defer stop()
+
+			client, err := newFreezerRemoteClient(scheme+"://"+listener.Addr().String(), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+
+			items := testFreezerRemoteItems(0, 100)
+			if err := client.AppendAncients(items[:60]); err != nil {
+				t.Fatal(err)
+			}
+			// Retried and overlapping batches are accepted.
+			if err := client.AppendAncients(items[50:]); err != nil {
+				t.Fatal(err)
+			}
+			if n, err := client.Ancients(); err != nil || n != 100 {
+				t.Fatalf("ancients: got %d (%v), want 100", n, err)
+			}
+			// Conflicting batches are not.
+			conflicting := testFreezerRemoteItems(90, 1)
+			conflicting[0].Hash = common.Hash{0xff}.Bytes()
+			if err := client.AppendAncients(conflicting); err == nil {
+				t.Fatal("expected error appending conflicting item")
+			}
+			if err := client.AppendAncient(100, common.Hash{}.Bytes(), nil, nil, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			// Range reads are limited by count, bytes and the number of items.
+			got, err := client.AncientRange(freezerHeaderTable, 10, 20, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != 20 {
+				t.Fatalf("range: got %d items, want 20", len(got))
+			}
+			for i, item := range got {
+				if !bytes.Equal(item, items[10+i].Header) {
+					t.Fatalf("range item %d: got %x, want %x", i, item, items[10+i].Header)
+				}
+			}
+			// The byte limit applies to the stored size, so check it against an uncompressed table.
+			if got, err := client.AncientRange(freezerDifficultyTable, 14, 20, 9); err != nil || len(got) != 3 {
+				t.Fatalf("byte limited range: got %d items (%v), want 3", len(got), err)
+			}
+			if got, err := client.AncientRange(freezerDifficultyTable, 6, 20, 1); err != nil || len(got) != 1 {
+				t.Fatalf("byte limited range: got %d items (%v), want 1", len(got), err)
+			}
+			if got, err := client.AncientRange(freezerHeaderTable, 95, 20, 0); err != nil || len(got) != 6 {
+				t.Fatalf("tail range: got %d items (%v), want 6", len(got), err)
+			}
+
+			if err := client.TruncateAncients(50); err != nil {
+				t.Fatal(err)
+			}
+			if err := client.Sync(); err != nil {
+				t.Fatal(err)
+			}
+			if ok, err := client.HasAncient(freezerBodiesTable, 50); err != nil || ok {
+				t.Fatalf("has truncated ancient: %v (%v)", ok, err)
+			}
+			if v, err := client.Ancient(freezerBodiesTable, 49); err != nil || !bytes.Equal(v, items[49].Body) {
+				t.Fatalf("ancient: got %x (%v), want %x", v, err, items[49].Body)
+			}
+		})
+	}
+}
+
+// TestFreezerRemoteClientReconnect tests that calls are retried across remote freezer restarts.
+func TestFreezerRemoteClientReconnect(t *testing.T) {
+	for _, scheme := range []string{"http", "ws"} {
+		t.Run(scheme, func(t *testing.T) {
+			datadir, err := ioutil.TempDir("", "freezer-remote")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(datadir)
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			addr := listener.Addr().String()
+			_, stop := newTestFreezerRemoteServer(t, datadir, listener)
+
+			client, err := newFreezerRemoteClient(scheme+"://"+addr, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+
+			if err := client.AppendAncients(testFreezerRemoteItems(0, 10)); err != nil {
+				t.Fatal(err)
+			}
+			if err := client.Sync(); err != nil {
+				t.Fatal(err)
+			}
+			stop()
+
+			listener, err = net.Listen("tcp", addr)
+			if err != nil {
+				t.Skipf("can't relisten on %s: %v", addr, err)
+			}
+			_, stop = newTestFreezerRemoteServer(t, datadir, listener)
+			defer stop()
+
+			if n, err := client.Ancients(); err != nil || n != 10 {
+				t.Fatalf("ancients: got %d (%v), want 10", n, err)
+			}
+		})
+	}
+}
+
+// TestFreezeRemote tests that the remote freezer client moves ancient chain data
+// from the key-value store into the remote freezer.
+func TestFreezeRemote(t *testing.T) {
+	datadir, err := ioutil.TempDir("", "freezer-remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(datadir)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, stop := newTestFreezerRemoteServer(t, datadir, listener)
+	defer stop()
+
+	client, err := newFreezerRemoteClient("http://"+listener.Addr().String(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Write a canonical chain into the key-value store.
+	db := memorydb.New()
+	var (
+		parent common.Hash
+		hashes []common.Hash
+	)
+	for i := uint64(0); i < 100; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), ParentHash: parent, Difficulty: big.NewInt(1)}
+		hash := header.Hash()
+		WriteHeader(db, header)
+		WriteBody(db, hash, i, &types.Body{})
+		WriteReceipts(db, hash, i, types.Receipts{})
+		WriteTd(db, hash, i, new(big.Int).SetUint64(i+1))
+		WriteCanonicalHash(db, hash, i)
+		hashes = append(hashes, hash)
+		parent = hash
+	}
+	WriteHeadBlockHash(db, parent)
+
+	trigger := make(chan chan struct{})
+	go freezeRemote(db, client, 10, client.quit, trigger)
+	triggered := make(chan struct{})
+	trigger <- triggered
+	<-triggered
+
+	if n, err := client.Ancients(); err != nil || n != 90 {
+		t.Fatalf("ancients: got %d (%v), want 90", n, err)
+	}
+	for i, hash := range hashes {
+		frozen, err := client.Ancient(freezerHashTable, uint64(i))
+		switch {
+		case i < 90 && (err != nil || !bytes.Equal(frozen, hash[:])):
+			t.Fatalf("block %d: frozen hash %x (%v), want %x", i, frozen, err, hash)
+		case i >= 90 && err == nil:
+			t.Fatalf("block %d: unexpectedly frozen", i)
+		}
+		if kv := ReadCanonicalHash(NewDatabase(db), uint64(i)); (kv == common.Hash{}) != (i > 0 && i < 90) {
+			t.Fatalf("block %d: key-value canonical hash %x", i, kv)
+		}
+	}
+}
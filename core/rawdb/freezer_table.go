@@ -626,6 +626,111 @@ func (t *freezerTable) retrieve(item uint64) ([]byte, error) {
 	return blob, nil
 }
 
+// RetrieveItems returns up to count consecutive items, starting at the given item.
+// Items are returned until their cumulative stored size would exceed maxBytes
+// (0 means no limit), but at least one item is returned. Consecutive items in
+// the same data file are read with a single disk access.
+func (t *freezerTable) RetrieveItems(start, count, maxBytes uint64) ([][]byte, error) {
+	blob, sizes, err := t.retrieveItems(start, count, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	items := make([][]byte, 0, len(sizes))
+	for _, size := range sizes {
+		item := blob[:size:size]
+		blob = blob[size:]
+		if !t.noCompression {
+			if item, err = snappy.Decode(nil, item); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// retrieveItems reads the raw binary blobs of up to count consecutive items from
+// the data files, returning them concatenated along with the size of each item.
+// OBS! This method does not decode compressed data.
+func (t *freezerTable) retrieveItems(start, count, maxBytes uint64) ([]byte, []int, error) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	// Ensure the table and the first item is accessible
+	if t.index == nil || t.head == nil {
+		return nil, nil, errClosed
+	}
+	items := atomic.LoadUint64(&t.items)
+	if items <= start || uint64(t.itemOffset) > start {
+		return nil, nil, errOutOfBounds
+	}
+	if items-start < count {
+		count = items - start
+	}
+	if count == 0 {
+		return nil, nil, nil
+	}
+	// Read all the index entries bounding the requested items at once
+	relative := start - uint64(t.itemOffset)
+	indices := make([]byte, (count+1)*indexEntrySize)
+	if _, err := t.index.ReadAt(indices, int64(relative*indexEntrySize)); err != nil {
+		return nil, nil, err
+	}
+	var (
+		blob  []byte
+		sizes []int
+		total uint64
+
+		prev indexEntry
+		// The pending run of consecutive items within a single data file
+		runFile          uint32
+		runStart, runEnd uint32
+	)
+	readRun := func() error {
+		if runStart == runEnd {
+			return nil
+		}
+		dataFile, exist := t.files[runFile]
+		if !exist {
+			return fmt.Errorf("missing data file %d", runFile)
+		}
+		offset := len(blob)
+		blob = append(blob, make([]byte, runEnd-runStart)...)
+		_, err := dataFile.ReadAt(blob[offset:], int64(runStart))
+		return err
+	}
+	prev.unmarshalBinary(indices[:indexEntrySize])
+	for i := uint64(0); i < count; i++ {
+		var next indexEntry
+		next.unmarshalBinary(indices[(i+1)*indexEntrySize:])
+
+		// Same as in getBounds: the very first item and items crossing into
+		// a new data file start at offset zero.
+		startOffset := prev.offset
+		if relative+i == 0 || prev.filenum != next.filenum {
+			startOffset = 0
+		}
+		size := uint64(next.offset - startOffset)
+		if len(sizes) > 0 && maxBytes > 0 && total+size > maxBytes {
+			break
+		}
+		if len(sizes) == 0 || next.filenum != runFile || startOffset != runEnd {
+			if err := readRun(); err != nil {
+				return nil, nil, err
+			}
+			runFile, runStart = next.filenum, startOffset
+		}
+		runEnd = next.offset
+		sizes = append(sizes, int(size))
+		total += size
+		prev = next
+	}
+	if err := readRun(); err != nil {
+		return nil, nil, err
+	}
+	t.readMeter.Mark(int64(len(blob) + (len(sizes)+1)*indexEntrySize))
+	return blob, sizes, nil
+}
+
 // has returns an indicator whether the specified number data
 // exists in the freezer table.
 func (t *freezerTable) has(number uint64) bool {
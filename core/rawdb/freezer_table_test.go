@@ -692,3 +692,51 @@ func TestAppendTruncateParallel(t *testing.T) {
 		}
 	}
 }
+
+// TestFreezerRetrieveItems tests reading ranges of items spanning multiple data files.
+func TestFreezerRetrieveItems(t *testing.T) {
+	t.Parallel()
+	for _, noCompression := range []bool{true, false} {
+		f, err := newCustomTable(os.TempDir(), fmt.Sprintf("unittest-%d", rand.Uint64()),
+			metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, noCompression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Write 100 items of varying sizes, some of them crossing data files
+		for x := 0; x < 100; x++ {
+			f.Append(uint64(x), getChunk(x%20+1, x))
+		}
+		for start := uint64(0); start < 100; start += 7 {
+			items, err := f.RetrieveItems(start, 30, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := 30
+			if left := 100 - int(start); left < want {
+				want = left
+			}
+			if len(items) != want {
+				t.Fatalf("start %d: have %d items, want %d", start, len(items), want)
+			}
+			for i, item := range items {
+				x := int(start) + i
+				if exp := getChunk(x%20+1, x); !bytes.Equal(item, exp) {
+					t.Fatalf("item %d: have %x, want %x", x, item, exp)
+				}
+			}
+		}
+		if noCompression {
+			// Items 0..4 take 1+2+3+4+5 bytes
+			if items, err := f.RetrieveItems(0, 10, 10); err != nil || len(items) != 4 {
+				t.Fatalf("byte limited: have %d items (%v), want 4", len(items), err)
+			}
+			if items, err := f.RetrieveItems(10, 10, 1); err != nil || len(items) != 1 {
+				t.Fatalf("byte limited: have %d items (%v), want 1", len(items), err)
+			}
+		}
+		if _, err := f.RetrieveItems(100, 10, 0); err != errOutOfBounds {
+			t.Fatalf("out of bounds: have %v, want %v", err, errOutOfBounds)
+		}
+		f.Close()
+	}
+}
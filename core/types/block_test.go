@@ -129,6 +129,45 @@ func TestEIP2718BlockEncoding(t *testing.T) {
 	}
 }
 
+func TestEIP1559HeaderEncoding(t *testing.T) {
+	header := &Header{
+		Difficulty: big.NewInt(131072),
+		Number:     big.NewInt(42),
+		GasLimit:   30000000,
+		GasUsed:    15000000,
+		Time:       1426516743,
+		Extra:      []byte("coolest block on chain"),
+	}
+	legacyEnc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		t.Fatal("encode error: ", err)
+	}
+	header.BaseFee = big.NewInt(1000000000)
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		t.Fatal("encode error: ", err)
+	}
+	if bytes.Equal(enc, legacyEnc) {
+		t.Fatal("base fee not included in header encoding")
+	}
+	var decoded, decodedLegacy Header
+	if err := rlp.DecodeBytes(enc, &decoded); err != nil {
+		t.Fatal("decode error: ", err)
+	}
+	if decoded.BaseFee == nil || decoded.BaseFee.Cmp(header.BaseFee) != 0 {
+		t.Errorf("base fee mismatch: got %v, want %v", decoded.BaseFee, header.BaseFee)
+	}
+	if decoded.Hash() != header.Hash() {
+		t.Errorf("hash mismatch: got %x, want %x", decoded.Hash(), header.Hash())
+	}
+	if err := rlp.DecodeBytes(legacyEnc, &decodedLegacy); err != nil {
+		t.Fatal("decode error: ", err)
+	}
+	if decodedLegacy.BaseFee != nil {
+		t.Errorf("legacy header decoded with base fee %v", decodedLegacy.BaseFee)
+	}
+}
+
 func TestUncleHash(t *testing.T) {
 	uncles := make([]*Header, 0)
 	h := CalcUncleHash(uncles)
@@ -0,0 +1,192 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/params/vars"
+)
+
+// BasicBlock is a sequence of instructions which can only be entered at its
+// first instruction and only be left after its last one.
+type BasicBlock struct {
+	Start      uint64 `json:"start"`      // Position of the first instruction
+	End        uint64 `json:"end"`        // Position following the last instruction
+	Reachable  bool   `json:"reachable"`  // Whether the block can be reached from the code entry
+	StackIn    int    `json:"stackIn"`    // Minimum stack height on entry for the block not to underflow
+	StackMax   int    `json:"stackMax"`   // Maximum stack height reached within the block, relative to the entry height
+	StackDelta int    `json:"stackDelta"` // Stack height change from entering to leaving the block
+	Gas        uint64 `json:"gas"`        // Static gas of the instructions
+	DynamicGas bool   `json:"dynamicGas"` // Whether any instruction charges gas on top of its static gas
+
+	jumpdest    bool     // Whether the block starts with a JUMPDEST
+	dynamicJump bool     // Whether the block ends with a jump to a computed destination
+	successors  []uint64 // Start positions of the blocks execution may continue with
+}
+
+// InvalidInstruction is an instruction not defined at the analysed fork.
+type InvalidInstruction struct {
+	PC        uint64 `json:"pc"`
+	Op        OpCode `json:"op"`
+	Reachable bool   `json:"reachable"`
+}
+
+// CodeAnalysis is the result of the static analysis of a contract code.
+type CodeAnalysis struct {
+	Blocks  []*BasicBlock        `json:"blocks"`
+	Invalid []InvalidInstruction `json:"invalid"`
+}
+
+// Unreachable returns the blocks which can't be reached from the code entry.
+func (a *CodeAnalysis) Unreachable() []*BasicBlock {
+	var blocks []*BasicBlock
+	for _, block := range a.Blocks {
+		if !block.Reachable {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// AnalyzeCode splits the code into basic blocks, using the instruction set of
+// the fork active at the given block number. Jumps to destinations pushed right
+// before them are followed to find the unreachable blocks, jumps to computed
+// destinations are assumed to reach any JUMPDEST.
+func AnalyzeCode(code []byte, config ctypes.ChainConfigurator, number *big.Int) *CodeAnalysis {
+	var (
+		analysis = new(CodeAnalysis)
+		jt       = instructionSetForConfig(config, number)
+		bits     = codeBitmap(code)
+		block    *BasicBlock
+		pushed   *big.Int // Value pushed by the previous instruction, if any
+	)
+	for pc := uint64(0); pc < uint64(len(code)); {
+		op := OpCode(code[pc])
+
+		// A JUMPDEST starts a new block, entered from the previous one too
+		if op == JUMPDEST && block != nil {
+			block.successors = append(block.successors, pc)
+			block = nil
+		}
+		if block == nil {
+			block = &BasicBlock{Start: pc, jumpdest: op == JUMPDEST}
+			analysis.Blocks = append(analysis.Blocks, block)
+		}
+		var size uint64 // Size of the immediate data of the instruction
+		if op.IsPush() {
+			size = uint64(op - PUSH1 + 1)
+		}
+		next := pc + 1 + size
+		if next > uint64(len(code)) {
+			next = uint64(len(code)) // Missing immediate data is zero padded
+		}
+		operation := jt[op]
+		if operation == nil {
+			// Undefined instructions abort the execution
+			analysis.Invalid = append(analysis.Invalid, InvalidInstruction{PC: pc, Op: op})
+			block.End, block, pushed, pc = next, nil, nil, next
+			continue
+		}
+		pops := operation.minStack
+		pushes := pops + int(vars.StackLimit) - operation.maxStack
+		if need := pops - block.StackDelta; need > block.StackIn {
+			block.StackIn = need
+		}
+		block.StackDelta += pushes - pops
+		if block.StackDelta > block.StackMax {
+			block.StackMax = block.StackDelta
+		}
+		block.Gas += operation.constantGas
+		block.DynamicGas = block.DynamicGas || operation.dynamicGas != nil
+		block.End = next
+
+		switch {
+		case op == JUMP || op == JUMPI:
+			if pushed == nil {
+				block.dynamicJump = true
+			} else if pushed.IsUint64() && isJumpdest(code, bits, pushed.Uint64()) {
+				block.successors = append(block.successors, pushed.Uint64())
+			}
+			if op == JUMPI {
+				block.successors = append(block.successors, next)
+			}
+			block = nil
+		case operation.halts || operation.reverts:
+			block = nil
+		}
+		// Track the pushed value for the static jumps
+		pushed = nil
+		if op.IsPush() {
+			pushed = new(big.Int).SetBytes(getData(code, pc+1, size))
+		}
+		pc = next
+	}
+	analysis.markReachable()
+	return analysis
+}
+
+// markReachable flags the blocks reachable from the code entry, and the invalid
+// instructions within them.
+func (a *CodeAnalysis) markReachable() {
+	if len(a.Blocks) == 0 {
+		return
+	}
+	var (
+		blocks  = make(map[uint64]*BasicBlock, len(a.Blocks))
+		queue   = []*BasicBlock{a.Blocks[0]}
+		dynamic bool // Whether a computed jump destination was reached
+	)
+	for _, block := range a.Blocks {
+		blocks[block.Start] = block
+	}
+	a.Blocks[0].Reachable = true
+	for len(queue) > 0 {
+		block := queue[0]
+		queue = queue[1:]
+
+		for _, start := range block.successors {
+			if next := blocks[start]; next != nil && !next.Reachable {
+				next.Reachable = true
+				queue = append(queue, next)
+			}
+		}
+		if block.dynamicJump && !dynamic {
+			dynamic = true
+			for _, next := range a.Blocks {
+				if next.jumpdest && !next.Reachable {
+					next.Reachable = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+	for i, invalid := range a.Invalid {
+		for _, block := range a.Blocks {
+			if block.Start <= invalid.PC && invalid.PC < block.End {
+				a.Invalid[i].Reachable = block.Reachable
+				break
+			}
+		}
+	}
+}
+
+// isJumpdest returns whether the position is a JUMPDEST instruction.
+func isJumpdest(code []byte, bits bitvec, dest uint64) bool {
+	return dest < uint64(len(code)) && OpCode(code[dest]) == JUMPDEST && bits.codeSegment(dest)
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestAnalyzeCode(t *testing.T) {
+	code := []byte{
+		byte(PUSH1), 0x01, byte(PUSH1), 0x08, byte(JUMPI), // 0: jump to 8 if 1
+		byte(PUSH1), 0x00, byte(DUP1), // 5: reached if the condition is zero
+		byte(JUMPDEST), byte(POP), byte(STOP), // 8: pops one item more than pushed
+		byte(JUMPDEST), byte(SELFBALANCE), byte(STOP), // 11: never jumped to
+	}
+	analysis := AnalyzeCode(code, params.ClassicChainConfig, big.NewInt(10_500_839))
+
+	type summary struct {
+		start, end         uint64
+		reachable          bool
+		stackIn, stackMax  int
+		stackDelta         int
+		gas                uint64
+		dynamicGas, jumpIn bool
+	}
+	var have []summary
+	for _, block := range analysis.Blocks {
+		have = append(have, summary{block.Start, block.End, block.Reachable, block.StackIn, block.StackMax, block.StackDelta, block.Gas, block.DynamicGas, block.jumpdest})
+	}
+	want := []summary{
+		{0, 5, true, 0, 2, 0, 3 + 3 + 10, false, false},
+		{5, 8, true, 0, 2, 2, 3 + 3, false, false},
+		{8, 11, true, 1, 0, -1, 1 + 2 + 0, false, true},
+		{11, 14, false, 0, 1, 1, 1 + 5 + 0, false, true},
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("blocks mismatch:\nhave %+v\nwant %+v", have, want)
+	}
+	if len(analysis.Invalid) != 0 {
+		t.Errorf("unexpected invalid instructions: %v", analysis.Invalid)
+	}
+	if unreachable := analysis.Unreachable(); len(unreachable) != 1 || unreachable[0].Start != 11 {
+		t.Errorf("unreachable blocks mismatch: %v", unreachable)
+	}
+	// SELFBALANCE is not available before Phoenix on Classic
+	analysis = AnalyzeCode(code, params.ClassicChainConfig, big.NewInt(10_500_838))
+	if want := []InvalidInstruction{{PC: 12, Op: SELFBALANCE}}; !reflect.DeepEqual(analysis.Invalid, want) {
+		t.Errorf("invalid instructions mismatch: have %v, want %v", analysis.Invalid, want)
+	}
+}
+
+func TestAnalyzeCodeDynamicJump(t *testing.T) {
+	code := []byte{
+		byte(CALLDATASIZE), byte(JUMP), // 0: computed destination
+		byte(JUMPDEST), byte(STOP), // 2: reachable through the computed jump
+		byte(PUSH1), 0x5b, byte(STOP), // 4: 0x5b is push data, not a JUMPDEST
+	}
+	analysis := AnalyzeCode(code, params.AllEthashProtocolChanges, new(big.Int))
+	if len(analysis.Blocks) != 3 {
+		t.Fatalf("block count mismatch: have %d, want 3", len(analysis.Blocks))
+	}
+	for i, want := range []bool{true, true, false} {
+		if analysis.Blocks[i].Reachable != want {
+			t.Errorf("block %d reachability mismatch: have %v, want %v", i, analysis.Blocks[i].Reachable, want)
+		}
+	}
+}
@@ -25,6 +25,8 @@ import (
 )
 
 var activators = map[int]func(*JumpTable){
+	3529: enable3529,
+	3198: enable3198,
 	2929: enable2929,
 	2200: enable2200,
 	1884: enable1884,
@@ -148,3 +150,42 @@ func enable2929(jt *JumpTable) {
 	jt[SELFDESTRUCT].constantGas = vars.SelfdestructGasEIP150
 	jt[SELFDESTRUCT].dynamicGas = gasSelfdestructEIP2929
 }
+
+// enable3529 enabled "EIP-3529: Reduction in refunds":
+// - Removes refunds for selfdestructs
+// - Reduces refunds for SSTORE
+// - Reduces max refunds to 20% gas
+func enable3529(jt *JumpTable) {
+	// Without the access lists of EIP-2929, which price SLOAD dynamically, the
+	// refunds are reduced on top of the SSTORE gas metering the chain configures
+	if jt[SLOAD].dynamicGas == nil {
+		jt[SSTORE].dynamicGas = gasSStoreEIP3529NoAccessList
+		jt[SELFDESTRUCT].dynamicGas = gasSelfdestructEIP3529NoAccessList
+		return
+	}
+	jt[SSTORE].dynamicGas = gasSStoreEIP3529
+	jt[SELFDESTRUCT].dynamicGas = gasSelfdestructEIP3529
+}
+
+// enable3198 applies EIP-3198 (BASEFEE Opcode)
+// - Adds an opcode that returns the current block's base fee.
+func enable3198(jt *JumpTable) {
+	// New opcode
+	jt[BASEFEE] = &operation{
+		execute:     opBaseFee,
+		constantGas: GasQuickStep,
+		minStack:    minStack(0, 1),
+		maxStack:    maxStack(0, 1),
+	}
+}
+
+// opBaseFee implements BASEFEE opcode
+func opBaseFee(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	// The base fee is unset if EIP-1559 isn't enabled alongside EIP-3198
+	baseFee := new(uint256.Int)
+	if interpreter.evm.Context.BaseFee != nil {
+		baseFee, _ = uint256.FromBig(interpreter.evm.Context.BaseFee)
+	}
+	scope.Stack.push(baseFee)
+	return nil, nil
+}
@@ -169,6 +169,11 @@ func (host *hostContext) SetStorage(evmcAddr evmc.Address, evmcKey evmc.Hash, ev
 
 	resetClearRefund := vars.NetSstoreResetClearRefund
 	cleanRefund := vars.NetSstoreResetRefund
+	clearRefund := vars.NetSstoreClearRefund
+
+	if host.env.ChainConfig().IsEnabled(host.env.ChainConfig().GetEIP3529Transition, host.env.Context.BlockNumber) {
+		clearRefund = vars.SstoreClearsScheduleRefundEIP3529 // 4800
+	}
 
 	if hasEIP2200 {
 		resetClearRefund = vars.SstoreSetGasEIP2200 - vars.SloadGasEIP2200 // 19200
@@ -180,16 +185,16 @@ func (host *hostContext) SetStorage(evmcAddr evmc.Address, evmcKey evmc.Hash, ev
 			return evmc.StorageAdded
 		}
 		if value.IsZero() { // delete slot (2.1.2b)
-			host.env.StateDB.AddRefund(vars.NetSstoreClearRefund)
+			host.env.StateDB.AddRefund(clearRefund)
 			return evmc.StorageDeleted
 		}
 		return evmc.StorageModified
 	}
 	if !original.IsZero() {
 		if current.IsZero() { // recreate slot (2.2.1.1)
-			host.env.StateDB.SubRefund(vars.NetSstoreClearRefund)
+			host.env.StateDB.SubRefund(clearRefund)
 		} else if value.IsZero() { // delete slot (2.2.1.2)
-			host.env.StateDB.AddRefund(vars.NetSstoreClearRefund)
+			host.env.StateDB.AddRefund(clearRefund)
 		}
 	}
 	if original.Eq(value) {
@@ -226,7 +231,8 @@ func (host *hostContext) Selfdestruct(evmcAddr evmc.Address, evmcBeneficiary evm
 	addr := common.Address(evmcAddr)
 	beneficiary := common.Address(evmcBeneficiary)
 	db := host.env.StateDB
-	if !db.HasSuicided(addr) {
+	// EIP-3529 removes the selfdestruct refund.
+	if !db.HasSuicided(addr) && !host.env.ChainConfig().IsEnabled(host.env.ChainConfig().GetEIP3529Transition, host.env.Context.BlockNumber) {
 		db.AddRefund(vars.SelfdestructRefundGas)
 	}
 	db.AddBalance(beneficiary, db.GetBalance(addr))
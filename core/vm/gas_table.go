@@ -94,6 +94,12 @@ var (
 )
 
 func gasSStore(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	return gasSStoreLegacy(evm, contract, stack, vars.SstoreRefundGas, vars.NetSstoreClearRefund)
+}
+
+// gasSStoreLegacy implements the SSTORE gas cost of the legacy and EIP-1283
+// gas metering, parameterized by the refunds granted for clearing a slot.
+func gasSStoreLegacy(evm *EVM, contract *Contract, stack *Stack, clearRefund, netClearRefund uint64) (uint64, error) {
 	var (
 		y, x    = stack.Back(1), stack.Back(0)
 		current = evm.StateDB.GetState(contract.Address(), x.Bytes32())
@@ -111,7 +117,7 @@ func gasSStore(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySi
 		case current == (common.Hash{}) && y.Sign() != 0: // 0 => non 0
 			return vars.SstoreSetGas, nil
 		case current != (common.Hash{}) && y.Sign() == 0: // non 0 => 0
-			evm.StateDB.AddRefund(vars.SstoreRefundGas)
+			evm.StateDB.AddRefund(clearRefund)
 			return vars.SstoreClearGas, nil
 		default: // non 0 => non 0 (or 0 => 0)
 			return vars.SstoreResetGas, nil
@@ -146,15 +152,15 @@ func gasSStore(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySi
 			return vars.NetSstoreInitGas, nil
 		}
 		if value == (common.Hash{}) { // delete slot (2.1.2b)
-			evm.StateDB.AddRefund(vars.NetSstoreClearRefund)
+			evm.StateDB.AddRefund(netClearRefund)
 		}
 		return vars.NetSstoreCleanGas, nil // write existing slot (2.1.2)
 	}
 	if original != (common.Hash{}) {
 		if current == (common.Hash{}) { // recreate slot (2.2.1.1)
-			evm.StateDB.SubRefund(vars.NetSstoreClearRefund)
+			evm.StateDB.SubRefund(netClearRefund)
 		} else if value == (common.Hash{}) { // delete slot (2.2.1.2)
-			evm.StateDB.AddRefund(vars.NetSstoreClearRefund)
+			evm.StateDB.AddRefund(netClearRefund)
 		}
 	}
 	if original == value {
@@ -181,6 +187,24 @@ func gasSStore(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySi
 //       2.2.2.1. If original value is 0, add SSTORE_SET_GAS - SLOAD_GAS to refund counter.
 //       2.2.2.2. Otherwise, add SSTORE_RESET_GAS - SLOAD_GAS gas to refund counter.
 func gasSStoreEIP2200(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	return gasSStoreNetMetered(evm, contract, stack, vars.SstoreClearsScheduleRefundEIP2200)
+}
+
+// gasSStoreEIP3529NoAccessList implements the SSTORE refund reduction of EIP-3529
+// for chains which don't enable the access lists of EIP-2929. Only the refund
+// for clearing a slot is reduced, on top of whichever gas metering (legacy,
+// EIP-1283 or EIP-2200) is in effect.
+func gasSStoreEIP3529NoAccessList(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	if evm.chainConfig.IsEnabled(evm.chainConfig.GetEIP2200Transition, evm.Context.BlockNumber) &&
+		!evm.chainConfig.IsEnabled(evm.chainConfig.GetEIP2200DisableTransition, evm.Context.BlockNumber) {
+		return gasSStoreNetMetered(evm, contract, stack, vars.SstoreClearsScheduleRefundEIP3529)
+	}
+	return gasSStoreLegacy(evm, contract, stack, vars.SstoreClearsScheduleRefundEIP3529, vars.SstoreClearsScheduleRefundEIP3529)
+}
+
+// gasSStoreNetMetered implements the EIP-2200 SSTORE gas cost, parameterized by
+// the refund granted for clearing a slot.
+func gasSStoreNetMetered(evm *EVM, contract *Contract, stack *Stack, clearingRefund uint64) (uint64, error) {
 	// If we fail the minimum gas availability invariant, fail (0)
 	if contract.Gas <= vars.SstoreSentryGasEIP2200 {
 		return 0, errors.New("not enough gas for reentrancy sentry")
@@ -201,15 +225,15 @@ func gasSStoreEIP2200(evm *EVM, contract *Contract, stack *Stack, mem *Memory, m
 			return vars.SstoreSetGasEIP2200, nil
 		}
 		if value == (common.Hash{}) { // delete slot (2.1.2b)
-			evm.StateDB.AddRefund(vars.SstoreClearsScheduleRefundEIP2200)
+			evm.StateDB.AddRefund(clearingRefund)
 		}
 		return vars.SstoreResetGasEIP2200, nil // write existing slot (2.1.2)
 	}
 	if original != (common.Hash{}) {
 		if current == (common.Hash{}) { // recreate slot (2.2.1.1)
-			evm.StateDB.SubRefund(vars.SstoreClearsScheduleRefundEIP2200)
+			evm.StateDB.SubRefund(clearingRefund)
 		} else if value == (common.Hash{}) { // delete slot (2.2.1.2)
-			evm.StateDB.AddRefund(vars.SstoreClearsScheduleRefundEIP2200)
+			evm.StateDB.AddRefund(clearingRefund)
 		}
 	}
 	if original == value {
@@ -454,6 +478,22 @@ func gasStaticCall(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memo
 }
 
 func gasSelfdestruct(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	gas := gasSelfdestructNoRefund(evm, contract, stack)
+	if !evm.StateDB.HasSuicided(contract.Address()) {
+		evm.StateDB.AddRefund(vars.SelfdestructRefundGas)
+	}
+	return gas, nil
+}
+
+// gasSelfdestructEIP3529NoAccessList implements the SELFDESTRUCT refund removal
+// of EIP-3529, for chains which don't enable the access lists of EIP-2929.
+func gasSelfdestructEIP3529NoAccessList(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+	return gasSelfdestructNoRefund(evm, contract, stack), nil
+}
+
+// gasSelfdestructNoRefund returns the SELFDESTRUCT gas cost, without granting
+// the refund.
+func gasSelfdestructNoRefund(evm *EVM, contract *Contract, stack *Stack) uint64 {
 	var gas uint64
 	// EIP150 homestead gas reprice fork:
 	if evm.ChainConfig().IsEnabled(evm.chainConfig.GetEIP150Transition, evm.Context.BlockNumber) {
@@ -469,9 +509,5 @@ func gasSelfdestruct(evm *EVM, contract *Contract, stack *Stack, mem *Memory, me
 			gas += vars.CreateBySelfdestructGas
 		}
 	}
-
-	if !evm.StateDB.HasSuicided(contract.Address()) {
-		evm.StateDB.AddRefund(vars.SelfdestructRefundGas)
-	}
-	return gas, nil
+	return gas
 }
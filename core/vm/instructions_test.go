@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -650,3 +651,26 @@ func TestCreate2Addreses(t *testing.T) {
 		}
 	}
 }
+
+// TestOpBaseFee checks that BASEFEE pushes zero if EIP-3198 is enabled without
+// EIP-1559, leaving the base fee of the block context unset.
+func TestOpBaseFee(t *testing.T) {
+	for i, tt := range []struct {
+		baseFee *big.Int
+		want    uint64
+	}{
+		{nil, 0},
+		{big.NewInt(7), 7},
+	} {
+		var (
+			env            = NewEVM(BlockContext{BaseFee: tt.baseFee}, TxContext{}, nil, params.TestChainConfig, Config{})
+			stack          = newstack()
+			pc             = uint64(0)
+			evmInterpreter = env.interpreter.(*EVMInterpreter)
+		)
+		opBaseFee(&pc, evmInterpreter, &ScopeContext{nil, stack, nil})
+		if have := stack.pop(); have.Uint64() != tt.want {
+			t.Errorf("test %d: base fee mismatch: have %d, want %d", i, have.Uint64(), tt.want)
+		}
+	}
+}
@@ -33,7 +33,9 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
 	"github.com/ethereum/go-ethereum/params/types/goethereum"
+	"github.com/ethereum/go-ethereum/params/vars"
 )
 
 func TestDefaults(t *testing.T) {
@@ -101,6 +103,105 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestBaseFee(t *testing.T) {
+	ret, _, err := Execute([]byte{
+		byte(vm.BASEFEE),
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}, nil, &Config{BaseFee: big.NewInt(7)})
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+
+	num := new(big.Int).SetBytes(ret)
+	if num.Cmp(big.NewInt(7)) != 0 {
+		t.Error("Expected 7, got", num)
+	}
+}
+
+// TestEIP3541 checks that contracts starting with 0xEF are only rejected
+// once the EIP-3541 transition is enabled, independently of other London EIPs.
+func TestEIP3541(t *testing.T) {
+	// Init code deploying the single byte 0xEF.
+	initCode := []byte{
+		byte(vm.PUSH1), 0xEF,
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE8),
+		byte(vm.PUSH1), 1,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}
+	for i, tt := range []struct {
+		fork *big.Int
+		want error
+	}{
+		{nil, nil},
+		{big.NewInt(0), vm.ErrInvalidCode},
+	} {
+		cfg := &Config{
+			ChainConfig: &coregeth.CoreGethChainConfig{
+				ChainID:       big.NewInt(1),
+				EIP3198FBlock: big.NewInt(0),
+				EIP3529FBlock: big.NewInt(0),
+				EIP3541FBlock: tt.fork,
+			},
+		}
+		if _, _, _, err := Create(initCode, cfg); err != tt.want {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.want)
+		}
+	}
+}
+
+// TestEIP3529WithoutEIP2929 checks that the refund reductions of EIP-3529 apply
+// on top of the legacy, EIP-1283 and EIP-2200 gas metering when the access lists
+// of EIP-2929 aren't enabled, leaving the gas costs of those meterings untouched.
+func TestEIP3529WithoutEIP2929(t *testing.T) {
+	address := common.HexToAddress("0x0a")
+	configs := []*coregeth.CoreGethChainConfig{
+		{ChainID: big.NewInt(1), EIP3529FBlock: big.NewInt(0)},                               // legacy metering
+		{ChainID: big.NewInt(1), EIP1283FBlock: big.NewInt(0), EIP3529FBlock: big.NewInt(0)}, // EIP-1283 metering
+		{ChainID: big.NewInt(1), EIP2200FBlock: big.NewInt(0), EIP3529FBlock: big.NewInt(0)}, // EIP-2200 metering
+	}
+	for i, tt := range []struct {
+		code   []byte
+		gas    [3]uint64 // gas used per config
+		refund uint64
+	}{
+		{[]byte{byte(vm.PUSH1), 1, byte(vm.PUSH1), 1, byte(vm.SSTORE)}, [3]uint64{20006, 20006, 20006}, 0},                                   // set a slot
+		{[]byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.SSTORE)}, [3]uint64{5006, 5006, 5006}, vars.SstoreClearsScheduleRefundEIP3529}, // clear a slot
+		{[]byte{byte(vm.PUSH1), 1, byte(vm.PUSH1), 0, byte(vm.SSTORE)}, [3]uint64{5006, 206, 806}, 0},                                        // rewrite a slot
+		{[]byte{byte(vm.PUSH1), 0xff, byte(vm.SELFDESTRUCT)}, [3]uint64{3, 3, 3}, 0},                                                         // no selfdestruct refund
+	} {
+		for j, config := range configs {
+			db := state.NewDatabase(rawdb.NewMemoryDatabase())
+			statedb, _ := state.New(common.Hash{}, db, nil)
+			statedb.SetCode(address, tt.code)
+			statedb.SetState(address, common.Hash{}, common.BytesToHash([]byte{1}))
+			root, _ := statedb.Commit(false)
+			statedb, _ = state.New(root, db, nil)
+
+			cfg := &Config{
+				ChainConfig: config,
+				GasLimit:    100000,
+				State:       statedb,
+			}
+			_, left, err := Call(address, nil, cfg)
+			if err != nil {
+				t.Fatalf("test %d, config %d: call failed: %v", i, j, err)
+			}
+			if have := cfg.GasLimit - left; have != tt.gas[j] {
+				t.Errorf("test %d, config %d: gas mismatch: have %d, want %d", i, j, have, tt.gas[j])
+			}
+			if have := statedb.GetRefund(); have != tt.refund {
+				t.Errorf("test %d, config %d: refund mismatch: have %d, want %d", i, j, have, tt.refund)
+			}
+		}
+	}
+}
+
 func TestCall(t *testing.T) {
 	state, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
 	address := common.HexToAddress("0x0a")
@@ -559,6 +559,104 @@ func (api *PrivateDebugAPI) RemovePendingTransaction(hash common.Hash) (*types.T
 	return api.eth.txPool.RemoveTx(hash), nil
 }
 
+// MessEvaluationResult describes the outcome of an ECBP1100 (MESS) artificial finality evaluation.
+type MessEvaluationResult struct {
+	Status         string         `json:"status"` // "accepted" or "rejected"
+	Accepted       bool           `json:"accepted"`
+	Age            string         `json:"age"`
+	CurrentSpan    uint64         `json:"currentSpan"`  // Seconds between the common ancestor and the current head
+	ProposedSpan   uint64         `json:"proposedSpan"` // Seconds between the common ancestor and the proposed head
+	TDRatio        float64        `json:"tdRatio"`
+	AntiGravity    float64        `json:"antiGravity"`
+	PolynomialV    *hexutil.Big   `json:"polynomialV"`
+	GravityRatio   float64        `json:"tdrGravity"`
+	CommonNumber   hexutil.Uint64 `json:"commonNumber"`
+	CommonHash     common.Hash    `json:"commonHash"`
+	CurrentNumber  hexutil.Uint64 `json:"currentNumber"`
+	CurrentHash    common.Hash    `json:"currentHash"`
+	ProposedNumber hexutil.Uint64 `json:"proposedNumber"`
+	ProposedHash   common.Hash    `json:"proposedHash"`
+}
+
+func newMessEvaluationResult(eval *core.ECBP1100Evaluation) *MessEvaluationResult {
+	status := "rejected"
+	if eval.Accepted {
+		status = "accepted"
+	}
+	return &MessEvaluationResult{
+		Status:         status,
+		Accepted:       eval.Accepted,
+		Age:            common.PrettyAge(time.Unix(int64(eval.CommonAncestor.Time), 0)).String(),
+		CurrentSpan:    eval.Current.Time - eval.CommonAncestor.Time,
+		ProposedSpan:   eval.Proposed.Time - eval.CommonAncestor.Time,
+		TDRatio:        eval.TDRatio,
+		AntiGravity:    eval.AntiGravity,
+		PolynomialV:    (*hexutil.Big)(eval.PolynomialV),
+		GravityRatio:   eval.GravityRatio(),
+		CommonNumber:   hexutil.Uint64(eval.CommonAncestor.Number.Uint64()),
+		CommonHash:     eval.CommonAncestor.Hash(),
+		CurrentNumber:  hexutil.Uint64(eval.Current.Number.Uint64()),
+		CurrentHash:    eval.Current.Hash(),
+		ProposedNumber: hexutil.Uint64(eval.Proposed.Number.Uint64()),
+		ProposedHash:   eval.Proposed.Hash(),
+	}
+}
+
+// MessEvaluate evaluates the ECBP1100 (MESS) artificial finality condition for a hypothetical
+// reorganization from the current head to the proposed head, given their common ancestor.
+// The evaluation is made regardless of whether the feature is enabled or activated.
+func (api *PrivateDebugAPI) MessEvaluate(ctx context.Context, commonAncestor, current, proposed rpc.BlockNumberOrHash) (*MessEvaluationResult, error) {
+	var headers [3]*types.Header
+	names := [3]string{"common ancestor", "current", "proposed"}
+	for i, blockNrOrHash := range []rpc.BlockNumberOrHash{commonAncestor, current, proposed} {
+		header, err := api.eth.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			return nil, fmt.Errorf("%s block not found", names[i])
+		}
+		headers[i] = header
+	}
+	eval, err := api.eth.blockchain.ECBP1100Evaluate(headers[0], headers[1], headers[2])
+	if err != nil {
+		return nil, err
+	}
+	return newMessEvaluationResult(eval), nil
+}
+
+// MessRejections creates a subscription that is notified with the evaluation
+// of each chain segment rejected by the ECBP1100 (MESS) artificial finality mechanism.
+func (api *PrivateDebugAPI) MessRejections(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		rejections := make(chan core.ECBP1100RejectionEvent, 10)
+		sub := api.eth.blockchain.SubscribeECBP1100RejectionEvent(rejections)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-rejections:
+				notifier.Notify(rpcSub.ID, newMessEvaluationResult(ev.Evaluation))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // PrivateTraceAPI is the collection of Ethereum full node APIs exposed over
 // the private debugging endpoint.
 type PrivateTraceAPI struct {
@@ -135,6 +135,7 @@ type Config struct {
 	NoPrefetch bool // Whether to disable prefetching and only load state on demand
 
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	TraceIndex    bool   `toml:",omitempty"` // Whether to index the addresses touched by the calls of each block for trace_filter
 
 	// Whitelist of required block number -> hash values to accept
 	Whitelist map[uint64]common.Hash `toml:"-"`
@@ -226,7 +227,9 @@ func CreateConsensusEngine(stack *node.Node, chainConfig ctypes.ChainConfigurato
 		}, db)
 	}
 	if chainConfig.GetConsensusEngineType().IsLyra2() {
-		return lyra2.New(notify, noverify)
+		engine := lyra2.New(lyra2.Config{NotifyFull: config.NotifyFull}, notify, noverify)
+		engine.SetThreads(-1) // Disable CPU mining
+		return engine
 	}
 	// Otherwise assume proof-of-work
 	switch config.PowMode {
@@ -254,6 +257,7 @@ func CreateConsensusEngine(stack *node.Node, chainConfig ctypes.ChainConfigurato
 			DatasetsOnDisk:   config.DatasetsOnDisk,
 			DatasetsLockMmap: config.DatasetsLockMmap,
 			NotifyFull:       config.NotifyFull,
+			StratumAddr:      config.StratumAddr,
 			ECIP1099Block:    chainConfig.GetEthashECIP1099Transition(),
 		}, notify, noverify)
 		engine.SetThreads(-1) // Disable CPU mining
@@ -106,6 +106,7 @@ type EventSystem struct {
 	rmLogsCh      chan core.RemovedLogsEvent // Channel to receive removed log event
 	chainCh       chan core.ChainEvent       // Channel to receive new chain event
 	chainSideCh   chan core.ChainSideEvent   // Channel to receive new side chain event
+	quit          chan struct{}              // Channel closed to stop the event loop
 }
 
 // NewEventSystem creates a new manager that listens for event on the given mux,
@@ -126,6 +127,7 @@ func NewEventSystem(backend Backend, lightMode bool) *EventSystem {
 		pendingLogsCh: make(chan []*types.Log, logsChanSize),
 		chainCh:       make(chan core.ChainEvent, chainEvChanSize),
 		chainSideCh:   make(chan core.ChainSideEvent, chainEvChanSize),
+		quit:          make(chan struct{}),
 	}
 
 	// Subscribe events
@@ -145,6 +147,12 @@ func NewEventSystem(backend Backend, lightMode bool) *EventSystem {
 	return m
 }
 
+// Stop terminates the event loop, unsubscribing the system from the backend.
+// Subscriptions can't be created nor removed once the system is stopped.
+func (es *EventSystem) Stop() {
+	close(es.quit)
+}
+
 // Subscription is created when the client registers itself for a particular event.
 type Subscription struct {
 	ID        rpc.ID
@@ -537,6 +545,8 @@ func (es *EventSystem) eventLoop() {
 			close(f.err)
 
 		// System stopped
+		case <-es.quit:
+			return
 		case <-es.txsSub.Err():
 			return
 		case <-es.logsSub.Err():
@@ -0,0 +1,220 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxFeeHistory is the maximum number of blocks that can be retrieved for a
+	// fee history request.
+	maxFeeHistory = 1024
+
+	// feeCacheSize is the number of processed blocks kept in the cache shared by
+	// the fee history and the tip suggestions.
+	feeCacheSize = 2048
+)
+
+var (
+	errInvalidPercentile = errors.New("invalid reward percentile")
+	errRequestBeyondHead = errors.New("request beyond head block")
+)
+
+// txTip is the effective tip paid by a transaction, along with the gas it used.
+type txTip struct {
+	tip      *big.Int
+	gasUsed  uint64 // Gas used by the transaction, zero unless the receipts were loaded
+	excluded bool   // Whether the transaction is sent by the miner or an unknown sender
+}
+
+// blockFees is the fee data of a processed block. Once cached, it is never
+// modified, so it can be shared between requests.
+type blockFees struct {
+	baseFee      *big.Int // Base fee of the block, zero before EIP-1559
+	nextBaseFee  *big.Int // Base fee of the next block, zero before EIP-1559
+	gasUsed      uint64
+	gasUsedRatio float64
+	tips         []txTip // Transactions of the block, sorted by effective tip
+	receipts     bool    // Whether the gas used by the transactions is known
+}
+
+// blockFees retrieves the fee data of the canonical block with the given number
+// from the cache, processing the block if needed. The gas used by each
+// transaction is only looked up in the receipts if requested.
+func (gpo *Oracle) blockFees(ctx context.Context, number uint64, receipts bool) (*blockFees, error) {
+	header, err := gpo.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+	if header == nil {
+		return nil, err
+	}
+	if cached, ok := gpo.feeCache.Get(header.Hash()); ok {
+		if fees := cached.(*blockFees); fees.receipts || !receipts {
+			return fees, nil
+		}
+	}
+	block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if block == nil {
+		return nil, err
+	}
+	var (
+		config = gpo.backend.ChainConfig()
+		signer = types.MakeSigner(config, block.Number())
+		fees   = &blockFees{
+			baseFee:     new(big.Int),
+			nextBaseFee: new(big.Int),
+			gasUsed:     block.GasUsed(),
+			tips:        make([]txTip, len(block.Transactions())),
+			receipts:    receipts,
+		}
+	)
+	if baseFee := block.BaseFee(); baseFee != nil {
+		fees.baseFee.Set(baseFee)
+	}
+	if config.IsEnabled(config.GetEIP1559Transition, new(big.Int).Add(block.Number(), common.Big1)) {
+		fees.nextBaseFee = misc.CalcBaseFee(config, block.Header())
+	}
+	if block.GasLimit() > 0 {
+		fees.gasUsedRatio = float64(block.GasUsed()) / float64(block.GasLimit())
+	}
+	var blockReceipts types.Receipts
+	if receipts && len(fees.tips) > 0 {
+		if blockReceipts, err = gpo.backend.GetReceipts(ctx, block.Hash()); err != nil {
+			return nil, err
+		}
+		if len(blockReceipts) != len(fees.tips) {
+			return nil, fmt.Errorf("receipts of block #%d %x not found", number, block.Hash())
+		}
+	}
+	for i, tx := range block.Transactions() {
+		// It's okay to discard the error because a tx would never be
+		// accepted into a block with an invalid effective tip.
+		tip, _ := tx.EffectiveGasTip(block.BaseFee())
+		sender, err := types.Sender(signer, tx)
+
+		fees.tips[i] = txTip{tip: tip, excluded: err != nil || sender == block.Coinbase()}
+		if blockReceipts != nil {
+			fees.tips[i].gasUsed = blockReceipts[i].GasUsed
+		}
+	}
+	sort.SliceStable(fees.tips, func(i, j int) bool {
+		return fees.tips[i].tip.Cmp(fees.tips[j].tip) < 0
+	})
+	gpo.feeCache.Add(block.Hash(), fees)
+	return fees, nil
+}
+
+// rewards returns the effective tips paid at the given percentiles of the gas
+// used in the block, which must have been processed along with its receipts.
+func (fees *blockFees) rewards(percentiles []float64) []*big.Int {
+	rewards := make([]*big.Int, len(percentiles))
+	if len(fees.tips) == 0 {
+		// Return zero tips for empty blocks
+		for i := range rewards {
+			rewards[i] = new(big.Int)
+		}
+		return rewards
+	}
+	var (
+		index   int
+		gasUsed = fees.tips[0].gasUsed
+	)
+	for i, p := range percentiles {
+		threshold := uint64(float64(fees.gasUsed) * p / 100)
+		for gasUsed < threshold && index < len(fees.tips)-1 {
+			index++
+			gasUsed += fees.tips[index].gasUsed
+		}
+		rewards[i] = new(big.Int).Set(fees.tips[index].tip)
+	}
+	return rewards
+}
+
+// FeeHistory returns data relevant for fee estimation based on the specified
+// range of blocks, ending with lastBlock:
+// - the oldest block of the range
+// - the effective tips paid at the given percentiles of the gas used by the
+//   transactions of each block, if any percentiles are given
+// - the base fee of each block, and of the one following the range
+// - the ratio of gas used to the gas limit of each block
+// Base fees are zero for blocks before EIP-1559, so that the history is usable
+// on chains without a fee market too. Pending blocks are not supported, the
+// latest block is used instead.
+func (gpo *Oracle) FeeHistory(ctx context.Context, blocks int, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+	if blocks < 1 {
+		return common.Big0, nil, nil, nil, nil
+	}
+	if blocks > maxFeeHistory {
+		log.Warn("Sanitizing fee history length", "requested", blocks, "truncated", maxFeeHistory)
+		blocks = maxFeeHistory
+	}
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return common.Big0, nil, nil, nil, fmt.Errorf("%w: %f", errInvalidPercentile, p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return common.Big0, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", errInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+		}
+	}
+	head, err := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil {
+		return common.Big0, nil, nil, nil, err
+	}
+	last := head.Number.Uint64()
+	if lastBlock >= 0 {
+		if uint64(lastBlock) > last {
+			return common.Big0, nil, nil, nil, fmt.Errorf("%w: requested %d, head %d", errRequestBeyondHead, lastBlock, last)
+		}
+		last = uint64(lastBlock)
+	}
+	if uint64(blocks) > last+1 {
+		blocks = int(last + 1)
+	}
+	var (
+		oldest       = last + 1 - uint64(blocks)
+		reward       [][]*big.Int
+		baseFee      = make([]*big.Int, blocks+1)
+		gasUsedRatio = make([]float64, blocks)
+	)
+	if len(rewardPercentiles) > 0 {
+		reward = make([][]*big.Int, blocks)
+	}
+	for i := 0; i < blocks; i++ {
+		fees, err := gpo.blockFees(ctx, oldest+uint64(i), len(rewardPercentiles) > 0)
+		if err != nil {
+			return common.Big0, nil, nil, nil, err
+		}
+		if fees == nil {
+			return common.Big0, nil, nil, nil, fmt.Errorf("block #%d not found", oldest+uint64(i))
+		}
+		baseFee[i], baseFee[i+1] = new(big.Int).Set(fees.baseFee), new(big.Int).Set(fees.nextBaseFee)
+		gasUsedRatio[i] = fees.gasUsedRatio
+		if reward != nil {
+			reward[i] = fees.rewards(rewardPercentiles)
+		}
+	}
+	return new(big.Int).SetUint64(oldest), reward, baseFee, gasUsedRatio, nil
+}
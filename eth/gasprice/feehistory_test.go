@@ -0,0 +1,96 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/params/vars"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestFeeHistory(t *testing.T) {
+	var cases = []struct {
+		count       int
+		last        rpc.BlockNumber
+		percent     []float64
+		expFirst    uint64
+		expCount    int
+		expRewarded bool
+		expErr      error
+	}{
+		{count: 0, last: rpc.LatestBlockNumber, percent: nil, expFirst: 0, expCount: 0},
+		{count: 4, last: rpc.LatestBlockNumber, percent: nil, expFirst: 29, expCount: 4},
+		{count: 4, last: rpc.PendingBlockNumber, percent: []float64{0, 50, 100}, expFirst: 29, expCount: 4, expRewarded: true},
+		{count: 10, last: 20, percent: []float64{25}, expFirst: 11, expCount: 10, expRewarded: true},
+		{count: 100, last: 5, percent: []float64{50}, expFirst: 0, expCount: 6, expRewarded: true},
+		{count: 2, last: 33, percent: nil, expErr: errRequestBeyondHead},
+		{count: 2, last: rpc.LatestBlockNumber, percent: []float64{101}, expErr: errInvalidPercentile},
+		{count: 2, last: rpc.LatestBlockNumber, percent: []float64{50, 25}, expErr: errInvalidPercentile},
+	}
+	backend := newTestBackend(t)
+	oracle := NewOracle(backend, Config{Blocks: 20, Percentile: 60})
+
+	for i, c := range cases {
+		first, reward, baseFee, ratio, err := oracle.FeeHistory(context.Background(), c.count, c.last, c.percent)
+		if c.expErr != nil {
+			if !errors.Is(err, c.expErr) {
+				t.Errorf("test %d: error mismatch: have %v, want %v", i, err, c.expErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test %d: failed to retrieve fee history: %v", i, err)
+		}
+		if first.Uint64() != c.expFirst {
+			t.Errorf("test %d: first block mismatch: have %d, want %d", i, first, c.expFirst)
+		}
+		if len(ratio) != c.expCount {
+			t.Errorf("test %d: gas used ratio count mismatch: have %d, want %d", i, len(ratio), c.expCount)
+		}
+		if c.expCount > 0 && len(baseFee) != c.expCount+1 {
+			t.Errorf("test %d: base fee count mismatch: have %d, want %d", i, len(baseFee), c.expCount+1)
+		}
+		if (reward != nil) != c.expRewarded {
+			t.Errorf("test %d: reward presence mismatch: have %v, want %v", i, reward != nil, c.expRewarded)
+		}
+		// The chain has no fee market, so the tips are the full gas prices
+		for _, fee := range baseFee {
+			if fee.Sign() != 0 {
+				t.Errorf("test %d: non-zero base fee %d", i, fee)
+			}
+		}
+		for j := range reward {
+			number := c.expFirst + uint64(j)
+			for k, tip := range reward[j] {
+				want := new(big.Int)
+				if number > 0 {
+					want.SetUint64(number * vars.GWei)
+				}
+				if tip.Cmp(want) != 0 {
+					t.Errorf("test %d, block %d, percentile %v: reward mismatch: have %d, want %d", i, number, c.percent[k], tip, want)
+				}
+			}
+			if number > 0 && ratio[j] == 0 {
+				t.Errorf("test %d, block %d: zero gas used ratio", i, number)
+			}
+		}
+	}
+}
@@ -51,6 +51,10 @@ func handleGetBlockHeaders66(backend Backend, msg Decoder, peer *Peer) error {
 
 func answerGetBlockHeadersQuery(backend Backend, query *GetBlockHeadersPacket, peer *Peer) []*types.Header {
 	hashMode := query.Origin.Hash != (common.Hash{})
+	if !hashMode && query.Skip == 0 {
+		// Number based queries for consecutive headers are served in bulk
+		return answerGetContiguousHeadersQuery(backend, query)
+	}
 	first := true
 	maxNonCanonical := uint64(100)
 
@@ -135,6 +139,44 @@ func answerGetBlockHeadersQuery(backend Backend, query *GetBlockHeadersPacket, p
 	return headers
 }
 
+// answerGetContiguousHeadersQuery answers a number based query without skips,
+// reading the consecutive canonical headers from the database at once.
+func answerGetContiguousHeadersQuery(backend Backend, query *GetBlockHeadersPacket) []*types.Header {
+	count := query.Amount
+	if count > maxHeadersServe {
+		count = maxHeadersServe
+	}
+	if limit := uint64(softResponseLimit / estHeaderSize); count > limit {
+		count = limit
+	}
+	from := query.Origin.Number
+	if query.Reverse {
+		// Number based traversal towards the genesis block
+		if count > from {
+			count = from + 1
+		}
+		from -= count - 1
+	}
+	raw := backend.Chain().GetHeaderRangeRLP(from, count)
+	if query.Reverse && uint64(len(raw)) < count {
+		// The origin itself is missing
+		return nil
+	}
+	headers := make([]*types.Header, 0, len(raw))
+	for i := range raw {
+		if query.Reverse {
+			i = len(raw) - 1 - i
+		}
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(raw[i], header); err != nil {
+			log.Error("Invalid header RLP", "number", from+uint64(i), "err", err)
+			break
+		}
+		headers = append(headers, header)
+	}
+	return headers
+}
+
 func handleGetBlockBodies(backend Backend, msg Decoder, peer *Peer) error {
 	// Decode the block body retrieval message
 	var query GetBlockBodiesPacket
@@ -107,6 +107,34 @@ func (context *chainContext) GetHeader(hash common.Hash, number uint64) *types.H
 	return header
 }
 
+func (context *chainContext) Config() ctypes.ChainConfigurator {
+	return context.api.backend.ChainConfig()
+}
+
+func (context *chainContext) CurrentHeader() *types.Header {
+	header, err := context.api.backend.HeaderByNumber(context.ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
+func (context *chainContext) GetHeaderByNumber(number uint64) *types.Header {
+	header, err := context.api.backend.HeaderByNumber(context.ctx, rpc.BlockNumber(number))
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
+func (context *chainContext) GetHeaderByHash(hash common.Hash) *types.Header {
+	header, err := context.api.backend.HeaderByHash(context.ctx, hash)
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
 // chainContext construts the context reader which is used by the evm for reading
 // the necessary chain context.
 func (api *API) chainContext(ctx context.Context) core.ChainContext {
@@ -164,8 +192,8 @@ type TraceConfig struct {
 	NestedTraceOutput bool // Returns the trace output JSON nested under the trace name key. This allows full Parity compatibility to be achieved.
 }
 
-// TraceCallConfig is the config for traceCall API. It holds one more
-// field to override the state for tracing.
+// TraceCallConfig is the config for traceCall API. It holds two more
+// fields to override the state and the block context for tracing.
 type TraceCallConfig struct {
 	*vm.LogConfig
 	Tracer            *string
@@ -173,6 +201,15 @@ type TraceCallConfig struct {
 	Reexec            *uint64
 	NestedTraceOutput bool // Returns the trace output JSON nested under the trace name key. This allows full Parity compatibility to be achieved.
 	StateOverrides    *ethapi.StateOverride
+	BlockOverrides    *ethapi.BlockOverrides
+}
+
+// TraceCallManyArgs is a single call of a traceCallMany request. The state
+// overrides are applied right before the call, on top of the effects of the
+// previous calls.
+type TraceCallManyArgs struct {
+	ethapi.CallArgs
+	StateOverrides *ethapi.StateOverride `json:"stateOverrides"`
 }
 
 // StdTraceConfig holds extra parameters to standard-json trace functions.
@@ -235,7 +272,15 @@ func getTraceConfigFromTraceCallConfig(config *TraceCallConfig) *TraceConfig {
 
 // TraceChain returns the structured logs created during the execution of EVM
 // between two blocks (excluding start) and returns them as a JSON object.
-func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig) (*rpc.Subscription, error) { // Fetch the block interval that we want to trace
+func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig) (*rpc.Subscription, error) {
+	return api.traceChainByNumber(ctx, start, end, config, nil, nil)
+}
+
+// traceChainByNumber traces the blocks between two block numbers (excluding start),
+// skipping the blocks not matched by the optional matcher and filtering the
+// results of the others with the optional filter.
+func (api *API) traceChainByNumber(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig, matches func(number uint64) bool, filter func([]*txTraceResult) []*txTraceResult) (*rpc.Subscription, error) {
+	// Fetch the block interval that we want to trace
 	from, err := api.blockByNumber(ctx, start)
 	if err != nil {
 		return nil, err
@@ -247,13 +292,15 @@ func (api *API) TraceChain(ctx context.Context, start, end rpc.BlockNumber, conf
 	if from.Number().Cmp(to.Number()) >= 0 {
 		return nil, fmt.Errorf("end block (#%d) needs to come after start block (#%d)", end, start)
 	}
-	return api.traceChain(ctx, from, to, config)
+	return api.traceChain(ctx, from, to, config, matches, filter)
 }
 
 // traceChain configures a new tracer according to the provided configuration, and
 // executes all the transactions contained within. The return value will be one item
-// per transaction, dependent on the requested tracer.
-func (api *API) traceChain(ctx context.Context, start, end *types.Block, config *TraceConfig) (*rpc.Subscription, error) {
+// per transaction, dependent on the requested tracer. Blocks not matched by the
+// optional matcher are executed without being traced, and the results of the
+// traced blocks are passed through the optional filter.
+func (api *API) traceChain(ctx context.Context, start, end *types.Block, config *TraceConfig, matches func(number uint64) bool, filter func([]*txTraceResult) []*txTraceResult) (*rpc.Subscription, error) {
 	// Tracing a chain is a **long** operation, only do with subscriptions
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -289,7 +336,7 @@ func (api *API) traceChain(ctx context.Context, start, end *types.Block, config
 				blockCtx := core.NewEVMBlockContext(task.block.Header(), api.chainContext(localctx), nil)
 				// Trace all the transactions contained within
 				for i, tx := range task.block.Transactions() {
-					msg, _ := tx.AsMessage(signer)
+					msg, _ := tx.AsMessage(signer, task.block.BaseFee())
 					txctx := &txTraceContext{
 						index: i,
 						hash:  tx.Hash(),
@@ -305,6 +352,9 @@ func (api *API) traceChain(ctx context.Context, start, end *types.Block, config
 					task.statedb.Finalise(api.backend.ChainConfig().IsEnabled(api.backend.ChainConfig().GetEIP161dTransition, task.block.Number()))
 					task.results[i] = &txTraceResult{Result: res}
 				}
+				if filter != nil {
+					task.results = filter(task.results)
+				}
 				// Stream the result back to the user or abort on teardown
 				select {
 				case results <- task:
@@ -383,6 +433,16 @@ func (api *API) traceChain(ctx context.Context, start, end *types.Block, config
 				failed = err
 				break
 			}
+			// Skip tracing the blocks which can't match, the state being carried
+			// forward through them nonetheless
+			if matches != nil && !matches(next.NumberU64()) {
+				select {
+				case results <- &blockTraceTask{block: next, results: []*txTraceResult{}}:
+				case <-notifier.Closed():
+					return
+				}
+				continue
+			}
 			// Send the block over to the concurrent tracers (if not in the fast-forward phase)
 			txs := next.Transactions()
 			select {
@@ -410,7 +470,7 @@ func (api *API) traceChain(ctx context.Context, start, end *types.Block, config
 			done[uint64(result.Block)] = result
 
 			// Dereference any parent tries held in memory by this task
-			if res.statedb.Database().TrieDB() != nil {
+			if res.statedb != nil && res.statedb.Database().TrieDB() != nil {
 				res.statedb.Database().TrieDB().Dereference(res.rootref)
 			}
 			// Stream completed traces to the user, aborting on the first error
@@ -541,7 +601,7 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 			defer pend.Done()
 			// Fetch and execute the next transaction trace tasks
 			for task := range jobs {
-				msg, _ := txs[task.index].AsMessage(signer)
+				msg, _ := txs[task.index].AsMessage(signer, block.BaseFee())
 				txctx := &txTraceContext{
 					index: task.index,
 					hash:  txs[task.index].Hash(),
@@ -563,7 +623,7 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		jobs <- &txTraceTask{statedb: statedb.Copy(), index: i}
 
 		// Generate the next state snapshot fast without tracing
-		msg, _ := tx.AsMessage(signer)
+		msg, _ := tx.AsMessage(signer, block.BaseFee())
 		statedb.Prepare(tx.Hash(), block.Hash(), i)
 		vmenv := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), statedb, api.backend.ChainConfig(), vm.Config{})
 		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
@@ -660,7 +720,7 @@ func (api *API) standardTraceBlockToFile(ctx context.Context, block *types.Block
 	for i, tx := range block.Transactions() {
 		// Prepare the trasaction for un-traced execution
 		var (
-			msg, _    = tx.AsMessage(signer)
+			msg, _    = tx.AsMessage(signer, block.BaseFee())
 			txContext = core.NewEVMTxContext(msg)
 			vmConf    vm.Config
 			dump      *os.File
@@ -792,8 +852,14 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.CallArgs, blockNrOrHa
 		}
 	}
 	// Execute the trace
-	msg := args.ToMessage(api.backend.RPCGasCap())
 	vmctx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+	if config != nil {
+		config.BlockOverrides.Apply(&vmctx, api.backend.ChainConfig())
+	}
+	msg, err := args.ToMessage(api.backend.RPCGasCap(), vmctx.BaseFee)
+	if err != nil {
+		return nil, err
+	}
 
 	originalCanTransfer := vmctx.CanTransfer
 	originalTransfer := vmctx.Transfer
@@ -840,7 +906,7 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.CallArgs, blockNrOrHa
 // TraceCallMany lets you trace a given eth_call. It collects the structured logs created during the execution of EVM
 // if the given transaction was added on top of the provided block and returns them as a JSON object.
 // You can provide -2 as a block number to trace on top of the pending block.
-func (api *API) TraceCallMany(ctx context.Context, txs []ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
+func (api *API) TraceCallMany(ctx context.Context, txs []TraceCallManyArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
 	// Try to retrieve the specified block
 	var (
 		err   error
@@ -876,9 +942,19 @@ func (api *API) TraceCallMany(ctx context.Context, txs []ethapi.CallArgs, blockN
 
 	var results = make([]interface{}, len(txs))
 	for idx, args := range txs {
+		// Apply the state overrides of the call on top of the previous calls
+		if err := args.StateOverrides.Apply(statedb); err != nil {
+			return nil, fmt.Errorf("failed to override state for transaction at index %d with error %v", idx, err)
+		}
 		// Execute the trace
-		msg := args.ToMessage(api.backend.RPCGasCap())
 		vmctx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+		if config != nil {
+			config.BlockOverrides.Apply(&vmctx, api.backend.ChainConfig())
+		}
+		msg, err := args.ToMessage(api.backend.RPCGasCap(), vmctx.BaseFee)
+		if err != nil {
+			return nil, err
+		}
 
 		originalCanTransfer := vmctx.CanTransfer
 		originalTransfer := vmctx.Transfer
@@ -938,49 +1014,71 @@ func (api *API) TraceCallMany(ctx context.Context, txs []ethapi.CallArgs, blockN
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
 func (api *API) traceTx(ctx context.Context, message core.Message, txctx *txTraceContext, vmctx vm.BlockContext, statedb *state.StateDB, extraContext map[string]interface{}, config *TraceConfig) (interface{}, error) {
-	// Assemble the structured logger or the JavaScript tracer
-	var (
-		tracer    vm.Tracer
-		err       error
-		txContext = core.NewEVMTxContext(message)
-	)
+	tracer, err := newTxTracer(config, core.NewEVMTxContext(message))
+	if err != nil {
+		return nil, err
+	}
+	return api.traceTxWithTracer(ctx, tracer, message, txctx, vmctx, statedb, extraContext, config)
+}
+
+// newTxTracer assembles the structured logger or the JavaScript tracer
+// requested by the provided configuration.
+func newTxTracer(config *TraceConfig, txContext vm.TxContext) (vm.Tracer, error) {
 	switch {
 	case config != nil && config.Tracer != nil:
+		// Construct the native or JavaScript tracer to execute with
+		return newTracer(*config.Tracer, txContext)
+
+	case config == nil:
+		return vm.NewStructLogger(nil), nil
+
+	default:
+		return vm.NewStructLogger(config.LogConfig), nil
+	}
+}
+
+// traceTxWithTracer executes the given message in the provided environment with
+// the given tracer. The return value will be tracer dependent.
+func (api *API) traceTxWithTracer(ctx context.Context, tracer vm.Tracer, message core.Message, txctx *txTraceContext, vmctx vm.BlockContext, statedb *state.StateDB, extraContext map[string]interface{}, config *TraceConfig) (interface{}, error) {
+	result, err := api.applyTxWithTracer(ctx, tracer, message, txctx, vmctx, statedb, extraContext, config, new(core.GasPool).AddGas(message.Gas()))
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %w", err)
+	}
+	return traceTxResult(tracer, result)
+}
+
+// applyTxWithTracer executes the given message in the provided environment,
+// drawing its gas from the given pool. The message is traced with the given
+// tracer, unless it is nil.
+func (api *API) applyTxWithTracer(ctx context.Context, tracer vm.Tracer, message core.Message, txctx *txTraceContext, vmctx vm.BlockContext, statedb *state.StateDB, extraContext map[string]interface{}, config *TraceConfig, gp *core.GasPool) (*core.ExecutionResult, error) {
+	txContext := core.NewEVMTxContext(message)
+	if resultTracer, ok := tracer.(ResultTracer); ok {
 		// Define a meaningful timeout of a single transaction trace
 		timeout := defaultTraceTimeout
-		if config.Timeout != nil {
+		if config != nil && config.Timeout != nil {
+			var err error
 			if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
 				return nil, err
 			}
 		}
-		// Constuct the JavaScript tracer to execute with
-		if tracer, err = New(*config.Tracer, txContext); err != nil {
-			return nil, err
-		}
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
 			if deadlineCtx.Err() == context.DeadlineExceeded {
-				tracer.(*Tracer).Stop(errors.New("execution timeout"))
+				resultTracer.Stop(errors.New("execution timeout"))
 			}
 		}()
 		defer cancel()
-
-	case config == nil:
-		tracer = vm.NewStructLogger(nil)
-
-	default:
-		tracer = vm.NewStructLogger(config.LogConfig)
 	}
 	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Debug: true, Tracer: tracer})
+	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Debug: tracer != nil, Tracer: tracer, NoBaseFee: true})
 
 	// Call Prepare to clear out the statedb access list
 	statedb.Prepare(txctx.hash, txctx.block, txctx.index)
 
 	switch tracer := tracer.(type) {
-	case *Tracer:
+	case ResultTracer:
 		if extraContext == nil {
 			extraContext = map[string]interface{}{}
 		}
@@ -1006,11 +1104,12 @@ func (api *API) traceTx(ctx context.Context, message core.Message, txctx *txTrac
 		tracer.CapturePreEVM(vmenv, extraContext)
 	}
 
-	result, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
-	if err != nil {
-		return nil, fmt.Errorf("tracing failed: %w", err)
-	}
+	return core.ApplyMessage(vmenv, message, gp)
+}
 
+// traceTxResult formats the output of the tracer which traced the execution
+// with the given result.
+func traceTxResult(tracer vm.Tracer, result *core.ExecutionResult) (interface{}, error) {
 	// Depending on the tracer type, format and return the output.
 	switch tracer := tracer.(type) {
 	case *vm.StructLogger:
@@ -1026,7 +1125,7 @@ func (api *API) traceTx(ctx context.Context, message core.Message, txctx *txTrac
 			StructLogs:  ethapi.FormatLogs(tracer.StructLogs()),
 		}, nil
 
-	case *Tracer:
+	case ResultTracer:
 		return tracer.GetResult()
 
 	default:
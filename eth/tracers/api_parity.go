@@ -19,10 +19,15 @@ package tracers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/params/mutations"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -52,6 +57,17 @@ type ParityTrace struct {
 	Type                string            `json:"type"`
 }
 
+// ParityTraceResults is the result of replaying a transaction with the requested
+// Parity trace types, as returned by the trace_replay* methods.
+// The results of the trace types which weren't requested are empty.
+type ParityTraceResults struct {
+	Output          hexutil.Bytes   `json:"output"`
+	StateDiff       json.RawMessage `json:"stateDiff"`
+	Trace           json.RawMessage `json:"trace"`
+	TransactionHash *common.Hash    `json:"transactionHash,omitempty"`
+	VMTrace         json.RawMessage `json:"vmTrace"`
+}
+
 // TraceRewardAction An Parity formatted trace reward action
 type TraceRewardAction struct {
 	Value      *hexutil.Big    `json:"value,omitempty"`
@@ -128,6 +144,8 @@ func decorateNestedTraceResponse(res interface{}, tracer string) interface{} {
 		out["trace"] = res
 	} else if tracer == "stateDiffTracer" {
 		out["stateDiff"] = res
+	} else if tracer == "vmTracerParity" {
+		out["vmTrace"] = res
 	} else {
 		return res
 	}
@@ -245,7 +263,117 @@ func (api *TraceAPI) Filter(ctx context.Context, args TraceFilterArgs, config *T
 	start := rpc.BlockNumber(args.FromBlock)
 	end := rpc.BlockNumber(args.ToBlock)
 
-	return api.debugAPI.TraceChain(ctx, start, end, config)
+	return api.debugAPI.traceChainByNumber(ctx, start, end, config, api.traceIndexMatcher(args), traceAddressFilter(args))
+}
+
+// parityTraceAddresses holds the addresses of a Parity trace which trace_filter
+// matches against.
+type parityTraceAddresses struct {
+	Type   string `json:"type"`
+	Action struct {
+		From          *common.Address `json:"from"`
+		To            *common.Address `json:"to"`
+		Address       *common.Address `json:"address"`
+		RefundAddress *common.Address `json:"refundAddress"`
+		Author        *common.Address `json:"author"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+	} `json:"result"`
+}
+
+// matches reports whether the trace is sent from and to the filtered addresses,
+// the same way as OpenEthereum: the sender of a self destruct is the destructed
+// contract, the recipient of a creation is the created contract, and rewards
+// have no sender.
+func (t *parityTraceAddresses) matches(from, to *common.Address) bool {
+	match := func(filter, addr *common.Address) bool {
+		return filter == nil || (addr != nil && *addr == *filter)
+	}
+	switch t.Type {
+	case "create":
+		var created *common.Address
+		if t.Result != nil {
+			created = t.Result.Address
+		}
+		return match(from, t.Action.From) && match(to, created)
+	case "suicide":
+		return match(from, t.Action.Address) && match(to, t.Action.RefundAddress)
+	case "reward":
+		return from == nil && match(to, t.Action.Author)
+	default:
+		return match(from, t.Action.From) && match(to, t.Action.To)
+	}
+}
+
+// traceAddressFilter returns a filter dropping the traces which aren't sent from
+// and to the filtered addresses, along with the transactions left without any,
+// or nil if no addresses are filtered. Results which aren't lists of Parity
+// traces are left untouched.
+func traceAddressFilter(args TraceFilterArgs) func([]*txTraceResult) []*txTraceResult {
+	if args.FromAddress == nil && args.ToAddress == nil {
+		return nil
+	}
+	return func(results []*txTraceResult) []*txTraceResult {
+		filtered := make([]*txTraceResult, 0, len(results))
+		for _, result := range results {
+			if result == nil || result.Error != "" {
+				filtered = append(filtered, result)
+				continue
+			}
+			blob, err := json.Marshal(result.Result)
+			if err != nil {
+				filtered = append(filtered, result)
+				continue
+			}
+			var traces []json.RawMessage
+			if err := json.Unmarshal(blob, &traces); err != nil {
+				filtered = append(filtered, result)
+				continue
+			}
+			var matched []json.RawMessage
+			for _, trace := range traces {
+				var addrs parityTraceAddresses
+				if err := json.Unmarshal(trace, &addrs); err != nil || addrs.matches(args.FromAddress, args.ToAddress) {
+					matched = append(matched, trace)
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+			blob, _ = json.Marshal(matched)
+			filtered = append(filtered, &txTraceResult{Result: json.RawMessage(blob)})
+		}
+		return filtered
+	}
+}
+
+// traceIndexMatcher returns a matcher of the blocks which may touch the
+// filtered addresses according to the trace index, or nil if no addresses are
+// filtered or the backend doesn't maintain a trace index.
+func (api *TraceAPI) traceIndexMatcher(args TraceFilterArgs) func(number uint64) bool {
+	indexed, ok := api.debugAPI.backend.(TraceIndexBackend)
+	if !ok {
+		return nil
+	}
+	var addresses []common.Address
+	if args.FromAddress != nil {
+		addresses = append(addresses, *args.FromAddress)
+	}
+	if args.ToAddress != nil {
+		addresses = append(addresses, *args.ToAddress)
+	}
+	size, sections := indexed.TraceIndexStatus()
+	if len(addresses) == 0 || sections == 0 {
+		return nil
+	}
+	matcher := &traceIndexMatcher{
+		db:        api.debugAPI.backend.ChainDb(),
+		size:      size,
+		sections:  sections,
+		addresses: addresses,
+	}
+	return matcher.matches
 }
 
 // Call lets you trace a given eth_call. It collects the structured logs created during the execution of EVM
@@ -264,7 +392,175 @@ func (api *TraceAPI) Call(ctx context.Context, args ethapi.CallArgs, blockNrOrHa
 // CallMany lets you trace a given eth_call. It collects the structured logs created during the execution of EVM
 // if the given transaction was added on top of the provided block and returns them as a JSON object.
 // You can provide -2 as a block number to trace on top of the pending block.
-func (api *TraceAPI) CallMany(ctx context.Context, txs []ethapi.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
+func (api *TraceAPI) CallMany(ctx context.Context, txs []TraceCallManyArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
 	config = setTraceCallConfigDefaultTracer(config)
 	return api.debugAPI.TraceCallMany(ctx, txs, blockNrOrHash, config)
 }
+
+// stateDiffContext returns the extra context needed by the state diff tracer
+// to execute the message on top of the given state.
+func stateDiffContext(vmctx vm.BlockContext, statedb *state.StateDB, msg core.Message) map[string]interface{} {
+	gasCost := new(big.Int).Mul(new(big.Int).SetUint64(msg.Gas()), msg.GasPrice())
+	totalCost := new(big.Int).Add(gasCost, msg.Value())
+	return map[string]interface{}{
+		"hasFromSufficientBalanceForValueAndGasCost": vmctx.CanTransfer(statedb, msg.From(), totalCost),
+		"hasFromSufficientBalanceForGasCost":         vmctx.CanTransfer(statedb, msg.From(), gasCost),
+	}
+}
+
+// replayTx executes the message on top of the given state, producing the
+// requested Parity trace types.
+func (api *TraceAPI) replayTx(ctx context.Context, msg core.Message, txctx *txTraceContext, vmctx vm.BlockContext, statedb *state.StateDB, traceTypes []string) (*ParityTraceResults, error) {
+	tracer, err := newParityReplayTracer(traceTypes)
+	if err != nil {
+		return nil, err
+	}
+	res, err := api.debugAPI.traceTxWithTracer(ctx, tracer, msg, txctx, vmctx, statedb, stateDiffContext(vmctx, statedb, msg), nil)
+	if err != nil {
+		return nil, err
+	}
+	results := new(ParityTraceResults)
+	if err := json.Unmarshal(res.(json.RawMessage), results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ReplayTransaction replays the transaction with the given hash, returning
+// the requested trace types of "trace", "vmTrace" and "stateDiff".
+func (api *TraceAPI) ReplayTransaction(ctx context.Context, hash common.Hash, traceTypes []string) (*ParityTraceResults, error) {
+	_, blockHash, blockNumber, index, err := api.debugAPI.backend.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	// It shouldn't happen in practice.
+	if blockNumber == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	block, err := api.debugAPI.blockByNumberAndHash(ctx, rpc.BlockNumber(blockNumber), blockHash)
+	if err != nil {
+		return nil, err
+	}
+	msg, vmctx, statedb, err := api.debugAPI.backend.StateAtTransaction(ctx, block, int(index), defaultTraceReexec)
+	if err != nil {
+		return nil, err
+	}
+	txctx := &txTraceContext{
+		index: int(index),
+		hash:  hash,
+		block: blockHash,
+	}
+	return api.replayTx(ctx, msg, txctx, vmctx, statedb, traceTypes)
+}
+
+// ReplayBlockTransactions replays all the transactions of the given block,
+// returning the requested trace types of "trace", "vmTrace" and "stateDiff"
+// for each.
+func (api *TraceAPI) ReplayBlockTransactions(ctx context.Context, number rpc.BlockNumber, traceTypes []string) ([]*ParityTraceResults, error) {
+	block, err := api.debugAPI.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	parent, err := api.debugAPI.blockByNumberAndHash(ctx, rpc.BlockNumber(block.NumberU64()-1), block.ParentHash())
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := api.debugAPI.backend.StateAtBlock(ctx, parent, defaultTraceReexec, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		chainConfig = api.debugAPI.backend.ChainConfig()
+		signer      = types.MakeSigner(chainConfig, block.Number())
+		blockCtx    = core.NewEVMBlockContext(block.Header(), api.debugAPI.chainContext(ctx), nil)
+		txs         = block.Transactions()
+		results     = make([]*ParityTraceResults, len(txs))
+	)
+	for i, tx := range txs {
+		msg, err := tx.AsMessage(signer, block.BaseFee())
+		if err != nil {
+			return nil, err
+		}
+		txctx := &txTraceContext{
+			index: i,
+			hash:  tx.Hash(),
+			block: block.Hash(),
+		}
+		res, err := api.replayTx(ctx, msg, txctx, blockCtx, statedb, traceTypes)
+		if err != nil {
+			return nil, err
+		}
+		res.TransactionHash = &txctx.hash
+		results[i] = res
+
+		// Finalize the state so any modifications are written to the trie
+		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
+		statedb.Finalise(chainConfig.IsEnabled(chainConfig.GetEIP161dTransition, block.Number()))
+	}
+	return results, nil
+}
+
+// RawTransaction executes the signed transaction on top of the latest block
+// without broadcasting it, returning the requested trace types of "trace",
+// "vmTrace" and "stateDiff".
+func (api *TraceAPI) RawTransaction(ctx context.Context, input hexutil.Bytes, traceTypes []string) (*ParityTraceResults, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return nil, err
+	}
+	block, err := api.debugAPI.blockByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := api.debugAPI.backend.StateAtBlock(ctx, block, defaultTraceReexec, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := tx.AsMessage(types.MakeSigner(api.debugAPI.backend.ChainConfig(), block.Number()), block.BaseFee())
+	if err != nil {
+		return nil, err
+	}
+	vmctx := core.NewEVMBlockContext(block.Header(), api.debugAPI.chainContext(ctx), nil)
+	return api.replayTx(ctx, msg, &txTraceContext{hash: tx.Hash()}, vmctx, statedb, traceTypes)
+}
+
+// Get returns the call trace at the given trace address within the transaction
+// with the given hash, or nil if there is none.
+func (api *TraceAPI) Get(ctx context.Context, hash common.Hash, indices []hexutil.Uint64) (json.RawMessage, error) {
+	res, err := api.Transaction(ctx, hash, nil)
+	if err != nil {
+		return nil, err
+	}
+	var traces []json.RawMessage
+	if err := json.Unmarshal(res.(json.RawMessage), &traces); err != nil {
+		return nil, err
+	}
+	for _, trace := range traces {
+		var position struct {
+			TraceAddress []int `json:"traceAddress"`
+		}
+		if err := json.Unmarshal(trace, &position); err != nil {
+			return nil, err
+		}
+		if equalTraceAddress(position.TraceAddress, indices) {
+			return trace, nil
+		}
+	}
+	return nil, nil
+}
+
+// equalTraceAddress reports whether the trace address is at the given indices.
+func equalTraceAddress(address []int, indices []hexutil.Uint64) bool {
+	if len(address) != len(indices) {
+		return false
+	}
+	for i := range address {
+		if uint64(address[i]) != uint64(indices[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,7 +1,19 @@
 package tracers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/vars"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // BenchmarkTraceResultsAppend1 compares performance against BenchmarkTraceResultsAppend2,
@@ -39,3 +51,214 @@ func BenchmarkTraceResultsAppend2(b *testing.B) {
 		results = append(results, traceResults...) // nolint:ineffassign
 	}
 }
+
+// testReplayVMTrace is the decoded vmTrace of a replayed transaction.
+type testReplayVMTrace struct {
+	Code hexutil.Bytes `json:"code"`
+	Ops  []struct {
+		Cost uint64 `json:"cost"`
+		Ex   *struct {
+			Mem *struct {
+				Data hexutil.Bytes `json:"data"`
+				Off  uint64        `json:"off"`
+			} `json:"mem"`
+			Push  []*hexutil.Big `json:"push"`
+			Store *struct {
+				Key *hexutil.Big `json:"key"`
+				Val *hexutil.Big `json:"val"`
+			} `json:"store"`
+			Used uint64 `json:"used"`
+		} `json:"ex"`
+		Pc  uint64             `json:"pc"`
+		Sub *testReplayVMTrace `json:"sub"`
+	} `json:"ops"`
+}
+
+func TestTraceReplayTransaction(t *testing.T) {
+	t.Parallel()
+
+	// The callee stores 42 at slot 0 and returns the word 1, which the caller
+	// calls into without copying the return data.
+	var (
+		accounts = newAccounts(1)
+		callee   = common.HexToAddress("0xc0ffee")
+		caller   = common.HexToAddress("0xca11e5")
+
+		calleeCode = common.FromHex("602a60005560016000526020" + "6000f3")
+		callerCode = append(append(common.FromHex("60006000600060006000"+"73"), callee.Bytes()...), common.FromHex("5af100")...)
+	)
+	genesis := &genesisT.Genesis{Alloc: genesisT.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(vars.Ether)},
+		callee:           {Balance: big.NewInt(0), Code: calleeCode},
+		caller:           {Balance: big.NewInt(0), Code: callerCode},
+	}}
+	var (
+		signer = types.HomesteadSigner{}
+		target common.Hash
+		raw    []byte
+	)
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), caller, big.NewInt(0), 100000, big.NewInt(0), nil), signer, accounts[0].key)
+		b.AddTx(tx)
+		target = tx.Hash()
+
+		// The same call, to be executed on top of the chain
+		next, _ := types.SignTx(types.NewTransaction(uint64(i)+1, callee, big.NewInt(0), 100000, big.NewInt(0), nil), signer, accounts[0].key)
+		raw, _ = next.MarshalBinary()
+	})
+	api := NewTraceAPI(NewAPI(backend))
+
+	res, err := api.ReplayTransaction(context.Background(), target, []string{"trace", "vmTrace", "stateDiff"})
+	if err != nil {
+		t.Fatalf("failed to replay transaction: %v", err)
+	}
+	if len(res.Output) != 0 {
+		t.Errorf("output mismatch: have %x, want none", res.Output)
+	}
+	var traces []map[string]interface{}
+	if err := json.Unmarshal(res.Trace, &traces); err != nil {
+		t.Fatalf("failed to decode trace: %v", err)
+	}
+	if len(traces) != 2 {
+		t.Fatalf("trace count mismatch: have %d, want 2", len(traces))
+	}
+	var stateDiff map[common.Address]json.RawMessage
+	if err := json.Unmarshal(res.StateDiff, &stateDiff); err != nil {
+		t.Fatalf("failed to decode state diff: %v", err)
+	}
+	if _, ok := stateDiff[callee]; !ok {
+		t.Errorf("state diff misses the storage change of the callee: %s", res.StateDiff)
+	}
+
+	// Check the instructions of both the caller and the callee
+	vmTrace := new(testReplayVMTrace)
+	if err := json.Unmarshal(res.VMTrace, vmTrace); err != nil {
+		t.Fatalf("failed to decode vmTrace: %v", err)
+	}
+	if !bytes.Equal(vmTrace.Code, callerCode) {
+		t.Errorf("caller code mismatch: have %x, want %x", vmTrace.Code, callerCode)
+	}
+	if len(vmTrace.Ops) != 9 {
+		t.Fatalf("caller instruction count mismatch: have %d, want 9", len(vmTrace.Ops))
+	}
+	call := vmTrace.Ops[7]
+	if call.Ex == nil || len(call.Ex.Push) != 1 || call.Ex.Push[0].ToInt().Uint64() != 1 {
+		t.Fatalf("call result mismatch: %+v", call.Ex)
+	}
+	sub := call.Sub
+	if sub == nil || !bytes.Equal(sub.Code, calleeCode) || len(sub.Ops) != 9 {
+		t.Fatalf("callee trace mismatch: %+v", sub)
+	}
+	for i, op := range sub.Ops {
+		if op.Ex == nil {
+			t.Fatalf("callee instruction %d failed", i)
+		}
+		if i > 0 && op.Ex.Used+op.Cost != sub.Ops[i-1].Ex.Used {
+			t.Errorf("callee instruction %d gas mismatch: used %d, cost %d", i, op.Ex.Used, op.Cost)
+		}
+	}
+	if store := sub.Ops[2].Ex.Store; store == nil || store.Key.ToInt().Sign() != 0 || store.Val.ToInt().Uint64() != 42 {
+		t.Errorf("storage write mismatch: %+v", store)
+	}
+	if mem := sub.Ops[5].Ex.Mem; mem == nil || mem.Off != 0 || !bytes.Equal(mem.Data, common.LeftPadBytes([]byte{1}, 32)) {
+		t.Errorf("memory write mismatch: %+v", mem)
+	}
+
+	// Replaying the block produces the same results, along with the transaction hashes
+	blockResults, err := api.ReplayBlockTransactions(context.Background(), rpc.BlockNumber(1), []string{"vmTrace"})
+	if err != nil {
+		t.Fatalf("failed to replay block: %v", err)
+	}
+	if len(blockResults) != 1 || blockResults[0].TransactionHash == nil || *blockResults[0].TransactionHash != target {
+		t.Fatalf("block results mismatch: %+v", blockResults)
+	}
+	if !bytes.Equal(blockResults[0].VMTrace, res.VMTrace) {
+		t.Errorf("block vmTrace mismatch: have %s, want %s", blockResults[0].VMTrace, res.VMTrace)
+	}
+	if string(blockResults[0].Trace) != "[]" || string(blockResults[0].StateDiff) != "null" {
+		t.Errorf("unrequested trace types were returned: %s, %s", blockResults[0].Trace, blockResults[0].StateDiff)
+	}
+
+	// Raw transactions are executed on top of the chain
+	rawResults, err := api.RawTransaction(context.Background(), raw, []string{"trace"})
+	if err != nil {
+		t.Fatalf("failed to trace raw transaction: %v", err)
+	}
+	if want := common.LeftPadBytes([]byte{1}, 32); !bytes.Equal(rawResults.Output, want) {
+		t.Errorf("raw transaction output mismatch: have %x, want %x", rawResults.Output, want)
+	}
+	if _, err := api.ReplayTransaction(context.Background(), target, []string{"bogus"}); err == nil {
+		t.Error("expected error for unknown trace type")
+	}
+
+	// Single traces are retrieved by their trace address
+	for _, test := range []struct {
+		indices []hexutil.Uint64
+		found   bool
+	}{
+		{[]hexutil.Uint64{}, true},
+		{[]hexutil.Uint64{0}, true},
+		{[]hexutil.Uint64{1}, false},
+	} {
+		trace, err := api.Get(context.Background(), target, test.indices)
+		if err != nil {
+			t.Fatalf("failed to get trace %v: %v", test.indices, err)
+		}
+		if (trace != nil) != test.found {
+			t.Errorf("trace %v: have %s, want found %v", test.indices, trace, test.found)
+		}
+	}
+}
+
+// Tests that trace_filter keeps the traces sent from and to the filtered
+// addresses only, dropping the transactions left without traces.
+func TestTraceAddressFilter(t *testing.T) {
+	results := []*txTraceResult{
+		{Result: json.RawMessage(`[
+			{"type":"call","action":{"from":"0x00000000000000000000000000000000000000aa","to":"0x00000000000000000000000000000000000000bb"}},
+			{"type":"call","action":{"from":"0x00000000000000000000000000000000000000bb","to":"0x00000000000000000000000000000000000000cc"}},
+			{"type":"create","action":{"from":"0x00000000000000000000000000000000000000bb"},"result":{"address":"0x00000000000000000000000000000000000000dd"}},
+			{"type":"suicide","action":{"address":"0x00000000000000000000000000000000000000dd","refundAddress":"0x00000000000000000000000000000000000000aa"},"result":null}
+		]`)},
+		{Result: json.RawMessage(`[{"type":"call","action":{"from":"0x00000000000000000000000000000000000000ee","to":"0x00000000000000000000000000000000000000ff"}}]`)},
+		{Error: "execution timeout"},
+	}
+	var (
+		aa = common.HexToAddress("0xaa")
+		bb = common.HexToAddress("0xbb")
+		dd = common.HexToAddress("0xdd")
+	)
+	for i, test := range []struct {
+		from, to *common.Address
+		want     []int // Indexes of the traces kept in the first transaction
+	}{
+		{from: &aa, want: []int{0}},
+		{from: &bb, want: []int{1, 2}},
+		{to: &aa, want: []int{3}},
+		{from: &bb, to: &dd, want: []int{2}},
+		{from: &dd, to: &aa, want: []int{3}},
+		{from: &aa, to: &dd, want: nil},
+	} {
+		filtered := traceAddressFilter(TraceFilterArgs{FromAddress: test.from, ToAddress: test.to})(results)
+
+		var all []json.RawMessage
+		json.Unmarshal(results[0].Result.(json.RawMessage), &all)
+		want := []*txTraceResult{results[2]}
+		if len(test.want) > 0 {
+			var kept []json.RawMessage
+			for _, index := range test.want {
+				kept = append(kept, all[index])
+			}
+			blob, _ := json.Marshal(kept)
+			want = []*txTraceResult{{Result: json.RawMessage(blob)}, results[2]}
+		}
+		have, _ := json.Marshal(filtered)
+		expect, _ := json.Marshal(want)
+		if !bytes.Equal(have, expect) {
+			t.Errorf("test %d: filtered traces mismatch:\nhave %s\nwant %s", i, have, expect)
+		}
+	}
+	if traceAddressFilter(TraceFilterArgs{}) != nil {
+		t.Errorf("filter without addresses")
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxSimulateBlocks is the maximum number of blocks a single simulation may
+// build on top of the chain.
+const maxSimulateBlocks = 256
+
+// SimulateBlock is a block to build on top of the previous one in a simulation.
+// Unless overridden, the block extends its parent by a second, with the same
+// coinbase, difficulty and gas limit, and the base fee following from its parent.
+// Once its calls are executed, the block is finalized by the consensus engine,
+// crediting the block reward to its coinbase.
+type SimulateBlock struct {
+	BlockOverrides *ethapi.BlockOverrides `json:"blockOverrides"` // Header fields of the block
+	StateOverrides *ethapi.StateOverride  `json:"stateOverrides"` // State changes applied before the calls
+	Calls          []ethapi.CallArgs      `json:"calls"`          // Calls to execute within the block
+}
+
+// SimulatedBlockResult is the outcome of a simulated block.
+type SimulatedBlockResult struct {
+	Number     hexutil.Uint64         `json:"number"`
+	Hash       common.Hash            `json:"hash"`
+	ParentHash common.Hash            `json:"parentHash"`
+	StateRoot  common.Hash            `json:"stateRoot"`
+	Timestamp  hexutil.Uint64         `json:"timestamp"`
+	Miner      common.Address         `json:"miner"`
+	Difficulty *hexutil.Big           `json:"difficulty"`
+	GasLimit   hexutil.Uint64         `json:"gasLimit"`
+	GasUsed    hexutil.Uint64         `json:"gasUsed"`
+	BaseFee    *hexutil.Big           `json:"baseFeePerGas,omitempty"`
+	Calls      []*SimulatedCallResult `json:"calls"`
+}
+
+// SimulatedCallResult is the receipt of a simulated call, along with its trace
+// if one was requested. The transaction hash is the one of the unsigned legacy
+// transaction equivalent to the call.
+type SimulatedCallResult struct {
+	TransactionHash   common.Hash     `json:"transactionHash"`
+	Status            hexutil.Uint64  `json:"status"`
+	ReturnData        hexutil.Bytes   `json:"returnData"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+	ContractAddress   *common.Address `json:"contractAddress,omitempty"`
+	Logs              []*types.Log    `json:"logs"`
+	Error             string          `json:"error,omitempty"`
+	Trace             interface{}     `json:"trace,omitempty"`
+}
+
+// SimulateBlocks executes the calls of a sequence of ephemeral blocks built on
+// top of the provided block, each on top of the state left by the previous one,
+// and returns their receipts. Nothing is written to the chain or broadcast.
+// The calls are traced as with traceCall if a config is given.
+func (api *API) SimulateBlocks(ctx context.Context, blocks []SimulateBlock, blockNrOrHash rpc.BlockNumberOrHash, config *TraceConfig) ([]*SimulatedBlockResult, error) {
+	if len(blocks) > maxSimulateBlocks {
+		return nil, fmt.Errorf("too many blocks to simulate: %d > %d", len(blocks), maxSimulateBlocks)
+	}
+	// Try to retrieve the specified block
+	var (
+		err   error
+		block *types.Block
+	)
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		block, err = api.blockByHash(ctx, hash)
+	} else if number, ok := blockNrOrHash.Number(); ok {
+		block, err = api.blockByNumber(ctx, number)
+	} else {
+		return nil, errors.New("invalid arguments; neither block nor hash specified")
+	}
+	if err != nil {
+		return nil, err
+	}
+	reexec := defaultTraceReexec
+	if config != nil && config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	statedb, err := api.backend.StateAtBlock(ctx, block, reexec, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		chainConfig = api.backend.ChainConfig()
+		chain       = &chainContext{api: api, ctx: ctx}
+		parent      = block.Header()
+		getHash     = core.GetHashFn(parent, chain)
+		hashes      = map[uint64]common.Hash{block.NumberU64(): block.Hash()} // Hashes not reachable from the chain
+		results     = make([]*SimulatedBlockResult, 0, len(blocks))
+	)
+	for i, sim := range blocks {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Coinbase:   parent.Coinbase,
+			Difficulty: new(big.Int).Set(parent.Difficulty),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			GasLimit:   parent.GasLimit,
+			Time:       parent.Time + 1,
+		}
+		// Apply the overrides to the block context and carry them over to the header
+		vmctx := core.NewEVMBlockContext(header, chain, &header.Coinbase)
+		vmctx.GetHash = func(n uint64) common.Hash {
+			if hash, ok := hashes[n]; ok {
+				return hash
+			}
+			return getHash(n)
+		}
+		sim.BlockOverrides.Apply(&vmctx, chainConfig)
+
+		header.Number, header.Time, header.Coinbase = vmctx.BlockNumber, vmctx.Time.Uint64(), vmctx.Coinbase
+		header.Difficulty, header.GasLimit, header.BaseFee = vmctx.Difficulty, vmctx.GasLimit, vmctx.BaseFee
+
+		// Derive the base fee from the parent, unless overridden, now that the number is final
+		if sim.BlockOverrides == nil || sim.BlockOverrides.BaseFee == nil {
+			header.BaseFee = nil
+			if chainConfig.IsEnabled(chainConfig.GetEIP1559Transition, header.Number) {
+				header.BaseFee = misc.CalcBaseFee(chainConfig, parent)
+			}
+			vmctx.BaseFee = header.BaseFee
+		}
+
+		if err := sim.StateOverrides.Apply(statedb); err != nil {
+			return nil, fmt.Errorf("block %d: %v", i, err)
+		}
+		result, err := api.simulateCalls(ctx, header, vmctx, sim.Calls, statedb, config)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %v", i, err)
+		}
+		// Credit the block rewards and fill in the state root
+		api.backend.Engine().Finalize(chain, header, statedb, nil, nil)
+
+		// Fill in the hash of the block now that the header is complete
+		hash := header.Hash()
+		for _, call := range result.Calls {
+			for _, log := range call.Logs {
+				log.BlockHash = hash
+			}
+		}
+		result.Hash, result.StateRoot = hash, header.Root
+		results = append(results, result)
+
+		hashes[header.Number.Uint64()] = hash
+		parent = header
+	}
+	return results, nil
+}
+
+// simulateCalls executes the calls of a simulated block on top of the given
+// state, filling in the gas used by the header.
+func (api *API) simulateCalls(ctx context.Context, header *types.Header, vmctx vm.BlockContext, calls []ethapi.CallArgs, statedb *state.StateDB, config *TraceConfig) (*SimulatedBlockResult, error) {
+	var (
+		chainConfig = api.backend.ChainConfig()
+		gp          = new(core.GasPool).AddGas(header.GasLimit)
+		logIndex    uint
+		result      = &SimulatedBlockResult{
+			Number:     hexutil.Uint64(header.Number.Uint64()),
+			ParentHash: header.ParentHash,
+			Timestamp:  hexutil.Uint64(header.Time),
+			Miner:      header.Coinbase,
+			Difficulty: (*hexutil.Big)(header.Difficulty),
+			GasLimit:   hexutil.Uint64(header.GasLimit),
+			BaseFee:    (*hexutil.Big)(header.BaseFee),
+			Calls:      make([]*SimulatedCallResult, 0, len(calls)),
+		}
+	)
+	for i, args := range calls {
+		// Calls without a gas limit may use all the gas left in the block
+		if args.Gas == nil {
+			gas := hexutil.Uint64(gp.Gas())
+			args.Gas = &gas
+		}
+		msg, err := args.ToMessage(api.backend.RPCGasCap(), header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %v", i, err)
+		}
+		nonce := statedb.GetNonce(msg.From())
+		tx := types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: msg.GasPrice(),
+			Gas:      msg.Gas(),
+			To:       msg.To(),
+			Value:    msg.Value(),
+			Data:     msg.Data(),
+		})
+		var tracer vm.Tracer
+		if config != nil {
+			if tracer, err = newTxTracer(config, core.NewEVMTxContext(msg)); err != nil {
+				return nil, err
+			}
+		}
+		// Calls sharing the hash of an earlier one append to its logs
+		logged := len(statedb.GetLogs(tx.Hash()))
+
+		txctx := &txTraceContext{index: i, hash: tx.Hash()}
+		res, err := api.applyTxWithTracer(ctx, tracer, msg, txctx, vmctx, statedb, nil, config, gp)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %v", i, err)
+		}
+		statedb.Finalise(chainConfig.IsEnabled(chainConfig.GetEIP161dTransition, header.Number))
+		header.GasUsed += res.UsedGas
+
+		call := &SimulatedCallResult{
+			TransactionHash:   tx.Hash(),
+			Status:            hexutil.Uint64(types.ReceiptStatusSuccessful),
+			ReturnData:        common.CopyBytes(res.ReturnData),
+			GasUsed:           hexutil.Uint64(res.UsedGas),
+			CumulativeGasUsed: hexutil.Uint64(header.GasUsed),
+			Logs:              []*types.Log{},
+		}
+		if res.Failed() {
+			call.Status, call.Error = hexutil.Uint64(types.ReceiptStatusFailed), res.Err.Error()
+		}
+		if msg.To() == nil {
+			addr := crypto.CreateAddress(msg.From(), nonce)
+			call.ContractAddress = &addr
+		}
+		for _, log := range statedb.GetLogs(tx.Hash())[logged:] {
+			log.BlockNumber, log.Index = header.Number.Uint64(), logIndex
+			logIndex++
+			call.Logs = append(call.Logs, log)
+		}
+		if tracer != nil {
+			trace, err := traceTxResult(tracer, res)
+			if err != nil {
+				return nil, fmt.Errorf("call %d: %v", i, err)
+			}
+			if call.Trace, err = decorateResponse(trace, config); err != nil {
+				return nil, fmt.Errorf("call %d: %v", i, err)
+			}
+		}
+		result.Calls = append(result.Calls, call)
+	}
+	result.GasUsed = hexutil.Uint64(header.GasUsed)
+	return result, nil
+}
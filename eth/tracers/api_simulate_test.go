@@ -0,0 +1,210 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/vars"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestSimulateBlocks(t *testing.T) {
+	t.Parallel()
+
+	// The counter increments slot 0, logs the block number and returns the
+	// new count.
+	var (
+		accounts = newAccounts(1)
+		counter  = common.HexToAddress("0xc0ffee")
+		code     = common.FromHex("600054600101" + "80600055" + "80600052" + "50" + "43" + "60206000a1" + "60206000f3")
+	)
+	genesis := &genesisT.Genesis{Alloc: genesisT.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(vars.Ether)},
+		counter:          {Balance: big.NewInt(0), Code: code},
+	}}
+	backend := newTestBackend(t, 2, genesis, func(i int, b *core.BlockGen) {})
+	api := NewAPI(backend)
+
+	var (
+		tracer = "callTracer"
+		call   = ethapi.CallArgs{From: &accounts[0].addr, To: &counter}
+		blocks = []SimulateBlock{
+			{Calls: []ethapi.CallArgs{call, call}},
+			{
+				BlockOverrides: &ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(50))},
+				StateOverrides: &ethapi.StateOverride{
+					counter: ethapi.OverrideAccount{StateDiff: newStates([]common.Hash{{}}, []common.Hash{common.BigToHash(big.NewInt(10))})},
+				},
+				Calls: []ethapi.CallArgs{call},
+			},
+			{},
+		}
+		latest = rpc.LatestBlockNumber
+	)
+	results, err := api.SimulateBlocks(context.Background(), blocks, rpc.BlockNumberOrHash{BlockNumber: &latest}, &TraceConfig{Tracer: &tracer})
+	if err != nil {
+		t.Fatalf("failed to simulate blocks: %v", err)
+	}
+	if len(results) != len(blocks) {
+		t.Fatalf("block count mismatch: have %d, want %d", len(results), len(blocks))
+	}
+	head := backend.chain.CurrentBlock()
+	for i, want := range []struct {
+		number uint64
+		counts []int64
+	}{
+		{3, []int64{1, 2}},
+		{50, []int64{11}},
+		{51, nil},
+	} {
+		result := results[i]
+		if uint64(result.Number) != want.number {
+			t.Errorf("block %d: number mismatch: have %d, want %d", i, result.Number, want.number)
+		}
+		parent := head.Hash()
+		if i > 0 {
+			parent = results[i-1].Hash
+		}
+		if result.ParentHash != parent {
+			t.Errorf("block %d: parent mismatch: have %x, want %x", i, result.ParentHash, parent)
+		}
+		if uint64(result.Timestamp) != head.Time()+uint64(i)+1 {
+			t.Errorf("block %d: timestamp mismatch: have %d, want %d", i, result.Timestamp, head.Time()+uint64(i)+1)
+		}
+		if len(result.Calls) != len(want.counts) {
+			t.Fatalf("block %d: call count mismatch: have %d, want %d", i, len(result.Calls), len(want.counts))
+		}
+		var cumulative uint64
+		for j, res := range result.Calls {
+			if res.Status != 1 || res.Error != "" {
+				t.Errorf("block %d, call %d: failed: %s", i, j, res.Error)
+			}
+			if want := common.BigToHash(big.NewInt(want.counts[j])).Bytes(); !bytes.Equal(res.ReturnData, want) {
+				t.Errorf("block %d, call %d: return mismatch: have %x, want %x", i, j, []byte(res.ReturnData), want)
+			}
+			cumulative += uint64(res.GasUsed)
+			if uint64(res.CumulativeGasUsed) != cumulative {
+				t.Errorf("block %d, call %d: cumulative gas mismatch: have %d, want %d", i, j, res.CumulativeGasUsed, cumulative)
+			}
+			if len(res.Logs) != 1 {
+				t.Fatalf("block %d, call %d: log count mismatch: have %d, want 1", i, j, len(res.Logs))
+			}
+			log := res.Logs[0]
+			if log.BlockHash != result.Hash || log.BlockNumber != want.number || log.Index != uint(j) || log.TxHash != res.TransactionHash {
+				t.Errorf("block %d, call %d: log context mismatch: %+v", i, j, log)
+			}
+			if log.Topics[0] != common.BigToHash(new(big.Int).SetUint64(want.number)) {
+				t.Errorf("block %d, call %d: logged number mismatch: have %x", i, j, log.Topics[0])
+			}
+			trace := new(callTrace)
+			if err := json.Unmarshal(res.Trace.(json.RawMessage), trace); err != nil {
+				t.Fatalf("block %d, call %d: failed to unmarshal trace: %v", i, j, err)
+			}
+			if trace.To != counter || !bytes.Equal(trace.Output, res.ReturnData) {
+				t.Errorf("block %d, call %d: trace mismatch: %+v", i, j, trace)
+			}
+		}
+		if uint64(result.GasUsed) != cumulative {
+			t.Errorf("block %d: gas used mismatch: have %d, want %d", i, result.GasUsed, cumulative)
+		}
+	}
+	// Nothing is written to the chain
+	if current := backend.chain.CurrentBlock(); current.Hash() != head.Hash() {
+		t.Errorf("chain head changed: have %x, want %x", current.Hash(), head.Hash())
+	}
+	if _, err := api.SimulateBlocks(context.Background(), make([]SimulateBlock, maxSimulateBlocks+1), rpc.BlockNumberOrHash{BlockNumber: &latest}, nil); err == nil {
+		t.Error("expected error for too many blocks")
+	}
+}
+
+func TestSimulateBlocksBaseFee(t *testing.T) {
+	t.Parallel()
+
+	genesis := &genesisT.Genesis{Alloc: genesisT.GenesisAlloc{}}
+	config := *params.TestChainConfig
+	config.LondonBlock = big.NewInt(10)
+	api := NewAPI(newTestBackendWithConfig(t, &config, 1, genesis, func(i int, b *core.BlockGen) {}))
+
+	var (
+		blocks = []SimulateBlock{
+			// Activates EIP-1559, then follows from an empty parent
+			{BlockOverrides: &ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(10))}},
+			{},
+			// Precedes EIP-1559, then overrides the base fee
+			{BlockOverrides: &ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(5))}},
+			{BlockOverrides: &ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(20)), BaseFee: (*hexutil.Big)(big.NewInt(7))}},
+		}
+		latest = rpc.LatestBlockNumber
+	)
+	results, err := api.SimulateBlocks(context.Background(), blocks, rpc.BlockNumberOrHash{BlockNumber: &latest}, nil)
+	if err != nil {
+		t.Fatalf("failed to simulate blocks: %v", err)
+	}
+	for i, want := range []*big.Int{new(big.Int).SetUint64(vars.InitialBaseFee), big.NewInt(875000000), nil, big.NewInt(7)} {
+		if have := (*big.Int)(results[i].BaseFee); (have == nil) != (want == nil) || (have != nil && have.Cmp(want) != 0) {
+			t.Errorf("block %d: base fee mismatch: have %v, want %v", i, have, want)
+		}
+	}
+}
+
+func TestSimulateBlocksRewards(t *testing.T) {
+	t.Parallel()
+
+	// The contract returns the balance of the coinbase of the block it is executed in.
+	var (
+		accounts = newAccounts(1)
+		contract = common.HexToAddress("0xc0ffee")
+		code     = common.FromHex("4131" + "600052" + "60206000f3")
+		coinbase = common.HexToAddress("0xc014ba5e")
+	)
+	genesis := &genesisT.Genesis{Alloc: genesisT.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(vars.Ether)},
+		contract:         {Balance: big.NewInt(0), Code: code},
+	}}
+	api := NewAPI(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+
+	var (
+		call      = ethapi.CallArgs{From: &accounts[0].addr, To: &contract}
+		overrides = &ethapi.BlockOverrides{Coinbase: &coinbase}
+		blocks    = []SimulateBlock{
+			{BlockOverrides: overrides, Calls: []ethapi.CallArgs{call}},
+			{BlockOverrides: overrides, Calls: []ethapi.CallArgs{call}},
+		}
+		latest = rpc.LatestBlockNumber
+	)
+	results, err := api.SimulateBlocks(context.Background(), blocks, rpc.BlockNumberOrHash{BlockNumber: &latest}, nil)
+	if err != nil {
+		t.Fatalf("failed to simulate blocks: %v", err)
+	}
+	// The reward of each block is credited once its calls are executed
+	for i, want := range []*big.Int{new(big.Int), vars.EIP1234FBlockReward} {
+		if have := results[i].Calls[0].ReturnData; !bytes.Equal(have, common.BigToHash(want).Bytes()) {
+			t.Errorf("block %d: coinbase balance mismatch: have %x, want %v", i, []byte(have), want)
+		}
+	}
+}
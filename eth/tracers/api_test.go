@@ -62,8 +62,13 @@ type testBackend struct {
 }
 
 func newTestBackend(t *testing.T, n int, gspec *genesisT.Genesis, generator func(i int, b *core.BlockGen)) *testBackend {
+	return newTestBackendWithConfig(t, params.TestChainConfig, n, gspec, generator)
+}
+
+// newTestBackendWithConfig creates a new test backend running the given chain configuration.
+func newTestBackendWithConfig(t *testing.T, config ctypes.ChainConfigurator, n int, gspec *genesisT.Genesis, generator func(i int, b *core.BlockGen)) *testBackend {
 	backend := &testBackend{
-		chainConfig: params.TestChainConfig,
+		chainConfig: config,
 		engine:      ethash.NewFaker(),
 		chaindb:     rawdb.NewMemoryDatabase(),
 	}
@@ -164,7 +169,7 @@ func (b *testBackend) StateAtTransaction(ctx context.Context, block *types.Block
 	// Recompute transactions up to the target index.
 	signer := types.MakeSigner(b.chainConfig, block.Number())
 	for idx, tx := range block.Transactions() {
-		msg, _ := tx.AsMessage(signer)
+		msg, _ := tx.AsMessage(signer, block.BaseFee())
 		txContext := core.NewEVMTxContext(msg)
 		context := core.NewEVMBlockContext(block.Header(), b.chain, nil)
 		if idx == txIndex {
@@ -452,6 +457,126 @@ func TestOverridenTraceCall(t *testing.T) {
 	}
 }
 
+func TestTraceCallManyOverrides(t *testing.T) {
+	t.Parallel()
+
+	// The contract returns the timestamp, number, coinbase, hash of the parent
+	// and storage slot 0 of the block context it is executed in.
+	var (
+		accounts = newAccounts(2)
+		contract = common.HexToAddress("0xc0ffee")
+		code     = common.FromHex("42600052" + "43602052" + "41604052" + "6001430340606052" + "600054608052" + "60a06000f3")
+	)
+	genesis := &genesisT.Genesis{Alloc: genesisT.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(vars.Ether)},
+		contract:         {Balance: big.NewInt(0), Code: code},
+	}}
+	api := NewAPI(newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {}))
+
+	var (
+		tracer   = "callTracer"
+		coinbase = common.HexToAddress("0xc014ba5e")
+		parent   = common.HexToHash("0xbeef")
+		call     = ethapi.CallArgs{From: &accounts[0].addr, To: &contract}
+		config   = &TraceCallConfig{
+			Tracer: &tracer,
+			BlockOverrides: &ethapi.BlockOverrides{
+				Number:    (*hexutil.Big)(big.NewInt(100)),
+				Time:      newRPCUint64(12345),
+				Coinbase:  &coinbase,
+				BlockHash: &map[hexutil.Uint64]common.Hash{99: parent},
+			},
+		}
+	)
+	// The storage written by the second call stays for the third one
+	txs := []TraceCallManyArgs{
+		{CallArgs: call},
+		{CallArgs: call, StateOverrides: &ethapi.StateOverride{
+			contract: ethapi.OverrideAccount{StateDiff: newStates([]common.Hash{{}}, []common.Hash{common.BigToHash(big.NewInt(7))})},
+		}},
+		{CallArgs: call},
+	}
+	latest := rpc.LatestBlockNumber
+	results, err := api.TraceCallMany(context.Background(), txs, rpc.BlockNumberOrHash{BlockNumber: &latest}, config)
+	if err != nil {
+		t.Fatalf("failed to trace calls: %v", err)
+	}
+	for i, result := range results.([]interface{}) {
+		raw, ok := result.(json.RawMessage)
+		if !ok {
+			t.Fatalf("call %d: unexpected result %v", i, result)
+		}
+		ret := new(callTrace)
+		if err := json.Unmarshal(raw, ret); err != nil {
+			t.Fatalf("call %d: failed to unmarshal trace result: %v", i, err)
+		}
+		slot := int64(7)
+		if i == 0 {
+			slot = 0
+		}
+		want := append(append(append(append(
+			common.BigToHash(big.NewInt(12345)).Bytes(),
+			common.BigToHash(big.NewInt(100)).Bytes()...),
+			common.BytesToHash(coinbase.Bytes()).Bytes()...),
+			parent.Bytes()...),
+			common.BigToHash(big.NewInt(slot)).Bytes()...)
+		if !bytes.Equal(ret.Output, want) {
+			t.Errorf("call %d: output mismatch:\nhave %x\nwant %x", i, []byte(ret.Output), want)
+		}
+	}
+}
+
+// Tests that calls traced with a number override activating EIP-1559 on a
+// block preceding it run with a base fee.
+func TestTraceCallBaseFeeOverride(t *testing.T) {
+	t.Parallel()
+
+	// The contract returns the base fee of the block context it is executed in.
+	var (
+		accounts = newAccounts(1)
+		contract = common.HexToAddress("0xc0ffee")
+		code     = common.FromHex("48600052" + "60206000f3")
+	)
+	genesis := &genesisT.Genesis{Alloc: genesisT.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(vars.Ether)},
+		contract:         {Balance: big.NewInt(0), Code: code},
+	}}
+	config := *params.TestChainConfig
+	config.LondonBlock = big.NewInt(10)
+	api := NewAPI(newTestBackendWithConfig(t, &config, 1, genesis, func(i int, b *core.BlockGen) {}))
+
+	var (
+		call   = ethapi.CallArgs{From: &accounts[0].addr, To: &contract}
+		number = rpc.LatestBlockNumber
+		latest = rpc.BlockNumberOrHash{BlockNumber: &number}
+	)
+
+	for i, tt := range []struct {
+		overrides *ethapi.BlockOverrides
+		want      *big.Int
+	}{
+		{&ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(10))}, new(big.Int).SetUint64(vars.InitialBaseFee)},
+		{&ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(100))}, new(big.Int).SetUint64(vars.InitialBaseFee)},
+		{&ethapi.BlockOverrides{Number: (*hexutil.Big)(big.NewInt(10)), BaseFee: (*hexutil.Big)(big.NewInt(7))}, big.NewInt(7)},
+	} {
+		want := common.BigToHash(tt.want).Bytes()
+		result, err := api.TraceCall(context.Background(), call, latest, &TraceCallConfig{BlockOverrides: tt.overrides})
+		if err != nil {
+			t.Fatalf("test %d: failed to trace call: %v", i, err)
+		}
+		if have := common.FromHex(result.(*ethapi.ExecutionResult).ReturnValue); !bytes.Equal(have, want) {
+			t.Errorf("test %d: base fee mismatch: have %x, want %x", i, have, want)
+		}
+		results, err := api.TraceCallMany(context.Background(), []TraceCallManyArgs{{CallArgs: call}}, latest, &TraceCallConfig{BlockOverrides: tt.overrides})
+		if err != nil {
+			t.Fatalf("test %d: failed to trace calls: %v", i, err)
+		}
+		if have := common.FromHex(results.([]interface{})[0].(*ethapi.ExecutionResult).ReturnValue); !bytes.Equal(have, want) {
+			t.Errorf("test %d: base fee mismatch: have %x, want %x", i, have, want)
+		}
+	}
+}
+
 func TestTraceTransaction(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,624 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/vars"
+	"github.com/holiman/uint256"
+)
+
+// parityPrecompiles are the precompiled contracts skipped by the Parity tracers,
+// the same as the JavaScript tracers' isPrecompiled.
+var parityPrecompiles = vm.PrecompiledContractsForConfig(params.AllEthashProtocolChanges, big.NewInt(0))
+
+// isParityPrecompiled reports whether the address is a precompiled contract.
+func isParityPrecompiled(addr common.Address) bool {
+	_, ok := parityPrecompiles[addr]
+	return ok
+}
+
+// parityErrorMapping maps EVM errors to their Parity counterparts.
+var parityErrorMapping = map[string]string{
+	"contract creation code storage out of gas": "Out of gas",
+	"out of gas":                      "Out of gas",
+	"gas uint64 overflow":             "Out of gas",
+	"max code size exceeded":          "Out of gas",
+	"invalid jump destination":        "Bad jump destination",
+	"execution reverted":              "Reverted",
+	"return data out of bounds":       "Out of bounds",
+	"stack limit reached 1024 (1023)": "Out of stack",
+	"precompiled failed":              "Built-in failed",
+}
+
+// parityErrorMappingContaining maps EVM errors containing a key to their Parity
+// counterparts, applied in order.
+var parityErrorMappingContaining = [][2]string{
+	{"invalid opcode:", "Bad instruction"},
+	{"stack underflow", "Stack underflow"},
+}
+
+// paritySkipTracesForErrors are the errors of calls which are left out of the traces.
+var paritySkipTracesForErrors = map[string]bool{
+	"insufficient balance for transfer": true,
+}
+
+// parityCallFrame is a call being assembled by the parityCallTracer.
+// Optional fields are nil until set.
+type parityCallFrame struct {
+	typ     string // Opcode of the call, empty for the placeholder of the outermost call
+	from    string
+	to      *string
+	input   *string
+	gas     *int64
+	gasIn   int64
+	gasCost int64
+	gasUsed *int64
+	value   *string
+	output  *string
+	err     *string
+	time    *string
+	calls   []*parityCallFrame
+}
+
+// empty reports whether none of the frame's fields have been set.
+func (f *parityCallFrame) empty() bool {
+	return f.typ == "" && f.from == "" && f.to == nil && f.input == nil && f.gas == nil &&
+		f.gasUsed == nil && f.value == nil && f.output == nil && f.err == nil && f.time == nil && f.calls == nil
+}
+
+// parityTrace is a single entry of the flattened Parity trace output.
+type parityTrace struct {
+	Type                string      `json:"type"`
+	Action              interface{} `json:"action"`
+	Result              interface{} `json:"result,omitempty"`
+	Error               *string     `json:"error,omitempty"`
+	TraceAddress        []int       `json:"traceAddress"`
+	Subtraces           int         `json:"subtraces"`
+	TransactionPosition *uint64     `json:"transactionPosition,omitempty"`
+	TransactionHash     *string     `json:"transactionHash,omitempty"`
+	BlockNumber         uint64      `json:"blockNumber"`
+	BlockHash           *string     `json:"blockHash,omitempty"`
+	Time                *string     `json:"time,omitempty"`
+}
+
+type parityCreateAction struct {
+	From           string  `json:"from"`
+	Value          *string `json:"value,omitempty"`
+	Gas            *string `json:"gas,omitempty"`
+	Init           *string `json:"init,omitempty"`
+	CreationMethod string  `json:"creationMethod"`
+}
+
+type parityCreateResult struct {
+	GasUsed *string `json:"gasUsed,omitempty"`
+	Code    *string `json:"code,omitempty"`
+	Address *string `json:"address,omitempty"`
+}
+
+type parityCallAction struct {
+	From     string  `json:"from"`
+	To       *string `json:"to,omitempty"`
+	Value    *string `json:"value,omitempty"`
+	Gas      *string `json:"gas,omitempty"`
+	Input    *string `json:"input,omitempty"`
+	CallType string  `json:"callType"`
+}
+
+type parityCallResult struct {
+	GasUsed *string `json:"gasUsed,omitempty"`
+	Output  *string `json:"output,omitempty"`
+}
+
+type paritySuicideAction struct {
+	Address       string  `json:"address"`
+	RefundAddress *string `json:"refundAddress,omitempty"`
+	Balance       *string `json:"balance,omitempty"`
+}
+
+// parityCallTracer is a native implementation of call_tracer_parity.js,
+// producing identical results.
+type parityCallTracer struct {
+	callstack []*parityCallFrame
+	descended bool
+
+	// Step gating of the JavaScript tracer, which only runs step when the result
+	// may be affected. The stale callstack length is part of its behaviour.
+	handleNextOpCode bool
+	callstackLength  *int
+
+	// Transaction context
+	block       uint64
+	typ         string
+	from        common.Address
+	to          common.Address
+	input       []byte
+	gas         uint64
+	value       *big.Int
+	gasUsed     uint64
+	output      []byte
+	time        string
+	ctxErr      *string
+	blockHash   *string
+	txHash      *string
+	txPosition  *uint64
+	ctxCaptured bool
+
+	err       error  // Error, if one has occurred
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newParityCallTracer creates a native Parity call tracer.
+func newParityCallTracer() ResultTracer {
+	return &parityCallTracer{
+		callstack:       []*parityCallFrame{{}},
+		callstackLength: new(int),
+	}
+}
+
+// CapturePreEVM retrieves the transaction and block identifiers from the inputs.
+func (t *parityCallTracer) CapturePreEVM(env *vm.EVM, inputs map[string]interface{}) {
+	if hash, ok := inputs["transactionHash"].(string); ok {
+		t.txHash = &hash
+	}
+	if hash, ok := inputs["blockHash"].(string); ok {
+		t.blockHash = &hash
+	}
+	if position, ok := inputs["transactionPosition"].(uint64); ok {
+		t.txPosition = &position
+	}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *parityCallTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.typ = "CALL"
+	if create {
+		t.typ = "CREATE"
+	}
+	t.from, t.to, t.input, t.gas, t.value = from, to, input, gas, value
+	t.block = env.Context.BlockNumber.Uint64()
+	t.ctxCaptured = true
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *parityCallTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.err != nil {
+		return
+	}
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.err = t.reason
+		return
+	}
+	callErr := env.CallErrorTemp
+	env.CallErrorTemp = nil
+
+	if t.callstackLength == nil {
+		length := len(t.callstack)
+		t.callstackLength = &length
+	}
+	switch {
+	case *t.callstackLength-1 == depth:
+	case t.handleNextOpCode:
+		t.handleNextOpCode = false
+	case op&0xf0 == 0xf0:
+		t.handleNextOpCode = true
+	default:
+		return
+	}
+	t.callstackLength = nil
+
+	if err != nil {
+		t.fault(gas, err.Error(), callErr)
+		return
+	}
+	t.step(env, op, gas, cost, scope, rData, depth, callErr)
+}
+
+// step processes an opcode, assembling the calls made by the transaction.
+func (t *parityCallTracer) step(env *vm.EVM, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, callErr error) {
+	if len(t.callstack) == 0 {
+		return
+	}
+	var (
+		stack = scope.Stack
+		top   = t.callstack[len(t.callstack)-1]
+	)
+	switch op {
+	case vm.CREATE, vm.CREATE2:
+		// If a new contract is being created, add to the call stack
+		call := &parityCallFrame{
+			typ:     op.String(),
+			from:    hexutil.Encode(scope.Contract.Address().Bytes()),
+			input:   newString(hexutil.Encode(memorySlice(scope.Memory, peekStack(stack, 1), peekStack(stack, 2)))),
+			gas:     newInt64(int64(env.CallGasTemp)),
+			gasIn:   int64(gas),
+			gasCost: int64(cost),
+			value:   newString(bigHex(peekStack(stack, 0).ToBig())),
+		}
+		t.callstack = append(t.callstack, call)
+		t.descended = true
+		return
+
+	case vm.SELFDESTRUCT:
+		// If a contract is being self destructed, gather that as a subcall too
+		addr := scope.Contract.Address()
+		top.calls = append(top.calls, &parityCallFrame{
+			typ:     op.String(),
+			from:    hexutil.Encode(addr.Bytes()),
+			to:      newString(hexutil.Encode(common.Address(peekStack(stack, 0).Bytes20()).Bytes())),
+			gasIn:   int64(gas),
+			gasCost: int64(cost),
+			value:   newString(bigHex(env.StateDB.GetBalance(addr))),
+		})
+		return
+
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		// If a new method invocation is being done, add to the call stack
+		to := common.Address(peekStack(stack, 1).Bytes20())
+
+		// Skip any pre-compile invocations, those are just fancy opcodes
+		if isParityPrecompiled(to) && (op == vm.CALL || op == vm.STATICCALL) {
+			return
+		}
+		off := 1
+		if op == vm.DELEGATECALL || op == vm.STATICCALL {
+			off = 0
+		}
+		call := &parityCallFrame{
+			typ:     op.String(),
+			from:    hexutil.Encode(scope.Contract.Address().Bytes()),
+			to:      newString(hexutil.Encode(to.Bytes())),
+			input:   newString(hexutil.Encode(memorySlice(scope.Memory, peekStack(stack, 2+off), peekStack(stack, 3+off)))),
+			gas:     newInt64(int64(env.CallGasTemp)),
+			gasIn:   int64(gas),
+			gasCost: int64(cost),
+		}
+		switch op {
+		case vm.CALL, vm.CALLCODE:
+			value := peekStack(stack, 2)
+			call.value = newString(bigHex(value.ToBig()))
+
+			// Add stipend (only CALL|CALLCODE when value > 0)
+			if !value.IsZero() {
+				*call.gas += int64(vars.CallStipend)
+			}
+		case vm.STATICCALL:
+			call.value = newString("0x0")
+		}
+		t.callstack = append(t.callstack, call)
+		t.descended = true
+		return
+	}
+	// If we've just descended into an inner call, its gas allowance was retrieved
+	// from the call operation, unless the call already failed.
+	if t.descended {
+		if depth >= len(t.callstack) && top.gas == nil {
+			top.gas = newInt64(int64(gas))
+		}
+		t.descended = false
+	}
+
+	switch op {
+	case vm.REVERT:
+		top.err = newString("execution reverted")
+		return
+
+	case vm.RETURN:
+		if depth == len(t.callstack) {
+			top.output = newString(hexutil.Encode(memorySlice(scope.Memory, peekStack(stack, 0), peekStack(stack, 1))))
+		}
+		return
+	}
+	if depth != len(t.callstack)-1 {
+		return
+	}
+	// Pop off the last call and get the execution results
+	call := top
+	t.callstack = t.callstack[:len(t.callstack)-1]
+
+	ret := peekStack(stack, 0)
+	if call.typ == vm.CREATE.String() || call.typ == vm.CREATE2.String() {
+		// If the call was a CREATE, retrieve the contract address and output code
+		if call.gas != nil {
+			call.gasUsed = newInt64(*call.gas - (int64(gas) - (call.gasIn - call.gasCost)))
+		}
+
+		if !ret.IsZero() {
+			addr := common.Address(ret.Bytes20())
+			call.to = newString(hexutil.Encode(addr.Bytes()))
+			call.output = newString(hexutil.Encode(env.StateDB.GetCode(addr)))
+		} else if call.err == nil {
+			if callErr == nil {
+				return
+			}
+			if paritySkipTracesForErrors[callErr.Error()] {
+				return
+			}
+			call.err = newString(callErr.Error())
+		}
+	} else {
+		// If the call was a contract call, retrieve the gas usage and output
+		if call.gas != nil {
+			call.gasUsed = newInt64(call.gasIn - call.gasCost + *call.gas - int64(gas))
+		}
+
+		if !ret.IsZero() {
+			if call.output == nil || *call.output == "0x" {
+				call.output = newString(hexutil.Encode(rData))
+			}
+		} else if call.err == nil {
+			if callErr != nil {
+				if paritySkipTracesForErrors[callErr.Error()] {
+					return
+				}
+				if call.to != nil && isParityPrecompiled(common.HexToAddress(*call.to)) && callErr.Error() != vm.ErrOutOfGas.Error() {
+					call.err = newString("precompiled failed")
+				} else {
+					call.err = newString(callErr.Error())
+				}
+			} else {
+				call.err = newString("internal failure")
+			}
+		}
+	}
+	// Inject the call into the previous one
+	if len(t.callstack) > 0 {
+		parent := t.callstack[len(t.callstack)-1]
+		parent.calls = append(parent.calls, call)
+	}
+}
+
+// fault processes an execution error of the current call.
+func (t *parityCallTracer) fault(gas uint64, errStr string, callErr error) {
+	if len(t.callstack) == 0 {
+		return
+	}
+	// If the topmost call already reverted, don't handle the additional fault again
+	if t.callstack[len(t.callstack)-1].err != nil {
+		return
+	}
+	// Pop off the just failed call
+	call := t.callstack[len(t.callstack)-1]
+	t.callstack = t.callstack[:len(t.callstack)-1]
+
+	call.err = &errStr
+	if callErr != nil {
+		if paritySkipTracesForErrors[callErr.Error()] {
+			return
+		}
+		call.err = newString(callErr.Error())
+	}
+	// Consume all available gas
+	if call.gas != nil {
+		call.gasUsed = newInt64(*call.gas)
+	} else {
+		// Retrieve the true allowance of the outermost call
+		call.gas = newInt64(int64(gas))
+	}
+	// Flatten the failed call into its parent
+	if len(t.callstack) > 0 {
+		parent := t.callstack[len(t.callstack)-1]
+		parent.calls = append(parent.calls, call)
+		return
+	}
+	// Last call failed too, leave it in the stack
+	t.callstack = append(t.callstack, call)
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault.
+func (t *parityCallTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if t.err != nil {
+		return
+	}
+	env.CallErrorTemp = nil
+	t.fault(gas, err.Error(), nil)
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *parityCallTracer) CaptureEnd(env *vm.EVM, output []byte, gasUsed uint64, d time.Duration, err error) {
+	t.output, t.gasUsed, t.time = output, gasUsed, d.String()
+	if err != nil {
+		t.ctxErr = newString(err.Error())
+	}
+}
+
+// GetResult returns the flattened Parity traces of the calls, or any error.
+func (t *parityCallTracer) GetResult() (json.RawMessage, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	if !t.ctxCaptured {
+		return json.RawMessage("[]"), nil
+	}
+	value := t.value
+	if value == nil {
+		value = new(big.Int)
+	}
+	result := &parityCallFrame{
+		typ:     t.typ,
+		from:    hexutil.Encode(t.from.Bytes()),
+		to:      newString(hexutil.Encode(t.to.Bytes())),
+		value:   newString(bigHex(value)),
+		gas:     newInt64(int64(t.gas)),
+		gasUsed: newInt64(int64(t.gasUsed)),
+		input:   newString(hexutil.Encode(t.input)),
+		output:  newString(hexutil.Encode(t.output)),
+		time:    &t.time,
+	}
+	// When descended remains set and the outermost call placeholder is unused,
+	// drop it in order to handle edge cases of the step gating.
+	if t.descended && len(t.callstack) > 1 && t.callstack[0].empty() {
+		t.callstack = t.callstack[1:]
+	}
+	if len(t.callstack) > 0 {
+		result.calls = t.callstack[0].calls
+		result.err = t.callstack[0].err
+	}
+	if result.err == nil {
+		result.err = t.ctxErr
+	}
+	if result.err != nil && (*result.err != "execution reverted" || *result.output == "0x") {
+		result.output = nil
+	}
+	traces := t.finalize(result, []int{}, nil)
+
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(traces); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// finalize flattens a call and its subcalls into Parity traces.
+func (t *parityCallTracer) finalize(call *parityCallFrame, traceAddress []int, traces []*parityTrace) []*parityTrace {
+	trace := &parityTrace{
+		Error:               call.err,
+		TraceAddress:        traceAddress,
+		Subtraces:           len(call.calls),
+		TransactionPosition: t.txPosition,
+		TransactionHash:     t.txHash,
+		BlockNumber:         t.block,
+		BlockHash:           t.blockHash,
+		Time:                call.time,
+	}
+	switch call.typ {
+	case "CREATE", "CREATE2":
+		trace.Type = "create"
+		trace.Action = &parityCreateAction{
+			From:           call.from,
+			Value:          call.value,
+			Gas:            int64Hex(call.gas),
+			Init:           call.input,
+			CreationMethod: strings.ToLower(call.typ),
+		}
+		trace.Result = &parityCreateResult{
+			GasUsed: int64Hex(call.gasUsed),
+			Code:    call.output,
+			Address: call.to,
+		}
+	case "SELFDESTRUCT":
+		trace.Type = "suicide"
+		trace.Action = &paritySuicideAction{
+			Address:       call.from,
+			RefundAddress: call.to,
+			Balance:       call.value,
+		}
+		trace.Result = json.RawMessage("null")
+	default:
+		trace.Type = "call"
+		trace.Action = &parityCallAction{
+			From:     call.from,
+			To:       call.to,
+			Value:    call.value,
+			Gas:      int64Hex(call.gas),
+			Input:    call.input,
+			CallType: strings.ToLower(call.typ),
+		}
+		trace.Result = &parityCallResult{
+			GasUsed: int64Hex(call.gasUsed),
+			Output:  call.output,
+		}
+	}
+	if trace.Error != nil {
+		if mapped, ok := parityErrorMapping[*trace.Error]; ok {
+			trace.Error, trace.Result = &mapped, nil
+		} else {
+			for _, mapping := range parityErrorMappingContaining {
+				if strings.Contains(*trace.Error, mapping[0]) {
+					trace.Error, trace.Result = newString(mapping[1]), nil
+				}
+			}
+		}
+	}
+	traces = append(traces, trace)
+
+	for i, child := range call.calls {
+		// Delegatecall uses the value from parent
+		if (child.typ == "DELEGATECALL" || child.typ == "STATICCALL") && child.value == nil {
+			child.value = call.value
+		}
+		childAddress := make([]int, len(traceAddress)+1)
+		copy(childAddress, traceAddress)
+		childAddress[len(traceAddress)] = i
+
+		traces = t.finalize(child, childAddress, traces)
+	}
+	return traces
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *parityCallTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// peekStack returns the n-th item from the top of the stack, or zero if the
+// stack is too short.
+func peekStack(stack *vm.Stack, n int) *uint256.Int {
+	if len(stack.Data()) <= n {
+		return new(uint256.Int)
+	}
+	return stack.Back(n)
+}
+
+// memorySlice returns a copy of size bytes of memory at offset, or nil if out
+// of bounds.
+func memorySlice(mem *vm.Memory, offset, size *uint256.Int) []byte {
+	if size.IsZero() {
+		return []byte{}
+	}
+	if !offset.IsUint64() || !size.IsUint64() {
+		return nil
+	}
+	begin, length := offset.Uint64(), size.Uint64()
+	if end := begin + length; end < begin || uint64(mem.Len()) < end {
+		return nil
+	}
+	return mem.GetCopy(int64(begin), int64(length))
+}
+
+// bigHex formats a big integer the way the JavaScript tracers do, as a 0x
+// prefixed hex string without leading zeros.
+func bigHex(n *big.Int) string {
+	return "0x" + n.Text(16)
+}
+
+// int64Hex formats a gas amount the way the JavaScript tracers do.
+func int64Hex(n *int64) *string {
+	if n == nil {
+		return nil
+	}
+	return newString("0x" + strconv.FormatInt(*n, 16))
+}
+
+func newString(s string) *string { return &s }
+
+func newInt64(n int64) *int64 { return &n }
@@ -0,0 +1,153 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Parity trace types, as requested by the trace_replay* methods.
+const (
+	parityTraceTypeTrace     = "trace"
+	parityTraceTypeVMTrace   = "vmTrace"
+	parityTraceTypeStateDiff = "stateDiff"
+)
+
+// parityReplayTracer runs the tracers of all the requested Parity trace types
+// within a single execution, collecting their results along with its output.
+type parityReplayTracer struct {
+	trace     ResultTracer // Parity call tracer, if requested
+	vmTrace   ResultTracer // Parity vmTrace tracer, if requested
+	stateDiff ResultTracer // State diff tracer, if requested
+
+	tracers []ResultTracer // All the requested tracers
+	output  []byte         // Output of the execution
+}
+
+// newParityReplayTracer creates a tracer producing the given Parity trace types.
+func newParityReplayTracer(traceTypes []string) (*parityReplayTracer, error) {
+	t := new(parityReplayTracer)
+	for _, typ := range traceTypes {
+		switch typ {
+		case parityTraceTypeTrace:
+			if t.trace == nil {
+				t.trace = newParityCallTracer()
+				t.tracers = append(t.tracers, t.trace)
+			}
+		case parityTraceTypeVMTrace:
+			if t.vmTrace == nil {
+				t.vmTrace = newParityVMTracer()
+				t.tracers = append(t.tracers, t.vmTrace)
+			}
+		case parityTraceTypeStateDiff:
+			if t.stateDiff == nil {
+				t.stateDiff = newStateDiffTracer()
+				t.tracers = append(t.tracers, t.stateDiff)
+			}
+		default:
+			return nil, fmt.Errorf("unknown trace type %q", typ)
+		}
+	}
+	return t, nil
+}
+
+// CapturePreEVM implements the Tracer interface, passing the transaction
+// context on to all tracers.
+func (t *parityReplayTracer) CapturePreEVM(env *vm.EVM, inputs map[string]interface{}) {
+	for _, tracer := range t.tracers {
+		tracer.CapturePreEVM(env, inputs)
+	}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *parityReplayTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	for _, tracer := range t.tracers {
+		tracer.CaptureStart(env, from, to, create, input, gas, value)
+	}
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *parityReplayTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	// The tracers consume the error of the last call, so hand it to each of them
+	callErr := env.CallErrorTemp
+	for _, tracer := range t.tracers {
+		env.CallErrorTemp = callErr
+		tracer.CaptureState(env, pc, op, gas, cost, scope, rData, depth, err)
+	}
+	env.CallErrorTemp = nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (t *parityReplayTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	callErr := env.CallErrorTemp
+	for _, tracer := range t.tracers {
+		env.CallErrorTemp = callErr
+		tracer.CaptureFault(env, pc, op, gas, cost, scope, depth, err)
+	}
+	env.CallErrorTemp = nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *parityReplayTracer) CaptureEnd(env *vm.EVM, output []byte, gasUsed uint64, d time.Duration, err error) {
+	t.output = common.CopyBytes(output)
+	for _, tracer := range t.tracers {
+		tracer.CaptureEnd(env, output, gasUsed, d, err)
+	}
+}
+
+// GetResult returns the encoded results of all the requested trace types.
+func (t *parityReplayTracer) GetResult() (json.RawMessage, error) {
+	results := &ParityTraceResults{
+		Output: t.output,
+		Trace:  json.RawMessage("[]"),
+	}
+	if results.Output == nil {
+		results.Output = []byte{}
+	}
+	var err error
+	if t.trace != nil {
+		if results.Trace, err = t.trace.GetResult(); err != nil {
+			return nil, err
+		}
+	}
+	if t.vmTrace != nil {
+		if results.VMTrace, err = t.vmTrace.GetResult(); err != nil {
+			return nil, err
+		}
+	}
+	if t.stateDiff != nil {
+		if results.StateDiff, err = t.stateDiff.GetResult(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(results)
+}
+
+// Stop terminates execution of all the tracers at the first opportune moment.
+func (t *parityReplayTracer) Stop(err error) {
+	for _, tracer := range t.tracers {
+		tracer.Stop(err)
+	}
+}
+
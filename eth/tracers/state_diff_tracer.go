@@ -0,0 +1,511 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// State diff markers.
+const (
+	stateDiffBorn    = "+"
+	stateDiffDied    = "-"
+	stateDiffChanged = "*"
+	stateDiffSame    = "="
+)
+
+// stateDiffSlot is a storage slot accessed during execution.
+type stateDiffSlot struct {
+	changed  bool // Whether the slot held data at least once
+	from, to common.Hash
+}
+
+// stateDiffAccountState is an account accessed during execution. The initial values
+// are unset for accounts only accessed through their storage so far.
+type stateDiffAccountState struct {
+	marker  string
+	removed bool // Removed from state
+	errored bool // Errored in the VM
+	final   bool // No longer updated, as its values were settled
+
+	balanceFrom, balanceTo *big.Int
+	nonceFrom, nonceTo     *uint64
+	codeFrom, codeTo       *string
+
+	storage     map[common.Hash]*stateDiffSlot
+	storageKeys []common.Hash // Storage slots in access order
+}
+
+// stateDiffTracer is a native implementation of state_diff_tracer.js,
+// producing identical results.
+type stateDiffTracer struct {
+	db       vm.StateDB
+	accounts map[common.Address]*stateDiffAccountState
+	order    []common.Address // Accounts in access order
+	last     *common.Address  // Last accessed account
+
+	// Transaction context
+	typ                      string
+	from, to, coinbase       common.Address
+	value                    *big.Int
+	hasCoinbase              bool
+	hasBalanceForValueAndGas bool
+	hasBalanceForGas         bool
+	ctxCaptured              bool
+
+	err       error  // Error, if one has occurred
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newStateDiffTracer creates a native state diff tracer.
+func newStateDiffTracer() ResultTracer {
+	return &stateDiffTracer{
+		accounts: make(map[common.Address]*stateDiffAccountState),
+	}
+}
+
+// accountInit returns the tracked account, adding it with the given marker if new.
+func (t *stateDiffTracer) accountInit(addr common.Address, marker string) *stateDiffAccountState {
+	if acc, ok := t.accounts[addr]; ok {
+		return acc
+	}
+	if marker == "" {
+		marker = stateDiffChanged
+	}
+	acc := &stateDiffAccountState{
+		marker:  marker,
+		storage: make(map[common.Hash]*stateDiffSlot),
+	}
+	t.accounts[addr] = acc
+	t.order = append(t.order, addr)
+	return acc
+}
+
+// remove stops tracking the account.
+func (t *stateDiffTracer) remove(addr common.Address) {
+	delete(t.accounts, addr)
+	for i, a := range t.order {
+		if a == addr {
+			t.order = append(t.order[:i], t.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// lookupAccount tracks the current state of the account, forcing the given
+// marker if not empty.
+func (t *stateDiffTracer) lookupAccount(addr common.Address, marker string) {
+	t.last = &addr
+
+	// No need to fetch updates, as the account's values were settled
+	if acc, ok := t.accounts[addr]; ok && acc.final {
+		return
+	}
+	var (
+		balance = new(big.Int).Set(t.db.GetBalance(addr))
+		code    = hexutil.Encode(t.db.GetCode(addr))
+		nonce   = t.db.GetNonce(addr)
+	)
+	acc := t.accountInit(addr, marker)
+
+	// The initial values are unset the first time the account itself is looked up
+	if acc.balanceFrom == nil {
+		acc.balanceFrom, acc.nonceFrom, acc.codeFrom = balance, &nonce, &code
+	}
+	if marker != "" {
+		// An account born and died within this run will never be persisted to the state
+		if acc.marker == stateDiffBorn && marker == stateDiffDied {
+			acc.removed = true
+		}
+		acc.marker = marker
+	}
+	acc.balanceTo = balance
+
+	// If the state doesn't have the account, most probably because of EIP-161, then remove it
+	if nonce < *acc.nonceFrom {
+		t.remove(addr)
+		return
+	} else if nonce != 0 {
+		acc.nonceTo = &nonce
+	}
+	acc.codeTo = &code
+}
+
+// lookupStorage tracks the current value of the storage slot, or the given
+// value about to be stored.
+func (t *stateDiffTracer) lookupStorage(addr common.Address, key common.Hash, val *common.Hash) {
+	t.last = &addr
+
+	acc := t.accountInit(addr, "")
+	slot, ok := acc.storage[key]
+	if !ok {
+		from := t.db.GetState(addr, key)
+		slot = &stateDiffSlot{changed: from != (common.Hash{}), from: from}
+		acc.storage[key] = slot
+		acc.storageKeys = append(acc.storageKeys, key)
+	}
+	if val != nil {
+		slot.to = *val
+	} else {
+		slot.to = t.db.GetState(addr, key)
+	}
+	if slot.to != (common.Hash{}) {
+		slot.changed = true
+	}
+}
+
+// markError marks the last accessed account as errored.
+func (t *stateDiffTracer) markError() bool {
+	if t.last == nil {
+		return false
+	}
+	acc, ok := t.accounts[*t.last]
+	if !ok {
+		return false
+	}
+	acc.errored = true
+	t.last = nil
+	return true
+}
+
+// CapturePreEVM retrieves the transaction context from the inputs and looks up
+// the initial state of the sender, coinbase and recipient accounts.
+func (t *stateDiffTracer) CapturePreEVM(env *vm.EVM, inputs map[string]interface{}) {
+	t.db = env.StateDB
+
+	t.hasBalanceForValueAndGas, _ = inputs["hasFromSufficientBalanceForValueAndGasCost"].(bool)
+	t.hasBalanceForGas, _ = inputs["hasFromSufficientBalanceForGasCost"].(bool)
+	if coinbase, ok := inputs["coinbase"].(common.Address); ok {
+		t.coinbase, t.hasCoinbase = coinbase, true
+	}
+	if from, ok := inputs["from"].(common.Address); ok {
+		t.lookupAccount(from, "")
+	}
+	if t.hasCoinbase {
+		t.lookupAccount(t.coinbase, "")
+	}
+	// msgTo is the actual recipient of the transaction, unlike the to address
+	// of contract creations set by the EVM.
+	if to, ok := inputs["msgTo"].(common.Address); ok {
+		t.lookupAccount(to, "")
+	}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *stateDiffTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.db = env.StateDB
+
+	t.typ = "CALL"
+	if create {
+		t.typ = "CREATE"
+	}
+	t.from, t.to, t.value = from, to, value
+	if !t.hasCoinbase {
+		t.coinbase, t.hasCoinbase = env.Context.Coinbase, true
+	}
+	t.ctxCaptured = true
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *stateDiffTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.err != nil {
+		return
+	}
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.err = t.reason
+		return
+	}
+	callErr := env.CallErrorTemp
+	env.CallErrorTemp = nil
+
+	if err != nil || (callErr != nil && strings.Contains(callErr.Error(), "contract address collision")) {
+		if t.markError() {
+			return
+		}
+	}
+	// Whenever new state is accessed, add it to the state diff
+	var (
+		stack    = scope.Stack
+		contract = scope.Contract.Address()
+	)
+	switch op {
+	case vm.EXTCODECOPY, vm.EXTCODESIZE, vm.BALANCE:
+		t.lookupAccount(peekStack(stack, 0).Bytes20(), "")
+
+	case vm.CREATE:
+		t.lookupAccount(crypto.CreateAddress(contract, t.db.GetNonce(contract)), stateDiffBorn)
+
+	case vm.CREATE2:
+		code := memorySlice(scope.Memory, peekStack(stack, 1), peekStack(stack, 2))
+		t.lookupAccount(crypto.CreateAddress2(contract, peekStack(stack, 3).Bytes32(), crypto.Keccak256(code)), stateDiffBorn)
+
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		// No need to handle anything in precompiles, which also helps
+		// maintaining the last accessed account
+		if addr := common.Address(peekStack(stack, 1).Bytes20()); !isParityPrecompiled(addr) {
+			t.lookupAccount(addr, "")
+		}
+
+	case vm.SLOAD:
+		t.lookupStorage(contract, peekStack(stack, 0).Bytes32(), nil)
+
+	case vm.SSTORE:
+		val := common.Hash(peekStack(stack, 1).Bytes32())
+		t.lookupStorage(contract, peekStack(stack, 0).Bytes32(), &val)
+
+	case vm.SELFDESTRUCT:
+		t.lookupAccount(contract, stateDiffDied)
+	}
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault.
+func (t *stateDiffTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if t.err != nil {
+		return
+	}
+	env.CallErrorTemp = nil
+	t.markError()
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *stateDiffTracer) CaptureEnd(env *vm.EVM, output []byte, gasUsed uint64, d time.Duration, err error) {
+	t.db = env.StateDB
+}
+
+// GetResult returns the state changes of the transaction, or any error.
+func (t *stateDiffTracer) GetResult() (json.RawMessage, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	if !t.ctxCaptured {
+		return json.RawMessage("{}"), nil
+	}
+	// Get the final values of the sender, recipient and coinbase accounts
+	t.lookupAccount(t.from, "")
+	t.lookupAccount(t.to, "")
+	t.lookupAccount(t.coinbase, "")
+
+	// A transaction with value, whose sender can't even pay for the gas, is
+	// not run at all and doesn't change the state
+	if !t.hasBalanceForGas && !t.hasBalanceForValueAndGas && t.value != nil && t.value.Sign() > 0 {
+		return json.RawMessage("{}"), nil
+	}
+	// The sender, recipient and coinbase are always included, and are not
+	// updated anymore
+	if acc, ok := t.accounts[t.from]; ok {
+		acc.errored, acc.final = false, true
+	}
+	if acc, ok := t.accounts[t.to]; ok {
+		if t.typ == "CREATE" {
+			if acc.marker != stateDiffDied {
+				acc.marker = stateDiffBorn
+			} else {
+				// A created contract which was not persisted is left out
+				acc.removed = true
+			}
+		} else {
+			acc.errored = false
+		}
+		acc.final = true
+	}
+	if acc, ok := t.accounts[t.coinbase]; ok {
+		acc.errored, acc.final = false, true
+	}
+	return t.format()
+}
+
+// format assembles the state diff of all the tracked accounts.
+func (t *stateDiffTracer) format() (json.RawMessage, error) {
+	var out orderedJSON
+
+	for _, addr := range append([]common.Address{}, t.order...) {
+		// Fetch the latest values
+		t.lookupAccount(addr, "")
+
+		acc, ok := t.accounts[addr]
+		if !ok || acc.errored || acc.removed {
+			continue
+		}
+		// Accounts without any previous state are newly born
+		if acc.marker == stateDiffChanged && acc.balanceFrom.Sign() == 0 && *acc.codeFrom == "0x" && *acc.nonceFrom == 0 {
+			acc.marker = stateDiffBorn
+		}
+		var balance, nonce, code interface{}
+		if acc.marker == stateDiffChanged {
+			balance = formatChanged(bigHex(clampBig(acc.balanceFrom)), optionalBigHex(acc.balanceTo))
+			nonce = formatChanged(uintHex(*acc.nonceFrom), optionalUintHex(acc.nonceTo))
+			code = formatChanged(*acc.codeFrom, acc.codeTo)
+		} else {
+			balanceVal, nonceVal, codeVal := acc.balanceFrom, acc.nonceFrom, acc.codeFrom
+			if acc.marker != stateDiffDied {
+				if acc.balanceTo != nil {
+					balanceVal = acc.balanceTo
+				}
+				if acc.nonceTo != nil {
+					nonceVal = acc.nonceTo
+				}
+				if acc.codeTo != nil {
+					codeVal = acc.codeTo
+				}
+			}
+			balance = map[string]string{acc.marker: bigHex(clampBig(balanceVal))}
+			nonce = map[string]string{acc.marker: uintHex(*nonceVal)}
+			code = map[string]string{acc.marker: *codeVal}
+		}
+		if t.db.Empty(addr) {
+			continue
+		}
+		unchanged := balance == stateDiffSame && nonce == stateDiffSame && code == stateDiffSame
+		if unchanged && len(acc.storage) == 0 {
+			continue
+		}
+		// Handle the storage entries
+		var storage orderedJSON
+		for _, key := range acc.storageKeys {
+			// Fetch the latest value
+			t.lookupStorage(addr, key, nil)
+
+			slot := acc.storage[key]
+			if !slot.changed {
+				continue
+			}
+			switch {
+			case slot.from == slot.to && acc.marker != stateDiffBorn:
+				// Unchanged, or died keeping the same value
+				continue
+			case acc.marker == stateDiffChanged:
+				to := slot.to.Hex()
+				storage = append(storage, orderedJSONField{slot.from.Hex(), formatChanged(slot.from.Hex(), &to)})
+			case acc.marker == stateDiffDied:
+				storage = append(storage, orderedJSONField{slot.from.Hex(), map[string]string{acc.marker: slot.from.Hex()}})
+			default:
+				storage = append(storage, orderedJSONField{slot.from.Hex(), map[string]string{acc.marker: slot.to.Hex()}})
+			}
+		}
+		if unchanged && len(storage) == 0 {
+			continue
+		}
+		out = append(out, orderedJSONField{hexutil.Encode(addr.Bytes()), &stateDiffAccountResult{
+			Balance: balance,
+			Nonce:   nonce,
+			Code:    code,
+			Storage: storage,
+		}})
+	}
+	return json.Marshal(out)
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *stateDiffTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// stateDiffAccountResult is the state diff of a single account.
+type stateDiffAccountResult struct {
+	Balance interface{} `json:"balance"`
+	Nonce   interface{} `json:"nonce"`
+	Code    interface{} `json:"code"`
+	Storage orderedJSON `json:"storage"`
+}
+
+// stateDiffChange is a changed value of the state diff.
+type stateDiffChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// formatChanged formats a value of a changed account, which is the same if
+// its final value is unset.
+func formatChanged(from string, to *string) interface{} {
+	if to == nil || from == *to {
+		return stateDiffSame
+	}
+	return map[string]*stateDiffChange{stateDiffChanged: {From: from, To: *to}}
+}
+
+// clampBig returns zero for negative balances, which are possible as the
+// balance checks are skipped when tracing calls.
+func clampBig(n *big.Int) *big.Int {
+	if n.Sign() < 0 {
+		return new(big.Int)
+	}
+	return n
+}
+
+func optionalBigHex(n *big.Int) *string {
+	if n == nil {
+		return nil
+	}
+	return newString(bigHex(clampBig(n)))
+}
+
+func uintHex(n uint64) string {
+	return "0x" + strconv.FormatUint(n, 16)
+}
+
+func optionalUintHex(n *uint64) *string {
+	if n == nil {
+		return nil
+	}
+	return newString(uintHex(*n))
+}
+
+// orderedJSONField is a field of an orderedJSON object.
+type orderedJSONField struct {
+	key   string
+	value interface{}
+}
+
+// orderedJSON is a JSON object, whose fields are encoded in order.
+type orderedJSON []orderedJSONField
+
+// MarshalJSON implements json.Marshaler.
+func (o orderedJSON) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,355 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// TraceIndexSectionSize is the number of blocks in a single trace index section.
+	TraceIndexSectionSize = 4096
+
+	// TraceIndexConfirms is the number of confirmation blocks before a trace
+	// index section is considered final.
+	TraceIndexConfirms = 256
+
+	// traceIndexThrottling is the time to wait between processing two consecutive
+	// index sections, preventing the indexer from starving block processing.
+	traceIndexThrottling = 100 * time.Millisecond
+)
+
+// TraceIndexBackend is implemented by backends maintaining a trace index with
+// NewTraceIndexer, allowing trace_filter to skip blocks not touching the
+// filtered addresses.
+type TraceIndexBackend interface {
+	// TraceIndexStatus returns the section size and the number of sections
+	// of the trace index.
+	TraceIndexStatus() (uint64, uint64)
+}
+
+// TraceIndexer implements a core.ChainIndexer, recording which blocks contain
+// calls touching each address. Every block is executed on top of the state of
+// its parent, so historical states must be available or regenerable.
+type TraceIndexer struct {
+	api  *API
+	size uint64 // Section size to generate the index for
+
+	section uint64                    // Section being processed currently
+	head    common.Hash               // Hash of the last header processed
+	bits    map[common.Address][]byte // Blocks touching each address within the section
+
+	base      *state.StateDB // State of the parent of the last processed block
+	baseBlock common.Hash    // Hash of the block of the base state
+	baseRoot  common.Hash    // State root referenced for the base state
+}
+
+// NewTraceIndexer returns a chain indexer recording the addresses touched by
+// the calls of each block on the canonical chain.
+func NewTraceIndexer(backend Backend, size, confirms uint64) *core.ChainIndexer {
+	indexer := &TraceIndexer{
+		api:  NewAPI(backend),
+		size: size,
+	}
+	db := backend.ChainDb()
+	table := rawdb.NewTable(db, string(rawdb.TraceIndexPrefix))
+
+	return core.NewChainIndexer(db, table, indexer, size, confirms, traceIndexThrottling, "traceindex")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new trace index section.
+func (t *TraceIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	t.release()
+	t.section, t.head = section, common.Hash{}
+	t.bits = make(map[common.Address][]byte)
+	return nil
+}
+
+// Process implements core.ChainIndexerBackend, executing the block and adding
+// the addresses touched by its calls into the index.
+func (t *TraceIndexer) Process(ctx context.Context, header *types.Header) error {
+	block, err := t.api.backend.BlockByHash(ctx, header.Hash())
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return fmt.Errorf("block #%d %x not found", header.Number, header.Hash())
+	}
+	index := uint(block.NumberU64() - t.section*t.size)
+
+	// The genesis block executes nothing, but allocates the initial accounts.
+	// Their addresses are resolved through the preimages written along with the
+	// genesis state, which the state cache of the chain may not look up.
+	if block.NumberU64() == 0 {
+		statedb, err := state.New(block.Root(), state.NewDatabase(t.api.backend.ChainDb()), nil)
+		if err != nil {
+			return err
+		}
+		for addr := range statedb.RawDump(true, true, true).Accounts {
+			t.mark(addr, index)
+		}
+		t.head = block.Hash()
+		return nil
+	}
+	// Block rewards are traced too, so index their recipients
+	t.mark(block.Coinbase(), index)
+	for _, uncle := range block.Uncles() {
+		t.mark(uncle.Coinbase, index)
+	}
+	// Regenerate the state of the parent on top of the one of its own parent,
+	// as long as the blocks are processed in order.
+	parent, err := t.api.backend.BlockByHash(ctx, block.ParentHash())
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return fmt.Errorf("block #%d %x not found", block.NumberU64()-1, block.ParentHash())
+	}
+	if t.base != nil && t.baseBlock != parent.ParentHash() {
+		t.release()
+	}
+	statedb, err := t.api.backend.StateAtBlock(ctx, parent, defaultTraceReexec, t.base, false)
+	if err != nil {
+		return err
+	}
+	t.reference(statedb, parent)
+
+	var (
+		chainConfig = t.api.backend.ChainConfig()
+		signer      = types.MakeSigner(chainConfig, block.Number())
+		blockCtx    = core.NewEVMBlockContext(block.Header(), t.api.chainContext(ctx), nil)
+		tracer      = &traceIndexTracer{addresses: make(map[common.Address]struct{})}
+		execdb      = statedb.Copy()
+	)
+	for i, tx := range block.Transactions() {
+		msg, err := tx.AsMessage(signer, block.BaseFee())
+		if err != nil {
+			return err
+		}
+		execdb.Prepare(tx.Hash(), block.Hash(), i)
+		vmenv := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), execdb, chainConfig, vm.Config{Debug: true, Tracer: tracer})
+		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
+			return fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		}
+		execdb.Finalise(chainConfig.IsEnabled(chainConfig.GetEIP161dTransition, block.Number()))
+	}
+	for addr := range tracer.addresses {
+		t.mark(addr, index)
+	}
+	t.head = block.Hash()
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, finalizing the trace index section
+// and writing it out into the database.
+func (t *TraceIndexer) Commit() error {
+	batch := t.api.backend.ChainDb().NewBatch()
+	for addr, bits := range t.bits {
+		rawdb.WriteTraceIndexBits(batch, addr, t.section, t.head, bitutil.CompressBytes(bits))
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	return batch.Write()
+}
+
+// Prune returns an empty error since we don't support pruning here.
+func (t *TraceIndexer) Prune(threshold uint64) error {
+	return nil
+}
+
+// mark records the address as touched by the block at the given index of the section.
+func (t *TraceIndexer) mark(addr common.Address, index uint) {
+	bits, ok := t.bits[addr]
+	if !ok {
+		bits = make([]byte, t.size/8)
+		t.bits[addr] = bits
+	}
+	bits[index/8] |= 1 << (7 - index%8)
+}
+
+// reference holds the state of the block as the base for the next block,
+// releasing the previous one.
+func (t *TraceIndexer) reference(statedb *state.StateDB, block *types.Block) {
+	if triedb := statedb.Database().TrieDB(); triedb != nil {
+		triedb.Reference(block.Root(), common.Hash{})
+	}
+	t.release()
+	t.base, t.baseBlock, t.baseRoot = statedb, block.Hash(), block.Root()
+}
+
+// release drops the base state.
+func (t *TraceIndexer) release() {
+	if t.base != nil {
+		if triedb := t.base.Database().TrieDB(); triedb != nil {
+			triedb.Dereference(t.baseRoot)
+		}
+	}
+	t.base, t.baseBlock, t.baseRoot = nil, common.Hash{}, common.Hash{}
+}
+
+// traceIndexTracer collects the addresses touched by calls, creations and self
+// destructs during execution.
+type traceIndexTracer struct {
+	addresses map[common.Address]struct{}
+}
+
+// CapturePreEVM implements the Tracer interface.
+func (t *traceIndexTracer) CapturePreEVM(env *vm.EVM, inputs map[string]interface{}) {}
+
+// CaptureStart implements the Tracer interface, recording the sender and the
+// recipient of the transaction.
+func (t *traceIndexTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.addresses[from] = struct{}{}
+	t.addresses[to] = struct{}{}
+}
+
+// CaptureState implements the Tracer interface, recording the addresses
+// touched by the instruction.
+func (t *traceIndexTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if err != nil {
+		return
+	}
+	caller := scope.Contract.Address()
+	switch op {
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		t.addresses[caller] = struct{}{}
+		t.addresses[common.Address(peekStack(scope.Stack, 1).Bytes20())] = struct{}{}
+
+	case vm.CREATE:
+		t.addresses[caller] = struct{}{}
+		t.addresses[crypto.CreateAddress(caller, env.StateDB.GetNonce(caller))] = struct{}{}
+
+	case vm.CREATE2:
+		code := memorySlice(scope.Memory, peekStack(scope.Stack, 1), peekStack(scope.Stack, 2))
+
+		t.addresses[caller] = struct{}{}
+		t.addresses[crypto.CreateAddress2(caller, peekStack(scope.Stack, 3).Bytes32(), crypto.Keccak256(code))] = struct{}{}
+
+	case vm.SELFDESTRUCT:
+		t.addresses[caller] = struct{}{}
+		t.addresses[common.Address(peekStack(scope.Stack, 0).Bytes20())] = struct{}{}
+	}
+}
+
+// CaptureFault implements the Tracer interface.
+func (t *traceIndexTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnd implements the Tracer interface.
+func (t *traceIndexTracer) CaptureEnd(env *vm.EVM, output []byte, gasUsed uint64, _ time.Duration, err error) {
+}
+
+// traceIndexMatcher reports whether blocks may touch all the filtered addresses
+// according to the trace index. The bits of the index are loaded one section
+// at a time, as blocks are matched in order.
+type traceIndexMatcher struct {
+	db             ethdb.Database
+	size, sections uint64
+	addresses      []common.Address
+
+	section uint64 // Section whose bits are loaded
+	bits    []byte // Blocks of the section touching all the addresses, nil if not loaded
+}
+
+// matches reports whether the block with the given number may touch all the
+// filtered addresses. Blocks beyond the indexed sections always match.
+func (m *traceIndexMatcher) matches(number uint64) bool {
+	section := number / m.size
+	if section >= m.sections {
+		return true
+	}
+	if m.bits == nil || m.section != section {
+		bits, err := m.load(section)
+		if err != nil {
+			// Trace the whole section rather than skipping blocks by mistake
+			log.Warn("Failed to load trace index section", "section", section, "err", err)
+			bits = bytes.Repeat([]byte{0xff}, int(m.size/8))
+		}
+		m.section, m.bits = section, bits
+	}
+	index := number % m.size
+	return m.bits[index/8]&(1<<(7-index%8)) != 0
+}
+
+// load retrieves the blocks of the section touching all the filtered addresses.
+// Sections which aren't indexed up to their canonical head are reported as
+// errors, as the missing bits of an address don't mean it isn't touched then.
+func (m *traceIndexMatcher) load(section uint64) ([]byte, error) {
+	head := rawdb.ReadCanonicalHash(m.db, (section+1)*m.size-1)
+	if indexed := traceIndexSectionHead(m.db, section); indexed != head {
+		return nil, fmt.Errorf("section head %x not indexed, have %x", head, indexed)
+	}
+	var bits []byte
+	for _, addr := range m.addresses {
+		if ok, err := rawdb.HasTraceIndexBits(m.db, addr, section, head); err != nil {
+			return nil, err
+		} else if !ok {
+			// The address isn't touched within the section at all
+			return make([]byte, m.size/8), nil
+		}
+		comp, err := rawdb.ReadTraceIndexBits(m.db, addr, section, head)
+		if err != nil {
+			return nil, err
+		}
+		blob, err := bitutil.DecompressBytes(comp, int(m.size/8))
+		if err != nil {
+			return nil, err
+		}
+		if bits == nil {
+			bits = blob
+		} else {
+			bitutil.ANDBytes(bits, bits, blob)
+		}
+	}
+	if bits == nil {
+		bits = bytes.Repeat([]byte{0xff}, int(m.size/8))
+	}
+	return bits, nil
+}
+
+// traceIndexSectionHead retrieves the head of a section processed by the trace
+// indexer, as stored by its chain indexer once the section is committed.
+func traceIndexSectionHead(db ethdb.Database, section uint64) common.Hash {
+	var data [8]byte
+	binary.BigEndian.PutUint64(data[:], section)
+
+	hash, _ := rawdb.NewTable(db, string(rawdb.TraceIndexPrefix)).Get(append([]byte("shead"), data[:]...))
+	if len(hash) == common.HashLength {
+		return common.BytesToHash(hash)
+	}
+	return common.Hash{}
+}
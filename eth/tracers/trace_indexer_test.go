@@ -0,0 +1,123 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/vars"
+)
+
+func TestTraceIndexer(t *testing.T) {
+	t.Parallel()
+
+	// Every third block calls into the callee through the caller contract, the
+	// other blocks transfer to an unrelated account.
+	var (
+		accounts = newAccounts(2)
+		callee   = common.HexToAddress("0xc0ffee")
+		caller   = common.HexToAddress("0xca11e5")
+		other    = common.HexToAddress("0x07e5")
+
+		calleeCode = common.FromHex("602a60005500")
+		callerCode = append(append(common.FromHex("60006000600060006000"+"73"), callee.Bytes()...), common.FromHex("5af100")...)
+	)
+	genesis := &genesisT.Genesis{Alloc: genesisT.GenesisAlloc{
+		accounts[0].addr: {Balance: big.NewInt(vars.Ether)},
+		callee:           {Balance: big.NewInt(0), Code: calleeCode},
+		caller:           {Balance: big.NewInt(0), Code: callerCode},
+	}}
+	const size, blocks = 8, 20
+
+	signer := types.HomesteadSigner{}
+	backend := newTestBackend(t, blocks, genesis, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(accounts[1].addr)
+		to := other
+		if i%3 == 0 {
+			to = caller
+		}
+		tx, _ := types.SignTx(types.NewTransaction(uint64(i), to, big.NewInt(1), 100000, big.NewInt(0), nil), signer, accounts[0].key)
+		b.AddTx(tx)
+	})
+
+	// Index the full sections of the chain
+	indexer := NewTraceIndexer(backend, size, 0)
+	indexer.Start(backend.chain)
+	defer indexer.Close()
+
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if sections, _, _ := indexer.Sections(); sections == blocks/size {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("trace index sections not generated")
+		}
+	}
+	for _, test := range []struct {
+		addresses []common.Address
+		genesis   bool // Whether the genesis allocates all the addresses
+		matches   func(number uint64) bool
+	}{
+		// The callee is only touched by internal calls
+		{[]common.Address{callee}, true, func(number uint64) bool { return number%3 == 1 }},
+		{[]common.Address{accounts[0].addr, callee}, true, func(number uint64) bool { return number%3 == 1 }},
+		{[]common.Address{other}, false, func(number uint64) bool { return number%3 != 1 }},
+		{[]common.Address{callee, other}, false, func(number uint64) bool { return false }},
+		{[]common.Address{accounts[1].addr}, false, func(number uint64) bool { return true }},
+		{[]common.Address{common.HexToAddress("0xdead")}, false, func(number uint64) bool { return false }},
+		{nil, true, func(number uint64) bool { return true }},
+	} {
+		matcher := &traceIndexMatcher{
+			db:        backend.chaindb,
+			size:      size,
+			sections:  blocks / size,
+			addresses: test.addresses,
+		}
+		if have := matcher.matches(0); have != test.genesis {
+			t.Errorf("addresses %x, genesis: match mismatch: have %v, want %v", test.addresses, have, test.genesis)
+		}
+		for number := uint64(1); number <= blocks; number++ {
+			want := test.matches(number) || number >= blocks/size*size
+			if have := matcher.matches(number); have != want {
+				t.Errorf("addresses %x, block %d: match mismatch: have %v, want %v", test.addresses, number, have, want)
+			}
+		}
+	}
+	// Sections whose head isn't indexed are traced in full
+	table := rawdb.NewTable(backend.chaindb, string(rawdb.TraceIndexPrefix))
+	if err := table.Delete(append([]byte("shead"), make([]byte, 8)...)); err != nil {
+		t.Fatalf("failed to delete section head: %v", err)
+	}
+	matcher := &traceIndexMatcher{
+		db:        backend.chaindb,
+		size:      size,
+		sections:  blocks / size,
+		addresses: []common.Address{common.HexToAddress("0xdead")},
+	}
+	for number := uint64(0); number < blocks/size*size; number++ {
+		if want := number < size; matcher.matches(number) != want {
+			t.Errorf("block %d: match mismatch without section head: have %v, want %v", number, !want, want)
+		}
+	}
+}
@@ -14,19 +14,42 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
-// Package tracers is a collection of JavaScript transaction tracers.
+// Package tracers is a collection of JavaScript and native transaction tracers.
 package tracers
 
 import (
+	"encoding/json"
 	"strings"
 	"unicode"
 
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers/internal/tracers"
 )
 
+// ResultTracer is a vm.Tracer assembling a JSON result, which can be interrupted.
+// It is implemented by both the JavaScript and the native tracers.
+type ResultTracer interface {
+	vm.Tracer
+
+	// GetResult returns the result of the tracing, or any accumulated error.
+	GetResult() (json.RawMessage, error)
+
+	// Stop terminates the tracing at the first opportune moment.
+	Stop(err error)
+}
+
 // all contains all the built in JavaScript tracers by name.
 var all = make(map[string]string)
 
+// native contains the built in tracers implemented in Go by name. They take
+// precedence over the JavaScript tracers of the same name, whose results they
+// reproduce exactly.
+var native = map[string]func() ResultTracer{
+	"callTracerParity": newParityCallTracer,
+	"stateDiffTracer":  newStateDiffTracer,
+	"vmTracerParity":   newParityVMTracer,
+}
+
 // camel converts a snake cased input string into a camel cased output.
 func camel(str string) string {
 	pieces := strings.Split(str, "_")
@@ -51,3 +74,16 @@ func tracer(name string) (string, bool) {
 	}
 	return "", false
 }
+
+// newTracer creates the tracer with the given name, or the JavaScript tracer
+// described by code, preferring the native implementation of built in tracers.
+func newTracer(code string, txCtx vm.TxContext) (ResultTracer, error) {
+	if constructor, ok := native[code]; ok {
+		return constructor(), nil
+	}
+	tracer, err := New(code, txCtx)
+	if err != nil {
+		return nil, err
+	}
+	return tracer, nil
+}
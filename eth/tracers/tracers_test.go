@@ -17,6 +17,7 @@
 package tracers
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/json"
@@ -25,6 +26,7 @@ import (
 	"math/big"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -228,7 +230,7 @@ func TestPrestateTracerCreate2(t *testing.T) {
 	}
 	evm := vm.NewEVM(context, txContext, statedb, params.MainnetChainConfig, vm.Config{Debug: true, Tracer: tracer})
 
-	msg, err := tx.AsMessage(signer)
+	msg, err := tx.AsMessage(signer, nil)
 	if err != nil {
 		t.Fatalf("failed to prepare transaction for tracing: %v", err)
 	}
@@ -303,7 +305,7 @@ func TestCallTracer(t *testing.T) {
 			}
 			evm := vm.NewEVM(context, txContext, statedb, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
 
-			msg, err := tx.AsMessage(signer)
+			msg, err := tx.AsMessage(signer, nil)
 			if err != nil {
 				t.Fatalf("failed to prepare transaction for tracing: %v", err)
 			}
@@ -350,20 +352,54 @@ func jsonEqual(x, y interface{}) bool {
 	return reflect.DeepEqual(xTrace, yTrace)
 }
 
-func callTracerParityTestRunner(filename string) error {
+// tracerImplementations are the implementations of the tracers which exist
+// both in JavaScript and natively, and must produce identical results.
+var tracerImplementations = []string{"js", "native"}
+
+// testTracer returns a constructor of the given implementation of a named tracer.
+func testTracer(impl, name string) func(vm.TxContext) (ResultTracer, error) {
+	if impl == "native" {
+		return func(vm.TxContext) (ResultTracer, error) { return native[name](), nil }
+	}
+	return func(txCtx vm.TxContext) (ResultTracer, error) { return New(name, txCtx) }
+}
+
+func callTracerParityTestRunner(filename string, newTracer func(vm.TxContext) (ResultTracer, error)) error {
+	res, test, err := runCallTracerParityTest(filename, newTracer)
+	if err != nil {
+		return err
+	}
+	ret := new([]callTraceParity)
+	if err := json.Unmarshal(res, ret); err != nil {
+		return fmt.Errorf("failed to unmarshal trace result: %v", err)
+	}
+
+	if !jsonEqualParity(ret, test.Result) {
+		// uncomment this for easier debugging
+		// have, _ := json.MarshalIndent(ret, "", " ")
+		// want, _ := json.MarshalIndent(test.Result, "", " ")
+		// return fmt.Errorf("trace mismatch: \nhave %+v\nwant %+v", string(have), string(want))
+		return fmt.Errorf("trace mismatch: \nhave %+v\nwant %+v", ret, test.Result)
+	}
+	return nil
+}
+
+// runCallTracerParityTest executes the transaction of a parity call tracer test case,
+// returning the raw trace result.
+func runCallTracerParityTest(filename string, newTracer func(vm.TxContext) (ResultTracer, error)) (json.RawMessage, *callTracerParityTest, error) {
 	// Call tracer test found, read if from disk
 	blob, err := ioutil.ReadFile(filepath.Join("testdata", filename))
 	if err != nil {
-		return fmt.Errorf("failed to read testcase: %v", err)
+		return nil, nil, fmt.Errorf("failed to read testcase: %v", err)
 	}
 	test := new(callTracerParityTest)
 	if err := json.Unmarshal(blob, test); err != nil {
-		return fmt.Errorf("failed to parse testcase: %v", err)
+		return nil, nil, fmt.Errorf("failed to parse testcase: %v", err)
 	}
 	// Configure a blockchain with the given prestate
 	tx := new(types.Transaction)
 	if err := rlp.DecodeBytes(common.FromHex(test.Input), tx); err != nil {
-		return fmt.Errorf("failed to parse testcase input: %v", err)
+		return nil, nil, fmt.Errorf("failed to parse testcase input: %v", err)
 	}
 	signer := types.MakeSigner(test.Genesis.Config, new(big.Int).SetUint64(uint64(test.Context.Number)))
 	origin, _ := signer.Sender(tx)
@@ -383,44 +419,40 @@ func callTracerParityTestRunner(filename string) error {
 	_, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), test.Genesis.Alloc, false)
 
 	// Create the tracer, the EVM environment and run it
-	tracer, err := New("callTracerParity", txContext)
+	tracer, err := newTracer(txContext)
 	if err != nil {
-		return fmt.Errorf("failed to create call tracer: %v", err)
+		return nil, nil, fmt.Errorf("failed to create call tracer: %v", err)
 	}
 	evm := vm.NewEVM(context, txContext, statedb, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
 
-	msg, err := tx.AsMessage(signer)
+	msg, err := tx.AsMessage(signer, nil)
 	if err != nil {
-		return fmt.Errorf("failed to prepare transaction for tracing: %v", err)
-	}
+		return nil, nil, fmt.Errorf("failed to prepare transaction for tracing: %v", err)
+	}
+	tracer.CapturePreEVM(evm, map[string]interface{}{
+		"transactionHash":     tx.Hash().Hex(),
+		"transactionPosition": uint64(0),
+		"from":                msg.From(),
+		"coinbase":            context.Coinbase,
+		"gasLimit":            msg.Gas(),
+		"gasPrice":            msg.GasPrice(),
+	})
 	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(tx.Gas()))
 
 	if _, err = st.TransitionDb(); err != nil {
-		return fmt.Errorf("failed to execute transaction: %v", err)
+		return nil, nil, fmt.Errorf("failed to execute transaction: %v", err)
 	}
 
-	// Retrieve the trace result and compare against the etalon
+	// Retrieve the trace result
 	res, err := tracer.GetResult()
 	if err != nil {
-		return fmt.Errorf("failed to retrieve trace result: %v", err)
-	}
-	ret := new([]callTraceParity)
-	if err := json.Unmarshal(res, ret); err != nil {
-		return fmt.Errorf("failed to unmarshal trace result: %v", err)
+		return nil, nil, fmt.Errorf("failed to retrieve trace result: %v", err)
 	}
-
-	if !jsonEqualParity(ret, test.Result) {
-		// uncomment this for easier debugging
-		// have, _ := json.MarshalIndent(ret, "", " ")
-		// want, _ := json.MarshalIndent(test.Result, "", " ")
-		// return fmt.Errorf("trace mismatch: \nhave %+v\nwant %+v", string(have), string(want))
-		return fmt.Errorf("trace mismatch: \nhave %+v\nwant %+v", ret, test.Result)
-	}
-	return nil
+	return res, test, nil
 }
 
 // Iterates over all the input-output datasets in the tracer parity test harness and
-// runs the JavaScript tracers against them.
+// runs the JavaScript and native tracers against them.
 func TestCallTracerParity(t *testing.T) {
 	files, err := ioutil.ReadDir("testdata")
 	if err != nil {
@@ -431,14 +463,17 @@ func TestCallTracerParity(t *testing.T) {
 			continue
 		}
 		file := file // capture range variable
-		t.Run(camel(strings.TrimSuffix(strings.TrimPrefix(file.Name(), "parity_call_tracer_"), ".json")), func(t *testing.T) {
-			t.Parallel()
+		for _, impl := range tracerImplementations {
+			impl := impl // capture range variable
+			t.Run(camel(strings.TrimSuffix(strings.TrimPrefix(file.Name(), "parity_call_tracer_"), ".json"))+"/"+impl, func(t *testing.T) {
+				t.Parallel()
 
-			err := callTracerParityTestRunner(file.Name())
-			if err != nil {
-				t.Fatal(err)
-			}
-		})
+				err := callTracerParityTestRunner(file.Name(), testTracer(impl, "callTracerParity"))
+				if err != nil {
+					t.Fatal(err)
+				}
+			})
+		}
 	}
 }
 
@@ -468,14 +503,17 @@ func BenchmarkCallTracerParity(b *testing.B) {
 
 	for _, file := range files {
 		filename := strings.TrimPrefix(file, "testdata/")
-		b.Run(camel(strings.TrimSuffix(strings.TrimPrefix(filename, "parity_call_tracer_"), ".json")), func(b *testing.B) {
-			for n := 0; n < b.N; n++ {
-				err := callTracerParityTestRunner(filename)
-				if err != nil {
-					b.Fatal(err)
+		for _, impl := range tracerImplementations {
+			newTracer := testTracer(impl, "callTracerParity")
+			b.Run(camel(strings.TrimSuffix(strings.TrimPrefix(filename, "parity_call_tracer_"), ".json"))+"/"+impl, func(b *testing.B) {
+				for n := 0; n < b.N; n++ {
+					err := callTracerParityTestRunner(filename, newTracer)
+					if err != nil {
+						b.Fatal(err)
+					}
 				}
-			}
-		})
+			})
+		}
 	}
 }
 
@@ -493,19 +531,44 @@ type stateDiffTest struct {
 	Result  map[common.Address]*stateDiffAccount `json:"result"`
 }
 
-func stateDiffTracerTestRunner(filename string) error {
+func stateDiffTracerTestRunner(filename string, newTracer func(vm.TxContext) (ResultTracer, error)) error {
+	res, test, err := runStateDiffTracerTest(filename, newTracer)
+	if err != nil {
+		return err
+	}
+	ret := new(map[common.Address]*stateDiffAccount)
+	if err := json.Unmarshal(res, ret); err != nil {
+		return fmt.Errorf("failed to unmarshal trace result: %v", err)
+	}
+
+	if !jsonEqualStateDiff(ret, test.Result) {
+		// uncomment this for easier debugging
+		// have, _ := json.MarshalIndent(ret, "", " ")
+		// want, _ := json.MarshalIndent(test.Result, "", " ")
+		// return fmt.Errorf("trace mismatch: \nhave %+v\nwant %+v", string(have), string(want))
+		return fmt.Errorf("trace mismatch: \nhave %+v\nwant %+v", ret, test.Result)
+	}
+	return nil
+}
+
+// runStateDiffTracerTest executes the state diff testcase in the given file,
+// returning the raw trace result.
+func runStateDiffTracerTest(filename string, newTracer func(vm.TxContext) (ResultTracer, error)) (json.RawMessage, *stateDiffTest, error) {
 	// Call tracer test found, read if from disk
 	blob, err := ioutil.ReadFile(filepath.Join("testdata", filename))
 	if err != nil {
-		return fmt.Errorf("failed to read testcase: %v", err)
+		return nil, nil, fmt.Errorf("failed to read testcase: %v", err)
 	}
 	test := new(stateDiffTest)
 	if err := json.Unmarshal(blob, test); err != nil {
-		return fmt.Errorf("failed to parse testcase: %v", err)
+		return nil, nil, fmt.Errorf("failed to parse testcase: %v", err)
 	}
 
 	// Configure a blockchain with the given prestate
-	msg := test.Input.ToMessage(uint64(test.Context.GasLimit))
+	msg, err := test.Input.ToMessage(uint64(test.Context.GasLimit), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create call message: %v", err)
+	}
 
 	// This is needed for trace_call (debug mode),
 	// as the Transaction is being run on top of the block transactions,
@@ -565,9 +628,9 @@ func stateDiffTracerTestRunner(filename string) error {
 	}
 
 	// Create the tracer, the EVM environment and run it
-	tracer, err := New("stateDiffTracer", txContext)
+	tracer, err := newTracer(txContext)
 	if err != nil {
-		return fmt.Errorf("failed to create state diff tracer: %v", err)
+		return nil, nil, fmt.Errorf("failed to create state diff tracer: %v", err)
 	}
 	evm := vm.NewEVM(context, txContext, statedb, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
 
@@ -575,31 +638,19 @@ func stateDiffTracerTestRunner(filename string) error {
 
 	st := core.NewStateTransition(evm, msg, new(core.GasPool).AddGas(msg.Gas()))
 	if _, err = st.TransitionDb(); err != nil {
-		return fmt.Errorf("failed to execute transaction: %v", err)
+		return nil, nil, fmt.Errorf("failed to execute transaction: %v", err)
 	}
 
-	// Retrieve the trace result and compare against the etalon
+	// Retrieve the trace result
 	res, err := tracer.GetResult()
 	if err != nil {
-		return fmt.Errorf("failed to retrieve trace result: %v", err)
-	}
-	ret := new(map[common.Address]*stateDiffAccount)
-	if err := json.Unmarshal(res, ret); err != nil {
-		return fmt.Errorf("failed to unmarshal trace result: %v", err)
-	}
-
-	if !jsonEqualStateDiff(ret, test.Result) {
-		// uncomment this for easier debugging
-		// have, _ := json.MarshalIndent(ret, "", " ")
-		// want, _ := json.MarshalIndent(test.Result, "", " ")
-		// return fmt.Errorf("trace mismatch: \nhave %+v\nwant %+v", string(have), string(want))
-		return fmt.Errorf("trace mismatch: \nhave %+v\nwant %+v", ret, test.Result)
+		return nil, nil, fmt.Errorf("failed to retrieve trace result: %v", err)
 	}
-	return nil
+	return res, test, nil
 }
 
 // TestStateDiffTracer Iterates over all the input-output datasets in the state diff tracer test harness and
-// runs the JavaScript tracers against them.
+// runs the JavaScript and native tracers against them.
 func TestStateDiffTracer(t *testing.T) {
 	files, err := ioutil.ReadDir("testdata")
 	if err != nil {
@@ -610,13 +661,66 @@ func TestStateDiffTracer(t *testing.T) {
 			continue
 		}
 		file := file // capture range variable
-		t.Run(camel(strings.TrimSuffix(strings.TrimPrefix(file.Name(), "state_diff_tracer_"), ".json")), func(t *testing.T) {
+		for _, impl := range tracerImplementations {
+			newTracer := testTracer(impl, "stateDiffTracer")
+			t.Run(camel(strings.TrimSuffix(strings.TrimPrefix(file.Name(), "state_diff_tracer_"), ".json"))+"/"+impl, func(t *testing.T) {
+				t.Parallel()
+
+				err := stateDiffTracerTestRunner(file.Name(), newTracer)
+				if err != nil {
+					t.Fatal(err)
+				}
+			})
+		}
+	}
+}
+
+// traceTimeRe matches the execution time reported by the parity call tracer.
+var traceTimeRe = regexp.MustCompile(`"time":"[^"]*"`)
+
+// TestNativeTracerIdentity checks that the native tracers produce byte-identical
+// results to their JavaScript counterparts, apart from execution times.
+func TestNativeTracerIdentity(t *testing.T) {
+	files, err := ioutil.ReadDir("testdata")
+	if err != nil {
+		t.Fatalf("failed to retrieve tracer test suite: %v", err)
+	}
+	for _, file := range files {
+		var (
+			tracer string
+			run    func(filename string, newTracer func(vm.TxContext) (ResultTracer, error)) (json.RawMessage, error)
+		)
+		switch {
+		case strings.HasPrefix(file.Name(), "parity_call_tracer_"):
+			tracer = "callTracerParity"
+			run = func(filename string, newTracer func(vm.TxContext) (ResultTracer, error)) (json.RawMessage, error) {
+				res, _, err := runCallTracerParityTest(filename, newTracer)
+				return traceTimeRe.ReplaceAll(res, []byte(`"time":""`)), err
+			}
+		case strings.HasPrefix(file.Name(), "state_diff_tracer_"):
+			tracer = "stateDiffTracer"
+			run = func(filename string, newTracer func(vm.TxContext) (ResultTracer, error)) (json.RawMessage, error) {
+				res, _, err := runStateDiffTracerTest(filename, newTracer)
+				return res, err
+			}
+		default:
+			continue
+		}
+		file := file // capture range variable
+		t.Run(camel(strings.TrimSuffix(file.Name(), ".json")), func(t *testing.T) {
 			t.Parallel()
 
-			err := stateDiffTracerTestRunner(file.Name())
+			want, err := run(file.Name(), testTracer("js", tracer))
+			if err != nil {
+				t.Fatal(err)
+			}
+			have, err := run(file.Name(), testTracer("native", tracer))
 			if err != nil {
 				t.Fatal(err)
 			}
+			if !bytes.Equal(have, want) {
+				t.Fatalf("native result mismatch:\nhave %s\nwant %s", have, want)
+			}
 		})
 	}
 }
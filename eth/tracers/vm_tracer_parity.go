@@ -0,0 +1,274 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/holiman/uint256"
+)
+
+// parityVMTrace is the trace of the code executed in a call frame, in the
+// format of Parity's vmTrace.
+type parityVMTrace struct {
+	Code hexutil.Bytes        `json:"code"`
+	Ops  []*parityVMOperation `json:"ops"`
+}
+
+// parityVMOperation is a single executed instruction, with the trace of the
+// call frame it entered, if any.
+type parityVMOperation struct {
+	Cost uint64                     `json:"cost"`
+	Ex   *parityVMExecutedOperation `json:"ex"` // Unset if the instruction failed
+	Pc   uint64                     `json:"pc"`
+	Sub  *parityVMTrace             `json:"sub"`
+}
+
+// parityVMExecutedOperation holds the effects of an executed instruction.
+type parityVMExecutedOperation struct {
+	Mem   *parityMemoryDiff  `json:"mem"`
+	Push  []*hexutil.Big     `json:"push"`
+	Store *parityStorageDiff `json:"store"`
+	Used  uint64             `json:"used"` // Gas remaining after the instruction
+}
+
+// parityMemoryDiff is a region of memory written by an instruction.
+type parityMemoryDiff struct {
+	Data hexutil.Bytes `json:"data"`
+	Off  uint64        `json:"off"`
+}
+
+// parityStorageDiff is a storage slot written by an instruction.
+type parityStorageDiff struct {
+	Key *hexutil.Big `json:"key"`
+	Val *hexutil.Big `json:"val"`
+}
+
+// parityVMFrame tracks the instruction of a call frame awaiting its effects,
+// which are only known once the next instruction of the frame is reached.
+type parityVMFrame struct {
+	trace *parityVMTrace
+
+	pending   *parityVMOperation // Instruction awaiting its effects
+	pendingOp vm.OpCode
+	gas, cost uint64
+	mem       *[2]uint64 // Offset and size of the memory written by the instruction
+	store     *parityStorageDiff
+}
+
+// parityVMTracer is a native tracer producing Parity's vmTrace: the nested
+// trace of all instructions executed, along with the stack items pushed and
+// the memory and storage written by each.
+type parityVMTracer struct {
+	root   *parityVMTrace
+	frames []*parityVMFrame // Call frames being executed, by depth
+
+	err       error  // Error, if one has occurred
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// newParityVMTracer creates a native Parity vmTrace tracer.
+func newParityVMTracer() ResultTracer {
+	return &parityVMTracer{}
+}
+
+// CapturePreEVM implements the Tracer interface. The vmTrace tracer needs no
+// transaction context.
+func (t *parityVMTracer) CapturePreEVM(env *vm.EVM, inputs map[string]interface{}) {}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (t *parityVMTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	code := input
+	if !create {
+		code = env.StateDB.GetCode(to)
+	}
+	t.root = &parityVMTrace{Code: common.CopyBytes(code), Ops: []*parityVMOperation{}}
+	t.frames = []*parityVMFrame{{trace: t.root}}
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (t *parityVMTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.err != nil || len(t.frames) == 0 {
+		return
+	}
+	if atomic.LoadUint32(&t.interrupt) > 0 {
+		t.err = t.reason
+		return
+	}
+	// Settle the call frames which returned since the last instruction
+	for len(t.frames) > depth {
+		t.frames[len(t.frames)-1].settleReturn()
+		t.frames = t.frames[:len(t.frames)-1]
+	}
+	// Enter the call frame created by the pending instruction of the caller
+	if depth > len(t.frames) {
+		sub := &parityVMTrace{Code: common.CopyBytes(scope.Contract.Code), Ops: []*parityVMOperation{}}
+		if caller := t.frames[len(t.frames)-1]; caller.pending != nil {
+			caller.pending.Sub = sub
+		}
+		t.frames = append(t.frames, &parityVMFrame{trace: sub})
+	}
+	frame := t.frames[len(t.frames)-1]
+	frame.settle(gas, scope)
+
+	operation := &parityVMOperation{Cost: cost, Pc: pc}
+	frame.trace.Ops = append(frame.trace.Ops, operation)
+
+	// Instructions failing before execution have no effects
+	if err != nil {
+		return
+	}
+	frame.pending, frame.pendingOp, frame.gas, frame.cost = operation, op, gas, cost
+	frame.mem, frame.store = parityMemoryWritten(op, scope.Stack), nil
+	if op == vm.SSTORE {
+		frame.store = &parityStorageDiff{
+			Key: (*hexutil.Big)(peekStack(scope.Stack, 0).ToBig()),
+			Val: (*hexutil.Big)(peekStack(scope.Stack, 1).ToBig()),
+		}
+	}
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (t *parityVMTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if t.err != nil || len(t.frames) == 0 {
+		return
+	}
+	for len(t.frames) > depth {
+		t.frames[len(t.frames)-1].settleReturn()
+		t.frames = t.frames[:len(t.frames)-1]
+	}
+	// A revert is a regular return as far as the instruction is concerned
+	if errors.Is(err, vm.ErrExecutionReverted) {
+		return
+	}
+	t.frames[len(t.frames)-1].pending = nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (t *parityVMTracer) CaptureEnd(env *vm.EVM, output []byte, gasUsed uint64, _ time.Duration, err error) {
+	for len(t.frames) > 0 {
+		t.frames[len(t.frames)-1].settleReturn()
+		t.frames = t.frames[:len(t.frames)-1]
+	}
+}
+
+// GetResult returns the vmTrace of the execution.
+func (t *parityVMTracer) GetResult() (json.RawMessage, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	return json.Marshal(t.root)
+}
+
+// Stop terminates execution of the tracer at the first opportune moment.
+func (t *parityVMTracer) Stop(err error) {
+	t.reason = err
+	atomic.StoreUint32(&t.interrupt, 1)
+}
+
+// settle records the effects of the pending instruction, given the state of
+// the frame at its next instruction.
+func (f *parityVMFrame) settle(gas uint64, scope *vm.ScopeContext) {
+	if f.pending == nil {
+		return
+	}
+	ex := &parityVMExecutedOperation{Push: []*hexutil.Big{}, Store: f.store, Used: gas}
+
+	stack := scope.Stack.Data()
+	n := parityPushCount(f.pendingOp)
+	if n > len(stack) {
+		n = len(stack)
+	}
+	for _, item := range stack[len(stack)-n:] {
+		ex.Push = append(ex.Push, (*hexutil.Big)(item.ToBig()))
+	}
+	if f.mem != nil {
+		off, size := f.mem[0], f.mem[1]
+		if size > 0 && off+size >= off && off+size <= uint64(scope.Memory.Len()) {
+			ex.Mem = &parityMemoryDiff{Data: scope.Memory.GetCopy(int64(off), int64(size)), Off: off}
+		}
+	}
+	f.pending.Ex = ex
+	f.pending = nil
+}
+
+// settleReturn records the effects of the last instruction of a returning frame.
+func (f *parityVMFrame) settleReturn() {
+	if f.pending == nil {
+		return
+	}
+	f.pending.Ex = &parityVMExecutedOperation{Push: []*hexutil.Big{}, Store: f.store, Used: f.gas - f.cost}
+	f.pending = nil
+}
+
+// parityPushCount returns the number of stack items reported as pushed by an
+// instruction. As in Parity, duplications and swaps report all stack items
+// they touched.
+func parityPushCount(op vm.OpCode) int {
+	switch {
+	case op >= vm.PUSH1 && op <= vm.PUSH32:
+		return 1
+	case op >= vm.DUP1 && op <= vm.DUP16:
+		return int(op-vm.DUP1) + 2
+	case op >= vm.SWAP1 && op <= vm.SWAP16:
+		return int(op-vm.SWAP1) + 2
+	case op >= vm.LOG0 && op <= vm.LOG4:
+		return 0
+	}
+	switch op {
+	case vm.STOP, vm.POP, vm.MSTORE, vm.MSTORE8, vm.SSTORE, vm.JUMP, vm.JUMPI, vm.JUMPDEST,
+		vm.CALLDATACOPY, vm.CODECOPY, vm.EXTCODECOPY, vm.RETURNDATACOPY,
+		vm.RETURN, vm.REVERT, vm.SELFDESTRUCT:
+		return 0
+	}
+	return 1
+}
+
+// parityMemoryWritten returns the offset and size of the memory an instruction
+// is about to write, as reported by Parity, or nil if it writes none.
+func parityMemoryWritten(op vm.OpCode, stack *vm.Stack) *[2]uint64 {
+	var off, size *uint256.Int
+	switch op {
+	case vm.MSTORE, vm.MLOAD:
+		off, size = peekStack(stack, 0), uint256.NewInt().SetUint64(32)
+	case vm.MSTORE8:
+		off, size = peekStack(stack, 0), uint256.NewInt().SetUint64(1)
+	case vm.CALLDATACOPY, vm.CODECOPY, vm.RETURNDATACOPY:
+		off, size = peekStack(stack, 0), peekStack(stack, 2)
+	case vm.EXTCODECOPY:
+		off, size = peekStack(stack, 1), peekStack(stack, 3)
+	case vm.CALL, vm.CALLCODE:
+		off, size = peekStack(stack, 5), peekStack(stack, 6)
+	case vm.DELEGATECALL, vm.STATICCALL:
+		off, size = peekStack(stack, 4), peekStack(stack, 5)
+	default:
+		return nil
+	}
+	if !off.IsUint64() || !size.IsUint64() {
+		return nil
+	}
+	return &[2]uint64{off.Uint64(), size.Uint64()}
+}
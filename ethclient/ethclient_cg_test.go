@@ -1,23 +1,34 @@
 package ethclient
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"reflect"
 	"regexp"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/confp"
+	"github.com/ethereum/go-ethereum/params/confp/generic"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/types/goethereum"
 )
 
 // TestEthGetBlockByNumber_ValidJSONResponse tests that
@@ -216,3 +227,169 @@ func TestUncleResponseEncoding(t *testing.T) {
 		}
 	}
 }
+
+// newTestBackendWithConfig duplicates the logic of newTestBackend, except that it generates a backend
+// on top of a chain running the given configuration, with the given accounts allocated next to the
+// test account.
+func newTestBackendWithConfig(t *testing.T, chainConfig ctypes.ChainConfigurator, alloc genesisT.GenesisAlloc) (*node.Node, []*types.Block) {
+	// Generate test chain.
+	db := rawdb.NewMemoryDatabase()
+	genesisAlloc := genesisT.GenesisAlloc{testAddr: {Balance: testBalance}}
+	for addr, account := range alloc {
+		genesisAlloc[addr] = account
+	}
+	genesis := &genesisT.Genesis{
+		Config:    chainConfig,
+		Alloc:     genesisAlloc,
+		ExtraData: []byte("test genesis"),
+		Timestamp: 9000,
+	}
+	gblock := core.GenesisToBlock(genesis, db)
+	blocks, _ := core.GenerateChain(chainConfig, gblock, ethash.NewFaker(), db, 1, func(i int, g *core.BlockGen) {
+		g.OffsetTime(5)
+	})
+	blocks = append([]*types.Block{gblock}, blocks...)
+
+	// Create node
+	n, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatalf("can't create new node: %v", err)
+	}
+	// Create Ethereum Service
+	config := &eth.Config{Genesis: genesis}
+	config.Ethash.PowMode = ethash.ModeFake
+	ethservice, err := eth.New(n, config)
+	if err != nil {
+		t.Fatalf("can't create new ethereum service: %v", err)
+	}
+	// Import the test chain.
+	if err := n.Start(); err != nil {
+		t.Fatalf("can't start test node: %v", err)
+	}
+	if _, err := ethservice.BlockChain().InsertChain(blocks[1:]); err != nil {
+		t.Fatalf("can't import test blocks: %v", err)
+	}
+	return n, blocks
+}
+
+// TestChainConfig tests that eth_chainConfig returns the chain configuration in
+// each supported format, along with its forks and the fork ID of the head.
+func TestChainConfig(t *testing.T) {
+	chainConfig := *params.AllEthashProtocolChanges
+	chainConfig.YoloV3Block = nil // EIP-2315 is not supported by every format
+	chainConfig.LondonBlock = big.NewInt(100)
+	backend, chain := newTestBackendWithConfig(t, &chainConfig, nil)
+	client, _ := backend.Attach()
+	defer backend.Close()
+	defer client.Close()
+
+	type result struct {
+		Format string                   `json:"format"`
+		Config json.RawMessage          `json:"config"`
+		Forks  []ethapi.ChainConfigFork `json:"forks"`
+		ForkID ethapi.ChainConfigForkID `json:"forkId"`
+	}
+	head := chain[len(chain)-1].NumberU64()
+	id := forkid.NewID(&chainConfig, chain[0].Hash(), head)
+	if id.Next != 100 {
+		t.Fatalf("bad test: next fork %d, want 100", id.Next)
+	}
+	for _, format := range append([]string{"", "native"}, generic.ChainConfiguratorFormats()...) {
+		var res result
+		if err := client.CallContext(context.Background(), &res, "eth_chainConfig", format); err != nil {
+			t.Fatalf("format %q: %v", format, err)
+		}
+		want := format
+		if want == "" {
+			want = "native"
+		}
+		if res.Format != want {
+			t.Errorf("format %q: result format mismatch: have %s, want %s", format, res.Format, want)
+		}
+		// The configuration decodes to the type of its format and keeps the forks of the chain.
+		var config ctypes.ChainConfigurator = &goethereum.ChainConfig{}
+		if want != "native" {
+			converted, err := generic.ConvertChainConfigurator(&chainConfig, want)
+			if err != nil {
+				t.Fatalf("format %q: %v", format, err)
+			}
+			config = reflect.New(reflect.TypeOf(converted).Elem()).Interface().(ctypes.ChainConfigurator)
+		}
+		if err := json.Unmarshal(res.Config, config); err != nil {
+			t.Fatalf("format %q: failed to decode config: %v", format, err)
+		}
+		if have, want := confp.Forks(config), confp.Forks(&chainConfig); !reflect.DeepEqual(have, want) {
+			t.Errorf("format %q: forks mismatch: have %v, want %v", format, have, want)
+		}
+		// The forks at genesis are activated, while London is scheduled past the head.
+		london := false
+		for _, fork := range res.Forks {
+			switch {
+			case fork.Block == 0 && fork.Activated:
+			case fork.Block == 100 && !fork.Activated:
+				london = london || fork.Name == "EIP1559"
+			default:
+				t.Errorf("format %q: unexpected fork %s at block %d (activated %v)", format, fork.Name, fork.Block, fork.Activated)
+			}
+		}
+		if !london {
+			t.Errorf("format %q: EIP1559 not scheduled at block 100: %+v", format, res.Forks)
+		}
+		if !bytes.Equal(res.ForkID.Hash, id.Hash[:]) || uint64(res.ForkID.Next) != id.Next {
+			t.Errorf("format %q: fork id mismatch: have %x/%d, want %x/%d", format, []byte(res.ForkID.Hash), res.ForkID.Next, id.Hash, id.Next)
+		}
+	}
+	var res result
+	if err := client.CallContext(context.Background(), &res, "eth_chainConfig", "aleth"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+// TestCreateAccessList tests that access lists are created for calls, and that the
+// gas reported for them matches the estimate of the call with the list attached.
+func TestCreateAccessList(t *testing.T) {
+	var (
+		balanceAddr = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		revertAddr  = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		otherAddr   = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	)
+	backend, _ := newTestBackendWithConfig(t, params.AllEthashProtocolChanges, genesisT.GenesisAlloc{
+		// BALANCE(otherAddr)
+		balanceAddr: {Balance: common.Big0, Code: append(append([]byte{byte(vm.PUSH20)}, otherAddr.Bytes()...), byte(vm.BALANCE), byte(vm.POP))},
+		// REVERT(0, 0)
+		revertAddr: {Balance: common.Big0, Code: []byte{byte(vm.PUSH1), 0, byte(vm.DUP1), byte(vm.REVERT)}},
+	})
+	client, _ := backend.Attach()
+	defer backend.Close()
+	defer client.Close()
+	ec := NewClient(client)
+
+	msg := ethereum.CallMsg{From: testAddr, To: &balanceAddr, GasPrice: big.NewInt(1)}
+	list, gasUsed, vmErr, err := ec.CreateAccessList(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vmErr != "" {
+		t.Fatalf("unexpected execution error: %v", vmErr)
+	}
+	if want := (types.AccessList{{Address: otherAddr, StorageKeys: []common.Hash{}}}); list == nil || !reflect.DeepEqual(*list, want) {
+		t.Fatalf("access list mismatch: have %v, want %v", list, want)
+	}
+	msg.AccessList = *list
+	gas, err := ec.EstimateGas(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gas != gasUsed {
+		t.Errorf("gas used mismatch: have %d, want estimate %d", gasUsed, gas)
+	}
+	// Failed executions are reported apart from the errors of the call itself. The gas
+	// is set, as it would otherwise be estimated, failing the call.
+	msg = ethereum.CallMsg{From: testAddr, To: &revertAddr, Gas: 100000, GasPrice: big.NewInt(1)}
+	if _, _, vmErr, err = ec.CreateAccessList(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vmErr != vm.ErrExecutionReverted.Error() {
+		t.Errorf("execution error mismatch: have %q, want %q", vmErr, vm.ErrExecutionReverted)
+	}
+}
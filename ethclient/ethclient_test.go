@@ -908,6 +908,8 @@ var allRPCMethods = []string{
 	"debug_getModifiedAccountsByNumber",
 	"debug_goTrace",
 	"debug_memStats",
+	"debug_messEvaluate",
+	"debug_messRejections",
 	"debug_mutexProfile",
 	"debug_preimage",
 	"debug_printBlock",
@@ -917,6 +919,7 @@ var allRPCMethods = []string{
 	"debug_setGCPercent",
 	"debug_setHead",
 	"debug_setMutexProfileFraction",
+	"debug_simulateBlocks",
 	"debug_stacks",
 	"debug_standardTraceBadBlockToFile",
 	"debug_standardTraceBlockToFile",
@@ -945,10 +948,12 @@ var allRPCMethods = []string{
 	"eth_accounts",
 	"eth_blockNumber",
 	"eth_call",
+	"eth_chainConfig",
 	"eth_chainId",
 	"eth_coinbase",
 	"eth_createAccessList",
 	"eth_estimateGas",
+	"eth_feeHistory",
 	"eth_etherbase",
 	"eth_fillTransaction",
 	"eth_gasPrice",
@@ -981,6 +986,7 @@ var allRPCMethods = []string{
 	"eth_getWork",
 	"eth_hashrate",
 	"eth_logs",
+	"eth_maxPriorityFeePerGas",
 	"eth_mining",
 	"eth_newBlockFilter",
 	"eth_newHeads",
@@ -1033,6 +1039,10 @@ var allRPCMethods = []string{
 	"trace_call",
 	"trace_callMany",
 	"trace_filter",
+	"trace_get",
+	"trace_rawTransaction",
+	"trace_replayBlockTransactions",
+	"trace_replayTransaction",
 	"trace_subscribe",
 	"trace_transaction",
 	"trace_unsubscribe",
@@ -76,6 +76,13 @@ type AncientReader interface {
 	// Ancient retrieves an ancient binary blob from the append-only immutable files.
 	Ancient(kind string, number uint64) ([]byte, error)
 
+	// AncientRange retrieves multiple consecutive ancient binary blobs of a kind,
+	// starting at the given number. It returns at most count items, and stops
+	// before the cumulative stored (possibly compressed) size of the items would
+	// exceed maxBytes (0 means no limit). At least one item is returned if the
+	// first one exists, even if it exceeds maxBytes.
+	AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error)
+
 	// Ancients returns the ancient item numbers in the ancient store.
 	Ancients() (uint64, error)
 
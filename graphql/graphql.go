@@ -19,9 +19,12 @@ package graphql
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -32,14 +35,22 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/params/mutations"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 var (
 	errBlockInvariant = errors.New("block objects must be instantiated with at least one of num or hash")
 )
 
+// maxStorageRange is the maximum number of storage slots returned in a page.
+const maxStorageRange = 1024
+
 type Long int64
 
 // ImplementsGraphQLType returns true if Long implements the provided GraphQL type.
@@ -120,6 +131,86 @@ func (a *Account) Storage(ctx context.Context, args struct{ Slot common.Hash })
 	return state.GetState(a.address, args.Slot), nil
 }
 
+func (a *Account) StorageRange(ctx context.Context, args struct {
+	Start *common.Hash
+	Limit int32
+}) (*StorageRange, error) {
+	if args.Limit < 0 || args.Limit > maxStorageRange {
+		return nil, fmt.Errorf("invalid storage range limit %d, must be within 0-%d", args.Limit, maxStorageRange)
+	}
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &StorageRange{entries: []*StorageEntry{}}
+	st := state.StorageTrie(a.address)
+	if st == nil {
+		return result, nil
+	}
+	var start []byte
+	if args.Start != nil {
+		start = args.Start.Bytes()
+	}
+	it := trie.NewIterator(st.NodeIterator(start))
+	for i := int32(0); i < args.Limit && it.Next(); i++ {
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return nil, err
+		}
+		entry := &StorageEntry{
+			hashedKey: common.BytesToHash(it.Key),
+			value:     common.BytesToHash(content),
+		}
+		if preimage := st.GetKey(it.Key); preimage != nil {
+			key := common.BytesToHash(preimage)
+			entry.key = &key
+		}
+		result.entries = append(result.entries, entry)
+	}
+	// Add the 'next key' so clients can continue downloading.
+	if it.Next() {
+		next := common.BytesToHash(it.Key)
+		result.nextKey = &next
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+	return result, nil
+}
+
+// StorageRange represents a page of the storage of an account.
+type StorageRange struct {
+	entries []*StorageEntry
+	nextKey *common.Hash
+}
+
+func (r *StorageRange) Entries() []*StorageEntry {
+	return r.entries
+}
+
+func (r *StorageRange) NextKey() *common.Hash {
+	return r.nextKey
+}
+
+// StorageEntry represents a storage slot of an account.
+type StorageEntry struct {
+	hashedKey common.Hash
+	key       *common.Hash
+	value     common.Hash
+}
+
+func (e *StorageEntry) HashedKey() common.Hash {
+	return e.hashedKey
+}
+
+func (e *StorageEntry) Key() *common.Hash {
+	return e.key
+}
+
+func (e *StorageEntry) Value() common.Hash {
+	return e.value
+}
+
 // Log represents an individual log message. All arguments are mandatory.
 type Log struct {
 	backend     ethapi.Backend
@@ -408,6 +499,161 @@ func (t *Transaction) V(ctx context.Context) (hexutil.Big, error) {
 	return hexutil.Big(*v), nil
 }
 
+// Trace returns the calls performed by the transaction, as reported by the
+// Parity call tracer, or nil if the backend is unable to trace transactions.
+func (t *Transaction) Trace(ctx context.Context) (*[]*CallTrace, error) {
+	if _, err := t.resolve(ctx); err != nil || t.block == nil {
+		return nil, err
+	}
+	backend, ok := t.backend.(tracers.Backend)
+	if !ok {
+		return nil, nil
+	}
+	res, err := tracers.NewTraceAPI(tracers.NewAPI(backend)).Transaction(ctx, t.hash, nil)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	var traces []*callTrace
+	if err := json.Unmarshal(blob, &traces); err != nil {
+		return nil, err
+	}
+	ret := make([]*CallTrace, 0, len(traces))
+	for _, trace := range traces {
+		ret = append(ret, &CallTrace{trace})
+	}
+	return &ret, nil
+}
+
+// callTrace is a trace of the Parity call tracer.
+type callTrace struct {
+	Type   string `json:"type"`
+	Action struct {
+		CallType      string          `json:"callType"`
+		From          *common.Address `json:"from"`
+		To            *common.Address `json:"to"`
+		Address       *common.Address `json:"address"`       // Self destructed contract
+		RefundAddress *common.Address `json:"refundAddress"` // Self destruct beneficiary
+		Value         *hexutil.Big    `json:"value"`
+		Balance       *hexutil.Big    `json:"balance"` // Self destruct refund
+		Gas           hexutil.Uint64  `json:"gas"`
+		Input         hexutil.Bytes   `json:"input"`
+		Init          hexutil.Bytes   `json:"init"` // Creation code
+	} `json:"action"`
+	Result *struct {
+		GasUsed hexutil.Uint64  `json:"gasUsed"`
+		Output  *hexutil.Bytes  `json:"output"`
+		Code    *hexutil.Bytes  `json:"code"`    // Created contract code
+		Address *common.Address `json:"address"` // Created contract
+	} `json:"result"`
+	Error        string `json:"error"`
+	Subtraces    int32  `json:"subtraces"`
+	TraceAddress []int  `json:"traceAddress"`
+}
+
+// CallTrace represents a call, contract creation or self destruct performed
+// while executing a transaction.
+type CallTrace struct {
+	trace *callTrace
+}
+
+func (c *CallTrace) Type() string {
+	return c.trace.Type
+}
+
+func (c *CallTrace) CallType() *string {
+	if c.trace.Action.CallType == "" {
+		return nil
+	}
+	return &c.trace.Action.CallType
+}
+
+func (c *CallTrace) From() common.Address {
+	if c.trace.Action.Address != nil {
+		return *c.trace.Action.Address
+	}
+	if c.trace.Action.From != nil {
+		return *c.trace.Action.From
+	}
+	return common.Address{}
+}
+
+func (c *CallTrace) To() *common.Address {
+	switch {
+	case c.trace.Action.RefundAddress != nil:
+		return c.trace.Action.RefundAddress
+	case c.trace.Action.To != nil:
+		return c.trace.Action.To
+	case c.trace.Result != nil:
+		return c.trace.Result.Address
+	}
+	return nil
+}
+
+func (c *CallTrace) Value() hexutil.Big {
+	switch {
+	case c.trace.Action.Balance != nil:
+		return *c.trace.Action.Balance
+	case c.trace.Action.Value != nil:
+		return *c.trace.Action.Value
+	}
+	return hexutil.Big{}
+}
+
+func (c *CallTrace) Gas() Long {
+	return Long(c.trace.Action.Gas)
+}
+
+func (c *CallTrace) GasUsed() *Long {
+	if c.trace.Result == nil {
+		return nil
+	}
+	ret := Long(c.trace.Result.GasUsed)
+	return &ret
+}
+
+func (c *CallTrace) Input() hexutil.Bytes {
+	if c.trace.Action.Init != nil {
+		return c.trace.Action.Init
+	}
+	if c.trace.Action.Input != nil {
+		return c.trace.Action.Input
+	}
+	return hexutil.Bytes{}
+}
+
+func (c *CallTrace) Output() *hexutil.Bytes {
+	if c.trace.Result == nil {
+		return nil
+	}
+	if c.trace.Result.Code != nil {
+		return c.trace.Result.Code
+	}
+	return c.trace.Result.Output
+}
+
+func (c *CallTrace) Error() *string {
+	if c.trace.Error == "" {
+		return nil
+	}
+	return &c.trace.Error
+}
+
+func (c *CallTrace) Subtraces() int32 {
+	return c.trace.Subtraces
+}
+
+func (c *CallTrace) TraceAddress() []int32 {
+	ret := make([]int32, len(c.trace.TraceAddress))
+	for i, index := range c.trace.TraceAddress {
+		ret[i] = int32(index)
+	}
+	return ret
+}
+
 type BlockType int
 
 // Block represents an Ethereum block.
@@ -626,6 +872,39 @@ func (b *Block) Ommers(ctx context.Context) (*[]*Block, error) {
 	return &ret, nil
 }
 
+// Rewards returns the mining rewards of the block, or nil if the chain doesn't
+// reward mining.
+func (b *Block) Rewards(ctx context.Context) (*BlockRewards, error) {
+	config := b.backend.ChainConfig()
+	if !config.GetConsensusEngineType().IsEthash() {
+		return nil, nil
+	}
+	block, err := b.resolve(ctx)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	winner, ommers := mutations.GetRewards(config, block.Header(), block.Uncles())
+	return &BlockRewards{winner: winner, ommers: ommers}, nil
+}
+
+// BlockRewards represents the mining rewards of a block.
+type BlockRewards struct {
+	winner *big.Int
+	ommers []*big.Int
+}
+
+func (r *BlockRewards) Winner() hexutil.Big {
+	return hexutil.Big(*r.winner)
+}
+
+func (r *BlockRewards) Ommers() []hexutil.Big {
+	ret := make([]hexutil.Big, len(r.ommers))
+	for i, reward := range r.ommers {
+		ret[i] = hexutil.Big(*reward)
+	}
+	return ret
+}
+
 func (b *Block) ExtraData(ctx context.Context) (hexutil.Bytes, error) {
 	header, err := b.resolveHeader(ctx)
 	if err != nil {
@@ -964,9 +1243,66 @@ func (p *Pending) EstimateGas(ctx context.Context, args struct {
 	return Long(gas), err
 }
 
+// fullNodeBackend is implemented by the backends of full nodes, whose chain
+// reports the logs of new blocks itself.
+type fullNodeBackend interface {
+	Miner() *miner.Miner
+}
+
+// errResolverStopped is returned by the subscriptions once the node is stopping.
+var errResolverStopped = errors.New("graphql service stopped")
+
 // Resolver is the top-level object in the GraphQL hierarchy.
 type Resolver struct {
 	backend ethapi.Backend
+
+	events  *filters.EventSystem // Event system of the subscriptions, created on first use
+	subs    sync.WaitGroup       // Running subscriptions, ended before stopping the event system
+	quit    chan struct{}        // Channel closed when the node stops
+	stopped bool
+	lock    sync.Mutex
+}
+
+// subscribe registers a new subscription, returning the event system to
+// subscribe with. The caller must end the subscription when quit is closed,
+// and call Done on subs once it is unsubscribed.
+func (r *Resolver) subscribe() (*filters.EventSystem, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.stopped {
+		return nil, errResolverStopped
+	}
+	if r.events == nil {
+		_, full := r.backend.(fullNodeBackend)
+		r.events = filters.NewEventSystem(r.backend, !full)
+	}
+	r.subs.Add(1)
+	return r.events, nil
+}
+
+// Start implements node.Lifecycle, the event system is created on first use.
+func (r *Resolver) Start() error {
+	return nil
+}
+
+// Stop implements node.Lifecycle, ending the running subscriptions and
+// terminating the event system.
+func (r *Resolver) Stop() error {
+	r.lock.Lock()
+	if r.stopped {
+		r.lock.Unlock()
+		return nil
+	}
+	r.stopped = true
+	close(r.quit)
+	r.lock.Unlock()
+
+	r.subs.Wait()
+	if r.events != nil {
+		r.events.Stop()
+	}
+	return nil
 }
 
 func (r *Resolver) Block(ctx context.Context, args struct {
@@ -1107,14 +1443,231 @@ func (r *Resolver) Logs(ctx context.Context, args struct{ Filter FilterCriteria
 }
 
 func (r *Resolver) GasPrice(ctx context.Context) (hexutil.Big, error) {
-	price, err := r.backend.SuggestPrice(ctx)
-	return hexutil.Big(*price), err
+	tipcap, err := r.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	if head := r.backend.CurrentHeader(); head.BaseFee != nil {
+		tipcap.Add(tipcap, head.BaseFee)
+	}
+	return (hexutil.Big)(*tipcap), nil
+}
+
+func (r *Resolver) FeeHistory(ctx context.Context, args struct {
+	BlockCount        int32
+	LastBlock         *Long
+	RewardPercentiles *[]float64
+}) (*FeeHistory, error) {
+	lastBlock := rpc.LatestBlockNumber
+	if args.LastBlock != nil {
+		lastBlock = rpc.BlockNumber(*args.LastBlock)
+	}
+	var percentiles []float64
+	if args.RewardPercentiles != nil {
+		percentiles = *args.RewardPercentiles
+	}
+	oldest, reward, baseFee, gasUsedRatio, err := r.backend.FeeHistory(ctx, int(args.BlockCount), lastBlock, percentiles)
+	if err != nil {
+		return nil, err
+	}
+	return &FeeHistory{
+		oldestBlock:  oldest,
+		reward:       reward,
+		baseFee:      baseFee,
+		gasUsedRatio: gasUsedRatio,
+	}, nil
 }
 
 func (r *Resolver) ChainID(ctx context.Context) (hexutil.Big, error) {
 	return hexutil.Big(*r.backend.ChainConfig().GetChainID()), nil
 }
 
+// NewBlock streams the blocks added to the canonical chain until the subscription
+// is cancelled.
+func (r *Resolver) NewBlock(ctx context.Context) (<-chan *Block, error) {
+	events, err := r.subscribe()
+	if err != nil {
+		return nil, err
+	}
+	headers := make(chan *types.Header)
+	sub := events.SubscribeNewHeads(headers)
+
+	blocks := make(chan *Block)
+	go func() {
+		defer close(blocks)
+		defer r.subs.Done()
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case header := <-headers:
+				numberOrHash := rpc.BlockNumberOrHashWithHash(header.Hash(), false)
+				block := &Block{
+					backend:      r.backend,
+					numberOrHash: &numberOrHash,
+					hash:         header.Hash(),
+					header:       header,
+				}
+				select {
+				case blocks <- block:
+				case <-r.quit:
+					return
+				case <-ctx.Done():
+					return
+				}
+			case <-sub.Err():
+				return
+			case <-r.quit:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return blocks, nil
+}
+
+// NewLogs streams the log entries of the blocks added to the canonical chain
+// which match the filter, until the subscription is cancelled. Logs of the
+// blocks removed by reorgs are not reported. The subscription can't be named
+// logs, as all the root fields are resolved by the same type as the logs query.
+func (r *Resolver) NewLogs(ctx context.Context, args struct{ Filter BlockFilterCriteria }) (<-chan *Log, error) {
+	var crit ethereum.FilterQuery
+	if args.Filter.Addresses != nil {
+		crit.Addresses = *args.Filter.Addresses
+	}
+	if args.Filter.Topics != nil {
+		crit.Topics = *args.Filter.Topics
+	}
+	events, err := r.subscribe()
+	if err != nil {
+		return nil, err
+	}
+	matches := make(chan []*types.Log)
+	sub, err := events.SubscribeLogs(crit, matches)
+	if err != nil {
+		r.subs.Done()
+		return nil, err
+	}
+	logs := make(chan *Log)
+	go func() {
+		defer close(logs)
+		defer r.subs.Done()
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case matched := <-matches:
+				for _, log := range matched {
+					if log.Removed {
+						continue
+					}
+					l := &Log{
+						backend:     r.backend,
+						transaction: &Transaction{backend: r.backend, hash: log.TxHash},
+						log:         log,
+					}
+					select {
+					case logs <- l:
+					case <-r.quit:
+						return
+					case <-ctx.Done():
+						return
+					}
+				}
+			case <-sub.Err():
+				return
+			case <-r.quit:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return logs, nil
+}
+
+// PendingTransactions streams the transactions entering the transaction pool
+// until the subscription is cancelled.
+func (r *Resolver) PendingTransactions(ctx context.Context) (<-chan *Transaction, error) {
+	events, err := r.subscribe()
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(chan []common.Hash)
+	sub := events.SubscribePendingTxs(hashes)
+
+	txs := make(chan *Transaction)
+	go func() {
+		defer close(txs)
+		defer r.subs.Done()
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case added := <-hashes:
+				for _, hash := range added {
+					select {
+					case txs <- &Transaction{backend: r.backend, hash: hash}:
+					case <-r.quit:
+						return
+					case <-ctx.Done():
+						return
+					}
+				}
+			case <-sub.Err():
+				return
+			case <-r.quit:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return txs, nil
+}
+
+// FeeHistory represents the fee market history returned from the `feeHistory` accessor.
+type FeeHistory struct {
+	oldestBlock  *big.Int
+	reward       [][]*big.Int
+	baseFee      []*big.Int
+	gasUsedRatio []float64
+}
+
+func (f *FeeHistory) OldestBlock() Long {
+	return Long(f.oldestBlock.Int64())
+}
+
+func (f *FeeHistory) Reward() *[][]hexutil.Big {
+	if f.reward == nil {
+		return nil
+	}
+	ret := make([][]hexutil.Big, len(f.reward))
+	for i, rewards := range f.reward {
+		ret[i] = make([]hexutil.Big, len(rewards))
+		for j, reward := range rewards {
+			ret[i][j] = hexutil.Big(*reward)
+		}
+	}
+	return &ret
+}
+
+func (f *FeeHistory) BaseFeePerGas() []hexutil.Big {
+	ret := make([]hexutil.Big, len(f.baseFee))
+	for i, fee := range f.baseFee {
+		ret[i] = hexutil.Big(*fee)
+	}
+	return ret
+}
+
+func (f *FeeHistory) GasUsedRatio() []float64 {
+	if f.gasUsedRatio == nil {
+		return []float64{}
+	}
+	return f.gasUsedRatio
+}
+
 // SyncState represents the synchronisation status returned from the `syncing` accessor.
 type SyncState struct {
 	progress ethereum.SyncProgress
@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/gorilla/websocket"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -144,6 +145,12 @@ func TestGraphQLBlockSerialization(t *testing.T) {
 			want: `{"data":{"block":{"number":10,"call":{"data":"0x","status":1}}}}`,
 			code: 200,
 		},
+		// should return zero base fees for blocks without a fee market
+		{
+			body: `{"query": "{feeHistory(blockCount:2,rewardPercentiles:[50]){oldestBlock,reward,baseFeePerGas,gasUsedRatio}}"}`,
+			want: `{"data":{"feeHistory":{"oldestBlock":9,"reward":[["0x0"],["0x0"]],"baseFeePerGas":["0x0","0x0","0x0"],"gasUsedRatio":[0,0]}}}`,
+			code: 200,
+		},
 	} {
 		resp, err := http.Post(fmt.Sprintf("%s/graphql", stack.HTTPEndpoint()), "application/json", strings.NewReader(tt.body))
 		if err != nil {
@@ -180,6 +187,29 @@ func TestGraphQLBlockSerializationEIP2718(t *testing.T) {
 			want: `{"data":{"block":{"number":1,"transactions":[{"from":{"address":"0x71562b71999873db5b286df957af199ec94617f7"},"to":{"address":"0x0000000000000000000000000000000000000dad"},"value":"0x64","hash":"0x4f7b8d718145233dcf7f29e34a969c63dd4de8715c054ea2af022b66c4f4633e","type":0,"accessList":[],"index":0},{"from":{"address":"0x71562b71999873db5b286df957af199ec94617f7"},"to":{"address":"0x0000000000000000000000000000000000000dad"},"value":"0x32","hash":"0x9c6c2c045b618fe87add0e49ba3ca00659076ecae00fd51de3ba5d4ccf9dbf40","type":1,"accessList":[{"address":"0x0000000000000000000000000000000000000dad","storageKeys":["0x0000000000000000000000000000000000000000000000000000000000000000"]}],"index":1}]}}}`,
 			code: 200,
 		},
+		// should return the rewards of the miner
+		{
+			body: `{"query": "{block {rewards { winner ommers }}}"}`,
+			want: `{"data":{"block":{"rewards":{"winner":"0x1bc16d674ec80000","ommers":[]}}}}`,
+			code: 200,
+		},
+		// should return the parity call traces of the transactions
+		{
+			body: `{"query": "{block {transactions { trace { type callType from to value gas gasUsed input output error subtraces traceAddress }}}}"}`,
+			want: `{"data":{"block":{"transactions":[{"trace":[{"type":"call","callType":"call","from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000dad","value":"0x64","gas":29000,"gasUsed":4204,"input":"0x","output":"0x","error":null,"subtraces":0,"traceAddress":[]}]},{"trace":[{"type":"call","callType":"call","from":"0x71562b71999873db5b286df957af199ec94617f7","to":"0x0000000000000000000000000000000000000dad","value":"0x32","gas":4700,"gasUsed":4204,"input":"0x","output":"0x","error":null,"subtraces":0,"traceAddress":[]}]}]}}}`,
+			code: 200,
+		},
+		// should return the storage ordered by hashed slot, with the key of the next page
+		{
+			body: `{"query": "{block {account(address: \"0x0000000000000000000000000000000000000dad\") { storageRange(limit: 1) { entries { hashedKey key value } nextKey }}}}"}`,
+			want: `{"data":{"block":{"account":{"storageRange":{"entries":[{"hashedKey":"0x405787fa12a823e0f2b7631cc41b3ba8828b3321ca811111fa75cd3aa3bb5ace","key":null,"value":"0x000000000000000000000000000000000000000000000000000000000000002b"}],"nextKey":"0xb10e2d527612073b26eecdfd717e6a320cf44b4afac2b0732d9fcbe2b7fa0cf6"}}}}}`,
+			code: 200,
+		},
+		{
+			body: `{"query": "{block {account(address: \"0x0000000000000000000000000000000000000dad\") { storageRange(start: \"0xb10e2d527612073b26eecdfd717e6a320cf44b4afac2b0732d9fcbe2b7fa0cf6\", limit: 2) { entries { hashedKey value } nextKey }}}}"}`,
+			want: `{"data":{"block":{"account":{"storageRange":{"entries":[{"hashedKey":"0xb10e2d527612073b26eecdfd717e6a320cf44b4afac2b0732d9fcbe2b7fa0cf6","value":"0x000000000000000000000000000000000000000000000000000000000000002a"}],"nextKey":null}}}}}`,
+			code: 200,
+		},
 	} {
 		resp, err := http.Post(fmt.Sprintf("%s/graphql", stack.HTTPEndpoint()), "application/json", strings.NewReader(tt.body))
 		if err != nil {
@@ -214,6 +244,131 @@ func TestGraphQLHTTPOnSamePort_GQLRequest_Unsuccessful(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 }
 
+// Tests that queries and subscriptions are served over websocket connections.
+func TestGraphQLWebsocket(t *testing.T) {
+	stack, err := node.New(&node.Config{
+		HTTPHost: "127.0.0.1",
+		HTTPPort: 0,
+	})
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	defer stack.Close()
+	ethBackend := createGQLService(t, stack)
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	dialer := websocket.Dialer{Subprotocols: []string{wsProtocol}}
+	conn, _, err := dialer.Dial(strings.Replace(stack.HTTPEndpoint(), "http://", "ws://", 1)+"/graphql", nil)
+	if err != nil {
+		t.Fatalf("could not dial: %v", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	expect := func(want string) {
+		t.Helper()
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("could not read: %v", err)
+		}
+		if have := strings.TrimSpace(string(msg)); have != want {
+			t.Fatalf("message mismatch,\nhave:\n%v\nwant:\n%v", have, want)
+		}
+	}
+	send := func(msg string) {
+		t.Helper()
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+			t.Fatalf("could not write: %v", err)
+		}
+	}
+	send(`{"type":"connection_init"}`)
+	expect(`{"type":"connection_ack"}`)
+	expect(`{"type":"ka"}`)
+
+	// Queries complete after a single response
+	send(`{"id":"1","type":"start","payload":{"query":"{block{number}}"}}`)
+	expect(`{"id":"1","type":"data","payload":{"data":{"block":{"number":10}}}}`)
+	expect(`{"id":"1","type":"complete"}`)
+
+	// Subscriptions are notified until stopped
+	send(`{"id":"2","type":"start","payload":{"query":"subscription{newBlock{number}}"}}`)
+
+	// Operations are started in order, so the subscription is installed once
+	// a later query completes
+	send(`{"id":"3","type":"start","payload":{"query":"{block{number}}"}}`)
+	expect(`{"id":"3","type":"data","payload":{"data":{"block":{"number":10}}}}`)
+	expect(`{"id":"3","type":"complete"}`)
+
+	chain, _ := core.GenerateChain(params.AllEthashProtocolChanges, ethBackend.BlockChain().CurrentBlock(),
+		ethash.NewFaker(), ethBackend.ChainDb(), 1, func(i int, gen *core.BlockGen) {})
+	if _, err := ethBackend.BlockChain().InsertChain(chain); err != nil {
+		t.Fatalf("could not import blocks: %v", err)
+	}
+	expect(`{"id":"2","type":"data","payload":{"data":{"newBlock":{"number":11}}}}`)
+
+	send(`{"id":"2","type":"stop"}`)
+	expect(`{"id":"2","type":"complete"}`)
+}
+
+// Tests that stopping the node ends the running subscriptions.
+func TestGraphQLWebsocketNodeStop(t *testing.T) {
+	stack, err := node.New(&node.Config{
+		HTTPHost: "127.0.0.1",
+		HTTPPort: 0,
+	})
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	createGQLService(t, stack)
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	dialer := websocket.Dialer{Subprotocols: []string{wsProtocol}}
+	conn, _, err := dialer.Dial(strings.Replace(stack.HTTPEndpoint(), "http://", "ws://", 1)+"/graphql", nil)
+	if err != nil {
+		t.Fatalf("could not dial: %v", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	expect := func(want string) {
+		t.Helper()
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("could not read: %v", err)
+		}
+		if have := strings.TrimSpace(string(msg)); have != want {
+			t.Fatalf("message mismatch,\nhave:\n%v\nwant:\n%v", have, want)
+		}
+	}
+	for _, msg := range []string{
+		`{"type":"connection_init"}`,
+		`{"id":"1","type":"start","payload":{"query":"subscription{pendingTransactions{hash}}"}}`,
+		`{"id":"2","type":"start","payload":{"query":"{block{number}}"}}`,
+	} {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+			t.Fatalf("could not write: %v", err)
+		}
+	}
+	expect(`{"type":"connection_ack"}`)
+	expect(`{"type":"ka"}`)
+	expect(`{"id":"2","type":"data","payload":{"data":{"block":{"number":10}}}}`)
+	expect(`{"id":"2","type":"complete"}`)
+
+	closed := make(chan error)
+	go func() { closed <- stack.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("could not close node: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("node close timed out")
+	}
+	expect(`{"id":"1","type":"complete"}`)
+}
+
 func createNode(t *testing.T, gqlEnabled bool, txEnabled bool) *node.Node {
 	stack, err := node.New(&node.Config{
 		HTTPHost: "127.0.0.1",
@@ -235,7 +390,7 @@ func createNode(t *testing.T, gqlEnabled bool, txEnabled bool) *node.Node {
 	return stack
 }
 
-func createGQLService(t *testing.T, stack *node.Node) {
+func createGQLService(t *testing.T, stack *node.Node) *eth.Ethereum {
 	// create backend
 	ethConf := &ethconfig.Config{
 		Genesis: &genesisT.Genesis{
@@ -270,6 +425,7 @@ func createGQLService(t *testing.T, stack *node.Node) {
 	if err != nil {
 		t.Fatalf("could not create graphql service: %v", err)
 	}
+	return ethBackend
 }
 
 func createGQLServiceWithTransactions(t *testing.T, stack *node.Node) {
@@ -296,6 +452,10 @@ func createGQLServiceWithTransactions(t *testing.T, stack *node.Node) {
 					},
 					Nonce:   0,
 					Balance: big.NewInt(0),
+					Storage: map[common.Hash]common.Hash{
+						common.HexToHash("0x01"): common.HexToHash("0x2a"),
+						common.HexToHash("0x02"): common.HexToHash("0x2b"),
+					},
 				},
 			},
 		},
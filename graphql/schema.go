@@ -34,6 +34,7 @@ const schema string = `
     schema {
         query: Query
         mutation: Mutation
+        subscription: Subscription
     }
 
     # Account is an Ethereum account at a particular block.
@@ -52,6 +53,30 @@ const schema string = `
         # Storage provides access to the storage of a contract account, indexed
         # by its 32 byte slot identifier.
         storage(slot: Bytes32!): Bytes32!
+        # StorageRange returns up to limit storage slots of a contract account,
+        # ordered by the hash of their identifier and starting with the given
+        # hashed slot, or the first one if not supplied.
+        storageRange(start: Bytes32, limit: Int!): StorageRange!
+    }
+
+    # StorageRange is a page of the storage of an account.
+    type StorageRange {
+        # Entries are the storage slots of the page.
+        entries: [StorageEntry!]!
+        # NextKey is the hashed identifier of the slot starting the next page,
+        # or null if this is the last page.
+        nextKey: Bytes32
+    }
+
+    # StorageEntry is a storage slot of an account.
+    type StorageEntry {
+        # HashedKey is the hash of the slot identifier, which the storage is
+        # ordered by.
+        hashedKey: Bytes32!
+        # Key is the 32 byte slot identifier, or null if its preimage is unknown.
+        key: Bytes32
+        # Value is the content of the slot.
+        value: Bytes32!
     }
 
     # Log is an Ethereum event log.
@@ -75,6 +100,42 @@ const schema string = `
         storageKeys : [Bytes32!]
     }
 
+    # CallTrace is a call, contract creation or self destruct performed while
+    # executing a transaction, as reported by the Parity call tracer.
+    type CallTrace {
+        # Type is the kind of the trace: call, create or suicide.
+        type: String!
+        # CallType is the opcode of a call trace: call, callcode, delegatecall
+        # or staticcall. This is null for other traces.
+        callType: String
+        # From is the address making the call or the contract creation, or the
+        # self destructed contract.
+        from: Address!
+        # To is the address the call is sent to, the created contract or the
+        # beneficiary of the self destruct. This is null for failed creations.
+        to: Address
+        # Value is the value, in wei, transferred by the call or the creation,
+        # or the balance refunded by the self destruct.
+        value: BigInt!
+        # Gas is the amount of gas available to the call or the creation.
+        gas: Long!
+        # GasUsed is the amount of gas used by the call or the creation. This
+        # is null if it failed.
+        gasUsed: Long
+        # Input is the data supplied to the call or the initialization code of
+        # the created contract.
+        input: Bytes!
+        # Output is the data returned by the call or the code of the created
+        # contract. This is null if it failed.
+        output: Bytes
+        # Error is the reason the call or the creation failed, if it did.
+        error: String
+        # Subtraces is the number of traces directly nested within this one.
+        subtraces: Int!
+        # TraceAddress is the position of the trace in the tree of nested traces.
+        traceAddress: [Int!]!
+    }
+
     # Transaction is an Ethereum transaction.
     type Transaction {
         # Hash is the hash of this transaction.
@@ -127,6 +188,11 @@ const schema string = `
         #Envelope transaction support
         type: Int
         accessList: [AccessTuple!]
+        # Trace is the list of calls, contract creations and self destructs
+        # performed by this transaction, starting with the transaction itself.
+        # If the transaction has not yet been mined, or the node is unable to
+        # trace it, this field will be null.
+        trace: [CallTrace!]
     }
 
     # BlockFilterCriteria encapsulates log filter criteria for a filter applied
@@ -202,6 +268,10 @@ const schema string = `
         # OmmerHash is the keccak256 hash of all the ommers (AKA uncles)
         # associated with this block.
         ommerHash: Bytes32!
+        # Rewards is the breakdown of the mining rewards of this block, following
+        # the reward schedule of the chain (such as the ECIP-1017 eras). This
+        # will be null on chains without mining rewards.
+        rewards: BlockRewards
         # Transactions is a list of transactions associated with this block. If
         # transactions are unavailable for this block, this field will be null.
         transactions: [Transaction!]
@@ -220,6 +290,17 @@ const schema string = `
         estimateGas(data: CallData!): Long!
     }
 
+    # BlockRewards is the breakdown of the mining rewards of a block, in wei.
+    # Transaction fees are not included.
+    type BlockRewards {
+        # Winner is the reward of the miner of the block, including the share
+        # earned for each included ommer (AKA uncle).
+        winner: BigInt!
+        # Ommers are the rewards of the miners of each ommer (AKA uncle) of the
+        # block, in the order of the ommers field.
+        ommers: [BigInt!]!
+    }
+
     # CallData represents the data associated with a local contract call.
     # All fields are optional.
     input CallData {
@@ -303,6 +384,20 @@ const schema string = `
       estimateGas(data: CallData!): Long!
     }
 
+    # FeeHistory is the fee market history of a range of blocks.
+    type FeeHistory {
+        # OldestBlock is the number of the first block of the range.
+        oldestBlock: Long!
+        # Reward is the effective priority fee paid at each of the requested
+        # percentiles of the gas used by each block, if any were requested.
+        reward: [[BigInt!]!]
+        # BaseFeePerGas is the base fee of each block and of the block following
+        # the range, zero for blocks without a fee market.
+        baseFeePerGas: [BigInt!]!
+        # GasUsedRatio is the ratio of gas used to the gas limit of each block.
+        gasUsedRatio: [Float!]!
+    }
+
     type Query {
         # Block fetches an Ethereum block by number or by hash. If neither is
         # supplied, the most recent known block is returned.
@@ -319,12 +414,26 @@ const schema string = `
         # GasPrice returns the node's estimate of a gas price sufficient to
         # ensure a transaction is mined in a timely fashion.
         gasPrice: BigInt!
+        # FeeHistory returns the fee market history of blockCount blocks ending
+        # with lastBlock, or the most recent known block if not supplied.
+        feeHistory(blockCount: Int!, lastBlock: Long, rewardPercentiles: [Float!]): FeeHistory!
         # Syncing returns information on the current synchronisation state.
         syncing: SyncState
         # ChainID returns the current chain ID for transaction replay protection.
         chainID: BigInt!
     }
 
+    type Subscription {
+        # NewBlock is notified of each block added to the canonical chain.
+        newBlock: Block!
+        # NewLogs is notified of each log entry matching the filter in the
+        # blocks added to the canonical chain.
+        newLogs(filter: BlockFilterCriteria!): Log!
+        # PendingTransactions is notified of each transaction entering the
+        # transaction pool.
+        pendingTransactions: Transaction!
+    }
+
     type Mutation {
         # SendRawTransaction sends an RLP-encoded transaction to the network.
         sendRawTransaction(data: Bytes!): Bytes32!
@@ -22,14 +22,20 @@ import (
 
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/gorilla/websocket"
 	"github.com/graph-gophers/graphql-go"
 )
 
 type handler struct {
 	Schema *graphql.Schema
+	ws     *wsHandler
 }
 
 func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		h.ws.ServeHTTP(w, r)
+		return
+	}
 	var params struct {
 		Query         string                 `json:"query"`
 		OperationName string                 `json:"operationName"`
@@ -64,21 +70,23 @@ func New(stack *node.Node, backend ethapi.Backend, cors, vhosts []string) error
 	return newHandler(stack, backend, cors, vhosts)
 }
 
-// newHandler returns a new `http.Handler` that will answer GraphQL queries.
+// newHandler returns a new `http.Handler` that will answer GraphQL queries,
+// and subscriptions over websocket connections.
 // It additionally exports an interactive query browser on the / endpoint.
 func newHandler(stack *node.Node, backend ethapi.Backend, cors, vhosts []string) error {
-	q := Resolver{backend}
+	q := &Resolver{backend: backend, quit: make(chan struct{})}
 
-	s, err := graphql.ParseSchema(schema, &q)
+	s, err := graphql.ParseSchema(schema, q)
 	if err != nil {
 		return err
 	}
-	h := handler{Schema: s}
+	h := handler{Schema: s, ws: newWSHandler(s, cors)}
 	handler := node.NewHTTPHandlerStack(h, cors, vhosts)
 
 	stack.RegisterHandler("GraphQL UI", "/graphql/ui", GraphiQL{})
 	stack.RegisterHandler("GraphQL", "/graphql", handler)
 	stack.RegisterHandler("GraphQL", "/graphql/", handler)
+	stack.RegisterLifecycle(q)
 
 	return nil
 }
@@ -0,0 +1,265 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/websocket"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// Message types of the graphql-ws protocol, see
+// https://github.com/apollographql/subscriptions-transport-ws/blob/master/PROTOCOL.md
+const (
+	wsConnectionInit      = "connection_init"      // Client -> Server
+	wsConnectionTerminate = "connection_terminate" // Client -> Server
+	wsStart               = "start"                // Client -> Server
+	wsStop                = "stop"                 // Client -> Server
+	wsConnectionAck       = "connection_ack"       // Server -> Client
+	wsConnectionError     = "connection_error"     // Server -> Client
+	wsKeepAlive           = "ka"                   // Server -> Client
+	wsData                = "data"                 // Server -> Client
+	wsError               = "error"                // Server -> Client
+	wsComplete            = "complete"             // Server -> Client
+)
+
+const (
+	wsProtocol          = "graphql-ws"
+	wsReadLimit         = 1024 * 1024
+	wsWriteTimeout      = 10 * time.Second
+	wsKeepAliveInterval = 30 * time.Second
+)
+
+// wsMessage is a message of the graphql-ws protocol.
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsStartPayload is the operation requested by a start message.
+type wsStartPayload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// wsHandler serves GraphQL operations, including subscriptions, over websocket
+// connections speaking the graphql-ws protocol.
+type wsHandler struct {
+	schema   *graphql.Schema
+	upgrader websocket.Upgrader
+}
+
+// newWSHandler creates a websocket handler accepting connections from the given
+// origins, or from any origin if "*" is included.
+func newWSHandler(schema *graphql.Schema, allowedOrigins []string) *wsHandler {
+	origins := make(map[string]struct{})
+	for _, origin := range allowedOrigins {
+		origins[strings.ToLower(origin)] = struct{}{}
+	}
+	return &wsHandler{
+		schema: schema,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{wsProtocol},
+			CheckOrigin: func(r *http.Request) bool {
+				// Browsers always set the origin, other clients may set anything
+				origin := r.Header.Get("Origin")
+				if origin == "" {
+					return true
+				}
+				if _, ok := origins["*"]; ok {
+					return true
+				}
+				if _, ok := origins[strings.ToLower(origin)]; ok {
+					return true
+				}
+				log.Warn("Rejected GraphQL WebSocket connection", "origin", origin)
+				return false
+			},
+		},
+	}
+}
+
+func (h *wsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Debug("GraphQL WebSocket upgrade failed", "err", err)
+		return
+	}
+	c := &wsConn{
+		conn:       conn,
+		schema:     h.schema,
+		operations: make(map[string]context.CancelFunc),
+	}
+	c.run()
+}
+
+// wsConn is a single graphql-ws connection, running any number of operations.
+type wsConn struct {
+	conn   *websocket.Conn
+	schema *graphql.Schema
+
+	writeLock  sync.Mutex
+	lock       sync.Mutex
+	operations map[string]context.CancelFunc // Cancellation of the running operations by ID
+	wg         sync.WaitGroup
+}
+
+// run reads the messages of the client until the connection is terminated,
+// then stops all the running operations.
+func (c *wsConn) run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		c.conn.Close()
+		c.wg.Wait()
+	}()
+	c.conn.SetReadLimit(wsReadLimit)
+
+	initialized := false
+	for {
+		var msg wsMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Debug("GraphQL WebSocket read failed", "err", err)
+			}
+			return
+		}
+		switch msg.Type {
+		case wsConnectionInit:
+			if initialized {
+				continue
+			}
+			initialized = true
+			c.send(&wsMessage{Type: wsConnectionAck})
+			c.send(&wsMessage{Type: wsKeepAlive})
+			c.wg.Add(1)
+			go c.keepAlive(ctx)
+
+		case wsConnectionTerminate:
+			return
+
+		case wsStart:
+			if !initialized {
+				c.sendError(wsConnectionError, "", "connection not initialized")
+				return
+			}
+			var payload wsStartPayload
+			if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+				c.sendError(wsError, msg.ID, err.Error())
+				continue
+			}
+			c.start(ctx, msg.ID, &payload)
+
+		case wsStop:
+			c.stop(msg.ID)
+
+		default:
+			c.sendError(wsError, msg.ID, "unknown message type "+msg.Type)
+		}
+	}
+}
+
+// start runs the operation with the given ID, sending its responses until it
+// either completes or is stopped.
+func (c *wsConn) start(ctx context.Context, id string, payload *wsStartPayload) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.operations[id]; ok {
+		c.sendError(wsError, id, "duplicate operation ID "+id)
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	responses, err := c.schema.Subscribe(ctx, payload.Query, payload.OperationName, payload.Variables)
+	if err != nil {
+		cancel()
+		c.sendError(wsError, id, err.Error())
+		return
+	}
+	c.operations[id] = cancel
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer c.stop(id)
+
+		// Drain the responses until closed, even if the connection failed
+		for response := range responses {
+			data, err := json.Marshal(response)
+			if err != nil {
+				c.sendError(wsError, id, err.Error())
+				continue
+			}
+			c.send(&wsMessage{ID: id, Type: wsData, Payload: data})
+		}
+		c.send(&wsMessage{ID: id, Type: wsComplete})
+	}()
+}
+
+// stop cancels the operation with the given ID, if it is still running.
+func (c *wsConn) stop(id string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cancel, ok := c.operations[id]; ok {
+		cancel()
+		delete(c.operations, id)
+	}
+}
+
+// keepAlive periodically notifies the client that the connection is alive.
+func (c *wsConn) keepAlive(ctx context.Context) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(wsKeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.send(&wsMessage{Type: wsKeepAlive})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendError sends an error message of the given type to the client.
+func (c *wsConn) sendError(typ string, id string, message string) {
+	payload, _ := json.Marshal(map[string]string{"message": message})
+	c.send(&wsMessage{ID: id, Type: typ, Payload: payload})
+}
+
+// send writes the message to the client. Failures are only logged, as they
+// terminate the connection anyway.
+func (c *wsConn) send(msg *wsMessage) {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	if err := c.conn.WriteJSON(msg); err != nil {
+		log.Debug("GraphQL WebSocket write failed", "err", err)
+	}
+}
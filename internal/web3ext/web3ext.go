@@ -34,6 +34,7 @@ var Modules = map[string]string{
 	"trace":      TraceJs,
 	"txpool":     TxpoolJs,
 	"les":        LESJs,
+	"lyra2":      Lyra2Js,
 	"vflux":      VfluxJs,
 }
 
@@ -94,6 +95,12 @@ web3._extend({
 			call: 'clique_getSignersAtHash',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getVoteHistory',
+			call: 'clique_getVoteHistory',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'propose',
 			call: 'clique_propose',
@@ -147,6 +154,34 @@ web3._extend({
 });
 `
 
+const Lyra2Js = `
+web3._extend({
+	property: 'lyra2',
+	methods: [
+		new web3._extend.Method({
+			name: 'getWork',
+			call: 'lyra2_getWork',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getHashrate',
+			call: 'lyra2_getHashrate',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'submitWork',
+			call: 'lyra2_submitWork',
+			params: 3,
+		}),
+		new web3._extend.Method({
+			name: 'submitHashrate',
+			call: 'lyra2_submitHashrate',
+			params: 2,
+		}),
+	]
+});
+`
+
 const AdminJs = `
 web3._extend({
 	property: 'admin',
@@ -460,12 +495,24 @@ web3._extend({
 			params: 3,
 			inputFormatter: [null, null, null]
 		}),
+		new web3._extend.Method({
+			name: 'simulateBlocks',
+			call: 'debug_simulateBlocks',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
 			params: 1,
 			inputFormatter: [null]
 		}),
+		new web3._extend.Method({
+			name: 'messEvaluate',
+			call: 'debug_messEvaluate',
+			params: 3,
+			inputFormatter: [null, null, null]
+		}),
 		new web3._extend.Method({
 			name: 'getBadBlocks',
 			call: 'debug_getBadBlocks',
@@ -512,6 +559,12 @@ web3._extend({
 			call: 'eth_chainId',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'chainConfig',
+			call: 'eth_chainConfig',
+			params: 1,
+			inputFormatter: [null]
+		}),
 		new web3._extend.Method({
 			name: 'sign',
 			call: 'eth_sign',
@@ -597,6 +650,12 @@ web3._extend({
 			params: 2,
 			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter],
 		}),
+		new web3._extend.Method({
+			name: 'feeHistory',
+			call: 'eth_feeHistory',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -830,6 +889,27 @@ web3._extend({
 				});
 			}, web3._extend.formatters.inputBlockNumberFormatter, null]
 		}),
+		new web3._extend.Method({
+			name: 'replayTransaction',
+			call: 'trace_replayTransaction',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'replayBlockTransactions',
+			call: 'trace_replayBlockTransactions',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'rawTransaction',
+			call: 'trace_rawTransaction',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'get',
+			call: 'trace_get',
+			params: 2
+		}),
 	],
 	properties: []
 });
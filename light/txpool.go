@@ -70,6 +70,7 @@ type TxPool struct {
 	eip2f    bool
 	eip2028f bool
 	eip2718  bool // Fork indicator whether we are in the eip2718 stage.
+	eip1559  bool // Fork indicator whether we are in the eip1559 stage.
 }
 
 // TxRelayBackend provides an interface to the mechanism that forwards transacions
@@ -335,6 +336,7 @@ func (pool *TxPool) setNewHead(head *types.Header) {
 
 	pool.eip2028f = pool.config.IsEnabled(pool.config.GetEIP2028Transition, next)
 	pool.eip2718 = pool.config.IsEnabled(pool.config.GetEIP2718Transition, next)
+	pool.eip1559 = pool.config.IsEnabled(pool.config.GetEIP1559Transition, next)
 }
 
 // Stop stops the light transaction pool
@@ -364,6 +366,14 @@ func (pool *TxPool) Stats() (pending int) {
 
 // validateTx checks whether a transaction is valid according to the consensus rules.
 func (pool *TxPool) validateTx(ctx context.Context, tx *types.Transaction) error {
+	// Reject dynamic fee transactions until EIP-1559 activates.
+	if !pool.eip1559 && tx.Type() == types.DynamicFeeTxType {
+		return core.ErrTxTypeNotSupported
+	}
+	// Ensure gasFeeCap is greater than or equal to gasTipCap.
+	if tx.GasFeeCapIntCmp(tx.GasTipCap()) < 0 {
+		return core.ErrTipAboveFeeCap
+	}
 	// Validate sender
 	var (
 		from common.Address
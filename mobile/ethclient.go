@@ -19,6 +19,7 @@
 package geth
 
 import (
+	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/core/types"
@@ -307,6 +308,24 @@ func (ec *EthereumClient) EstimateGas(ctx *Context, msg *CallMsg) (gas int64, _
 	return int64(rawGas), err
 }
 
+// CreateAccessList tries to create an EIP-2930 access list for a specific transaction
+// based on the current pending state of the backend blockchain. The list is attached
+// to the message, so that further gas estimations account for it, and the gas used
+// by the transaction with the list is returned.
+func (ec *EthereumClient) CreateAccessList(ctx *Context, msg *CallMsg) (gas int64, _ error) {
+	list, gasUsed, vmErr, err := ec.client.CreateAccessList(ctx.context, msg.msg)
+	if err != nil {
+		return 0, err
+	}
+	if vmErr != "" {
+		return 0, errors.New(vmErr)
+	}
+	if list != nil {
+		msg.msg.AccessList = *list
+	}
+	return int64(gasUsed), nil
+}
+
 // SendTransaction injects a signed transaction into the pending pool for execution.
 //
 // If the transaction was a contract creation use the TransactionReceipt method to get the
@@ -0,0 +1,90 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package geth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+)
+
+// Tests that access lists created through the client are attached to the call
+// message, so that gas estimations account for them.
+func TestEthereumClientCreateAccessList(t *testing.T) {
+	var (
+		from     = common.HexToAddress("0x1000000000000000000000000000000000000000")
+		contract = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		other    = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	)
+	stack, err := node.New(&node.Config{})
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	defer stack.Close()
+
+	config := &ethconfig.Config{Genesis: &genesisT.Genesis{
+		Config: params.AllEthashProtocolChanges,
+		Alloc: genesisT.GenesisAlloc{
+			from: {Balance: big.NewInt(1e18)},
+			// BALANCE(other)
+			contract: {Balance: common.Big0, Code: append(append([]byte{byte(vm.PUSH20)}, other.Bytes()...), byte(vm.BALANCE), byte(vm.POP))},
+		},
+	}}
+	config.Ethash.PowMode = ethash.ModeFake
+	if _, err := eth.New(stack, config); err != nil {
+		t.Fatalf("failed to create ethereum service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	rpcClient, err := stack.Attach()
+	if err != nil {
+		t.Fatalf("failed to attach to node: %v", err)
+	}
+	client := &EthereumClient{ethclient.NewClient(rpcClient)}
+	defer rpcClient.Close()
+
+	msg := NewCallMsg()
+	msg.SetFrom(&Address{from})
+	msg.SetTo(&Address{contract})
+	msg.SetGasPrice(NewBigInt(1))
+
+	ctx := NewContext()
+	gas, err := client.CreateAccessList(ctx, msg)
+	if err != nil {
+		t.Fatalf("failed to create access list: %v", err)
+	}
+	if len(msg.msg.AccessList) != 1 || msg.msg.AccessList[0].Address != other {
+		t.Fatalf("access list not attached to message: %v", msg.msg.AccessList)
+	}
+	estimate, err := client.EstimateGas(ctx, msg)
+	if err != nil {
+		t.Fatalf("failed to estimate gas: %v", err)
+	}
+	if gas != estimate {
+		t.Errorf("gas mismatch: have %d, want estimate %d", gas, estimate)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package confp
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+)
+
+// Diff returns the differences between two configurations, which may be of different data types.
+// All argument-less Get methods of the configurator interfaces implemented by both values
+// are compared (transitions, chain and consensus parameters, reward schedules, genesis values),
+// as well as the genesis accounts.
+// Values are compared semantically, so that eg. a nil and an empty schedule are equal.
+func Diff(a, b interface{}) (diffs []DiffT) {
+	seen := make(map[string]bool)
+	for _, k := range []reflect.Type{
+		reflect.TypeOf((*ctypes.ProtocolSpecifier)(nil)).Elem(),
+		reflect.TypeOf((*ctypes.Forker)(nil)).Elem(),
+		reflect.TypeOf((*ctypes.ConsensusEnginator)(nil)).Elem(),
+		reflect.TypeOf((*ctypes.GenesisBlocker)(nil)).Elem(),
+	} {
+		if !reflect.TypeOf(a).Implements(k) || !reflect.TypeOf(b).Implements(k) {
+			continue
+		}
+		for i := 0; i < k.NumMethod(); i++ {
+			method := k.Method(i)
+			if !strings.HasPrefix(method.Name, "Get") || method.Type.NumIn() != 0 || method.Type.NumOut() != 1 {
+				continue
+			}
+			if seen[method.Name] {
+				continue
+			}
+			seen[method.Name] = true
+
+			va := diffValue(reflect.ValueOf(a).MethodByName(method.Name).Call(nil)[0])
+			vb := diffValue(reflect.ValueOf(b).MethodByName(method.Name).Call(nil)[0])
+			if fmt.Sprint(va) != fmt.Sprint(vb) {
+				diffs = append(diffs, DiffT{
+					Field: strings.TrimPrefix(method.Name, "Get"),
+					A:     va,
+					B:     vb,
+				})
+			}
+		}
+	}
+	ga, okA := a.(ctypes.GenesisBlocker)
+	gb, okB := b.(ctypes.GenesisBlocker)
+	if okA && okB {
+		diffs = append(diffs, diffAccounts(ga, gb)...)
+	}
+	return diffs
+}
+
+// diffValue normalizes a value for comparison and display.
+// Pointers are dereferenced, and nil and empty values are represented as nil.
+func diffValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return diffValue(v.Elem())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		if i, ok := v.Interface().(*big.Int); ok {
+			return i
+		}
+		return diffValue(v.Elem())
+	case reflect.Map, reflect.Slice:
+		if v.Len() == 0 {
+			return nil
+		}
+		if b, ok := v.Interface().([]byte); ok {
+			return hexutil.Bytes(b)
+		}
+	}
+	return v.Interface()
+}
+
+type diffAccount struct {
+	balance *big.Int
+	nonce   uint64
+	code    []byte
+	storage map[common.Hash]common.Hash
+}
+
+func collectAccounts(g ctypes.GenesisBlocker) map[common.Address]diffAccount {
+	accounts := make(map[common.Address]diffAccount)
+	g.ForEachAccount(func(address common.Address, bal *big.Int, nonce uint64, code []byte, storage map[common.Hash]common.Hash) error {
+		accounts[address] = diffAccount{bal, nonce, code, storage}
+		return nil
+	})
+	return accounts
+}
+
+// diffAccounts returns the differences between the genesis accounts of two configurations,
+// sorted by address. A missing account is treated as empty.
+func diffAccounts(a, b ctypes.GenesisBlocker) (diffs []DiffT) {
+	accountsA, accountsB := collectAccounts(a), collectAccounts(b)
+
+	var addresses []common.Address
+	for addr := range accountsA {
+		addresses = append(addresses, addr)
+	}
+	for addr := range accountsB {
+		if _, ok := accountsA[addr]; !ok {
+			addresses = append(addresses, addr)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return bytes.Compare(addresses[i][:], addresses[j][:]) < 0
+	})
+
+	add := func(field string, va, vb interface{}) {
+		va = diffValue(reflect.ValueOf(&va).Elem())
+		vb = diffValue(reflect.ValueOf(&vb).Elem())
+		if fmt.Sprint(va) != fmt.Sprint(vb) {
+			diffs = append(diffs, DiffT{Field: field, A: va, B: vb})
+		}
+	}
+	for _, addr := range addresses {
+		accA, accB := accountsA[addr], accountsB[addr]
+		prefix := fmt.Sprintf("Alloc[%s]", addr.Hex())
+
+		add(prefix+".Balance", accA.balance, accB.balance)
+		if accA.nonce != accB.nonce {
+			diffs = append(diffs, DiffT{Field: prefix + ".Nonce", A: accA.nonce, B: accB.nonce})
+		}
+		add(prefix+".Code", accA.code, accB.code)
+
+		var keys []common.Hash
+		for k := range accA.storage {
+			keys = append(keys, k)
+		}
+		for k := range accB.storage {
+			if _, ok := accA.storage[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return bytes.Compare(keys[i][:], keys[j][:]) < 0
+		})
+		for _, k := range keys {
+			va, okA := accA.storage[k]
+			vb, okB := accB.storage[k]
+			if okA && okB && va == vb {
+				continue
+			}
+			var da, db interface{}
+			if okA {
+				da = va
+			}
+			if okB {
+				db = vb
+			}
+			diffs = append(diffs, DiffT{Field: fmt.Sprintf("%s.Storage[%s]", prefix, k.Hex()), A: da, B: db})
+		}
+	}
+	return diffs
+}
+
+// FirstIncompatibleBlock returns the lowest block at which a chain running configuration a
+// would be rejected by b with a ConfigCompatError, along with that error.
+// If the configurations are compatible for all blocks, nil is returned.
+func FirstIncompatibleBlock(a, b ctypes.ChainConfigurator) (*uint64, *ConfigCompatError) {
+	head := uint64(math.MaxUint64)
+	err := Compatible(&head, a, b)
+	if err == nil {
+		return nil, nil
+	}
+	var n uint64
+	switch {
+	case err.StoredConfig == nil && err.NewConfig == nil:
+	case err.StoredConfig == nil:
+		n = *err.NewConfig
+	case err.NewConfig == nil || *err.StoredConfig < *err.NewConfig:
+		n = *err.StoredConfig
+	default:
+		n = *err.NewConfig
+	}
+	return &n, err
+}
@@ -20,11 +20,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/params/confp"
+	"github.com/ethereum/go-ethereum/params/types/besu"
 	"github.com/ethereum/go-ethereum/params/types/coregeth"
 	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/params/types/goethereum"
 	"github.com/ethereum/go-ethereum/params/types/multigeth"
+	"github.com/ethereum/go-ethereum/params/types/nethermind"
 	"github.com/ethereum/go-ethereum/params/types/parity"
 	"github.com/ethereum/go-ethereum/params/vars"
 	"github.com/tidwall/gjson"
@@ -59,6 +64,12 @@ func (c GenericCC) DAOSupport() bool {
 			*pc.Engine.Ethash.Params.DaoHardforkBeneficiary == vars.DAORefundContract &&
 			len(pc.Engine.Ethash.Params.DaoHardforkAccounts) == len(vars.DAODrainList())
 	}
+	if nc, ok := c.ChainConfigurator.(*nethermind.NethermindChainSpec); ok {
+		return AsGenericCC(&nc.ParityChainSpec).DAOSupport()
+	}
+	if bc, ok := c.ChainConfigurator.(*besu.ChainConfig); ok {
+		return bc.GetEthashEIP779Transition() != nil
+	}
 	panic(fmt.Sprintf("uimplemented DAO logic, config: %v", c.ChainConfigurator))
 }
 
@@ -161,3 +172,38 @@ func asMapHasAnyKey(input []byte, keys []string) (bool, error) {
 //	}
 //	return false, nil
 //}
+
+// chainConfiguratorFormats maps the names of the chain configuration formats
+// to constructors of their respective data types.
+var chainConfiguratorFormats = map[string]func() ctypes.ChainConfigurator{
+	"coregeth":   func() ctypes.ChainConfigurator { return &coregeth.CoreGethChainConfig{} },
+	"multigeth":  func() ctypes.ChainConfigurator { return &multigeth.ChainConfig{} },
+	"geth":       func() ctypes.ChainConfigurator { return &goethereum.ChainConfig{} },
+	"besu":       func() ctypes.ChainConfigurator { return &besu.ChainConfig{} },
+	"parity":     func() ctypes.ChainConfigurator { return &parity.ParityChainSpec{} },
+	"nethermind": func() ctypes.ChainConfigurator { return &nethermind.NethermindChainSpec{} },
+}
+
+// ChainConfiguratorFormats returns the sorted names of the formats chain
+// configurations can be converted to with ConvertChainConfigurator.
+func ChainConfiguratorFormats() []string {
+	names := make([]string, 0, len(chainConfiguratorFormats))
+	for name := range chainConfiguratorFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConvertChainConfigurator converts the chain configuration to the named format.
+func ConvertChainConfigurator(c ctypes.ChainConfigurator, format string) (ctypes.ChainConfigurator, error) {
+	newConfig, ok := chainConfiguratorFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported chain config format: %s (want one of %s)", format, strings.Join(ChainConfiguratorFormats(), ", "))
+	}
+	converted := newConfig()
+	if err := confp.Convert(c, converted); err != nil {
+		return nil, fmt.Errorf("chain config not convertible to %s: %v", format, err)
+	}
+	return converted, nil
+}
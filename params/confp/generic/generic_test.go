@@ -15,6 +15,7 @@ import (
 	"github.com/ethereum/go-ethereum/params/types/goethereum"
 	"github.com/ethereum/go-ethereum/params/types/multigeth"
 	"github.com/ethereum/go-ethereum/params/types/parity"
+	"github.com/ethereum/go-ethereum/params/vars"
 )
 
 // TestUnmarshalChainConfigurator is a non deterministic test. WTF.
@@ -296,3 +297,74 @@ func TestUnmarshalChainConfigurator2(t *testing.T) {
 		}
 	}
 }
+
+// Tests that EIP-1559 parameters of zero, which the base fee calculation would
+// divide by, are rejected, and that unset ones default.
+func TestUnmarshalChainConfiguratorEIP1559Params(t *testing.T) {
+	for i, raw := range []string{
+		`{"networkId": 1, "chainId": 1, "eip1559FBlock": 0, "eip1559ElasticityMultiplier": 0}`,
+		`{"networkId": 1, "chainId": 1, "eip1559FBlock": 0, "eip1559BaseFeeChangeDenominator": 0}`,
+		`{"engine": {"Ethash": {}}, "params": {"eip1559Transition": "0x0", "eip1559ElasticityMultiplier": "0x0"}}`,
+		`{"engine": {"Ethash": {}}, "params": {"eip1559Transition": "0x0", "eip1559BaseFeeMaxChangeDenominator": 0}}`,
+	} {
+		if conf, err := UnmarshalChainConfigurator([]byte(raw)); err == nil {
+			t.Errorf("%d: expected error, got config %v", i, conf)
+		}
+	}
+	for i, raw := range []string{
+		`{"networkId": 1, "chainId": 1, "eip1559FBlock": 0}`,
+		`{"engine": {"Ethash": {}}, "params": {"eip1559Transition": "0x0"}}`,
+	} {
+		conf, err := UnmarshalChainConfigurator([]byte(raw))
+		if err != nil {
+			t.Fatalf("%d: failed to unmarshal: %v", i, err)
+		}
+		if have := *conf.GetEIP1559ElasticityMultiplier(); have != vars.DefaultElasticityMultiplier {
+			t.Errorf("%d: elasticity multiplier mismatch: have %d, want %d", i, have, vars.DefaultElasticityMultiplier)
+		}
+		if have := *conf.GetEIP1559BaseFeeChangeDenominator(); have != vars.DefaultBaseFeeChangeDenominator {
+			t.Errorf("%d: base fee change denominator mismatch: have %d, want %d", i, have, vars.DefaultBaseFeeChangeDenominator)
+		}
+	}
+}
+
+func TestConvertChainConfigurator(t *testing.T) {
+	var spec struct {
+		Config *goethereum.ChainConfig `json:"config"`
+	}
+	b, err := ioutil.ReadFile(filepath.Join("..", "testdata", "stureby_geth.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &spec); err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range ChainConfiguratorFormats() {
+		converted, err := ConvertChainConfigurator(spec.Config, format)
+		if err != nil {
+			t.Errorf("%s: %v", format, err)
+			continue
+		}
+		// The legacy multigeth format drops EIP-1283 when it's disabled at its
+		// own activation, so only the forks are compared.
+		want := confp.Forks(spec.Config)
+		if have := confp.Forks(converted); !reflect.DeepEqual(have, want) {
+			t.Errorf("%s: forks mismatch: have %v, want %v", format, have, want)
+		}
+		// The converted configuration reads back from its JSON encoding.
+		b, err := json.Marshal(converted)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		decoded := reflect.New(reflect.TypeOf(converted).Elem()).Interface().(ctypes.ChainConfigurator)
+		if err := json.Unmarshal(b, decoded); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if have := confp.Forks(decoded); !reflect.DeepEqual(have, want) {
+			t.Errorf("%s: forks mismatch after decoding: have %v, want %v", format, have, want)
+		}
+	}
+	if _, err := ConvertChainConfigurator(spec.Config, "aleth"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
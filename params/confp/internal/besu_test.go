@@ -0,0 +1,114 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package convert_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/params/confp"
+	"github.com/ethereum/go-ethereum/params/types/besu"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+)
+
+// TestBesuRoundTrip tests that a Besu configuration survives conversion
+// to the core-geth format and back.
+func TestBesuRoundTrip(t *testing.T) {
+	var spec struct {
+		Config *besu.ChainConfig `json:"config"`
+	}
+	mustOpenF(t, "besu", &spec)
+
+	gethSpec := &genesisT.Genesis{}
+	mustOpenF(t, "geth", gethSpec)
+	if err := confp.Equivalent(spec.Config, gethSpec.Config); err != nil {
+		t.Errorf("besu and geth stureby configs not equivalent: %v", err)
+	}
+
+	cg := &coregeth.CoreGethChainConfig{}
+	if err := confp.Convert(spec.Config, cg); err != nil {
+		t.Fatal(err)
+	}
+	if err := confp.Equivalent(spec.Config, cg); err != nil {
+		t.Errorf("not equivalent: %v", err)
+	}
+
+	back := &besu.ChainConfig{}
+	if err := confp.Convert(cg, back); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(spec.Config)
+	got, _ := json.Marshal(back)
+	if string(want) != string(got) {
+		t.Errorf("round trip mismatch\nwant: %s\ngot:  %s", want, got)
+	}
+}
+
+// TestBesuDefaults tests that the builtin chain configurations, including
+// the Ethereum Classic ones, can be represented in the Besu format.
+func TestBesuDefaults(t *testing.T) {
+	for name, config := range map[string]ctypes.ChainConfigurator{
+		"foundation": params.MainnetChainConfig,
+		"ropsten":    params.RopstenChainConfig,
+		"rinkeby":    params.RinkebyChainConfig,
+		"goerli":     params.GoerliChainConfig,
+		"classic":    params.ClassicChainConfig,
+		"mordor":     params.MordorChainConfig,
+		"kotti":      params.KottiChainConfig,
+	} {
+		bc := &besu.ChainConfig{}
+		if err := confp.Convert(config, bc); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if err := confp.Equivalent(config, bc); err != nil {
+			t.Errorf("%s: not equivalent: %v", name, err)
+		}
+
+		// Read the configuration back from JSON, as Besu would.
+		b, err := json.Marshal(bc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bc2 := &besu.ChainConfig{}
+		if err := json.Unmarshal(b, bc2); err != nil {
+			t.Fatal(err)
+		}
+		cg := &coregeth.CoreGethChainConfig{}
+		if err := confp.Convert(bc2, cg); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if err := confp.Equivalent(config, cg); err != nil {
+			t.Errorf("%s: not equivalent after round trip: %v", name, err)
+		}
+	}
+
+	bc := &besu.ChainConfig{}
+	if err := confp.Convert(params.ClassicChainConfig, bc); err != nil {
+		t.Fatal(err)
+	}
+	if bc.AtlantisBlock == nil || bc.AtlantisBlock.Uint64() != 8772000 {
+		t.Errorf("classic atlantis: got %v, want %d", bc.AtlantisBlock, 8772000)
+	}
+	if bc.ByzantiumBlock != nil {
+		t.Errorf("classic byzantium: got %v, want nil", bc.ByzantiumBlock)
+	}
+}
@@ -28,10 +28,12 @@ import (
 	"github.com/ethereum/go-ethereum/params/confp"
 	"github.com/ethereum/go-ethereum/params/confp/tconvert"
 	"github.com/ethereum/go-ethereum/params/types/aleth"
+	"github.com/ethereum/go-ethereum/params/types/besu"
 	"github.com/ethereum/go-ethereum/params/types/coregeth"
 	"github.com/ethereum/go-ethereum/params/types/ctypes"
 	"github.com/ethereum/go-ethereum/params/types/genesisT"
 	"github.com/ethereum/go-ethereum/params/types/goethereum"
+	"github.com/ethereum/go-ethereum/params/types/nethermind"
 	"github.com/ethereum/go-ethereum/params/types/parity"
 )
 
@@ -48,7 +50,7 @@ func mustOpenF(t *testing.T, fabbrev string, into interface{}) {
 
 func Test_UnmarshalJSON(t *testing.T) {
 	for _, f := range []string{
-		"geth", "parity", "aleth",
+		"geth", "parity", "aleth", "besu", "nethermind",
 	} {
 		switch f {
 		case "geth":
@@ -67,6 +69,18 @@ func Test_UnmarshalJSON(t *testing.T) {
 		case "aleth":
 			a := &aleth.AlethGenesisSpec{}
 			mustOpenF(t, f, a)
+		case "besu":
+			c := &genesisT.Genesis{Config: &besu.ChainConfig{}}
+			mustOpenF(t, f, c)
+			if *c.Config.GetNetworkID() != 314158 {
+				t.Errorf("networkid")
+			}
+		case "nethermind":
+			n := &nethermind.NethermindChainSpec{}
+			mustOpenF(t, f, n)
+			if *n.GetNetworkID() != 314158 {
+				t.Errorf("networkid")
+			}
 		}
 	}
 }
@@ -124,6 +138,7 @@ func TestIdentical(t *testing.T) {
 func TestConfiguratorImplementationsSatisfied(t *testing.T) {
 	for _, ty := range []interface{}{
 		&parity.ParityChainSpec{},
+		&nethermind.NethermindChainSpec{},
 	} {
 		_ = ty.(ctypes.Configurator)
 	}
@@ -131,6 +146,7 @@ func TestConfiguratorImplementationsSatisfied(t *testing.T) {
 	for _, ty := range []interface{}{
 		&goethereum.ChainConfig{},
 		&coregeth.CoreGethChainConfig{},
+		&besu.ChainConfig{},
 	} {
 		_ = ty.(ctypes.ChainConfigurator)
 	}
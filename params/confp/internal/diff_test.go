@@ -0,0 +1,101 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package convert_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params/confp"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/types/goethereum"
+)
+
+func TestDiff(t *testing.T) {
+	addr1, addr2 := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+	a := &genesisT.Genesis{
+		Config: &coregeth.CoreGethChainConfig{
+			NetworkID:    1,
+			ChainID:      big.NewInt(1),
+			EIP2FBlock:   big.NewInt(10),
+			EIP7FBlock:   big.NewInt(10),
+			EIP150Block:  big.NewInt(20),
+			EIP155Block:  big.NewInt(30),
+			EIP160FBlock: big.NewInt(30),
+			EIP161FBlock: big.NewInt(30),
+			EIP170FBlock: big.NewInt(30),
+			Ethash:       new(ctypes.EthashConfig),
+		},
+		GasLimit: 5000,
+		Alloc: genesisT.GenesisAlloc{
+			addr1: {Balance: big.NewInt(1)},
+			addr2: {Balance: big.NewInt(2), Storage: map[common.Hash]common.Hash{{0x1}: {0x1}}},
+		},
+	}
+	// An equivalent configuration of a different data type.
+	b := &genesisT.Genesis{
+		Config: &goethereum.ChainConfig{
+			NetworkID:      1,
+			ChainID:        big.NewInt(1),
+			HomesteadBlock: big.NewInt(10),
+			EIP150Block:    big.NewInt(20),
+			EIP155Block:    big.NewInt(30),
+			EIP158Block:    big.NewInt(30),
+			Ethash:         new(ctypes.EthashConfig),
+		},
+		GasLimit: 5000,
+		Alloc: genesisT.GenesisAlloc{
+			addr1: {Balance: big.NewInt(1)},
+			addr2: {Balance: big.NewInt(2), Storage: map[common.Hash]common.Hash{{0x1}: {0x1}}},
+		},
+	}
+	if diffs := confp.Diff(a, b); len(diffs) != 0 {
+		t.Fatalf("want no diffs, got %v", diffs)
+	}
+	if n, err := confp.FirstIncompatibleBlock(a, b); err != nil {
+		t.Fatalf("want compatible, got %v at %d", err, *n)
+	}
+
+	b.Config.(*goethereum.ChainConfig).EIP150Block = big.NewInt(25)
+	b.Alloc[addr2] = genesisT.GenesisAccount{Balance: big.NewInt(3)}
+	diffs := confp.Diff(a, b)
+	want := []string{
+		"Field: EIP150Transition, A: 20, B: 25",
+		fmt.Sprintf("Field: Alloc[%s].Balance, A: 2, B: 3", addr2.Hex()),
+		fmt.Sprintf("Field: Alloc[%s].Storage[%s], A: %v, B: <nil>", addr2.Hex(), common.Hash{0x1}.Hex(), common.Hash{0x1}),
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("want %d diffs, got %d: %v", len(want), len(diffs), diffs)
+	}
+	for i := range want {
+		if got := diffs[i].String(); got != want[i] {
+			t.Errorf("diff %d: want %q, got %q", i, want[i], got)
+		}
+	}
+
+	n, err := confp.FirstIncompatibleBlock(a, b)
+	if err == nil {
+		t.Fatal("want incompatible")
+	}
+	if *n != 20 {
+		t.Errorf("want first incompatible block 20, got %d (%v)", *n, err)
+	}
+}
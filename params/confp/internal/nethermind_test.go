@@ -0,0 +1,116 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package convert_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/params/confp"
+	"github.com/ethereum/go-ethereum/params/confp/tconvert"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/params/types/genesisT"
+	"github.com/ethereum/go-ethereum/params/types/nethermind"
+	"github.com/ethereum/go-ethereum/params/types/parity"
+)
+
+// assertTransitions checks that every transition of the configurations is the same.
+func assertTransitions(t *testing.T, name string, a, b ctypes.ChainConfigurator) {
+	t.Helper()
+
+	want := make(map[string]*uint64)
+	fns, names := confp.Transitions(b)
+	for i, fn := range fns {
+		want[names[i]] = fn()
+	}
+	fns, names = confp.Transitions(a)
+	for i, fn := range fns {
+		have, want := fn(), want[names[i]]
+		if (have == nil) != (want == nil) || (have != nil && *have != *want) {
+			t.Errorf("%s: transition %s mismatch: have %s, want %s", name, names[i], u64String(have), u64String(want))
+		}
+	}
+}
+
+func u64String(n *uint64) string {
+	if n == nil {
+		return "nil"
+	}
+	return strconv.FormatUint(*n, 10)
+}
+
+// TestNethermindEquivalence tests that the Nethermind chainspec agrees on every
+// fork with its Parity counterpart, and on the genesis block with the geth one.
+func TestNethermindEquivalence(t *testing.T) {
+	spec := &nethermind.NethermindChainSpec{}
+	mustOpenF(t, "nethermind", spec)
+
+	paritySpec := &parity.ParityChainSpec{}
+	mustOpenF(t, "parity", paritySpec)
+	gethSpec := &genesisT.Genesis{}
+	mustOpenF(t, "geth", gethSpec)
+
+	if err := confp.Equivalent(spec, paritySpec); err != nil {
+		t.Errorf("nethermind and parity stureby configs not equivalent: %v", err)
+	}
+	assertTransitions(t, "parity", spec, paritySpec)
+
+	genesis, err := tconvert.ParityConfigToCoreGethGenesis(&spec.ParityChainSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := core.GenesisToBlock(genesis, nil).Hash(), core.GenesisToBlock(gethSpec, nil).Hash(); have != want {
+		t.Errorf("genesis hash mismatch: have %x, want %x", have, want)
+	}
+}
+
+// TestNethermindRoundTrip tests that a Nethermind chainspec survives conversion
+// to the core-geth format and back, keeping its fork blocks.
+func TestNethermindRoundTrip(t *testing.T) {
+	spec := &nethermind.NethermindChainSpec{}
+	mustOpenF(t, "nethermind", spec)
+
+	cg := &coregeth.CoreGethChainConfig{}
+	if err := confp.Convert(spec, cg); err != nil {
+		t.Fatal(err)
+	}
+	if err := confp.Equivalent(spec, cg); err != nil {
+		t.Errorf("not equivalent: %v", err)
+	}
+	assertTransitions(t, "coregeth", cg, spec)
+
+	back := &nethermind.NethermindChainSpec{}
+	if err := confp.Convert(cg, back); err != nil {
+		t.Fatal(err)
+	}
+	// Read the chainspec back from JSON, as Nethermind would.
+	b, err := json.Marshal(back)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back = &nethermind.NethermindChainSpec{}
+	if err := json.Unmarshal(b, back); err != nil {
+		t.Fatal(err)
+	}
+	if err := confp.Equivalent(spec, back); err != nil {
+		t.Errorf("not equivalent after round trip: %v", err)
+	}
+	assertTransitions(t, "round trip", back, spec)
+}
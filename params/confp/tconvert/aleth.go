@@ -70,6 +70,24 @@ func NewAlethGenesisSpec(network string, genesis *genesisT.Genesis) (*aleth.Alet
 	if num := genesis.Config.GetEIP145Transition(); num != nil {
 		spec.Params.IstanbulForkBlock = (*hexutil.Big)(((*hexutil.Uint64)(num)).Big())
 	}
+	if num := genesis.Config.GetEIP1559Transition(); num != nil {
+		// Aleth only supports the canonical EIP1559 base fee parameters.
+		if *genesis.Config.GetEIP1559ElasticityMultiplier() != vars.DefaultElasticityMultiplier ||
+			*genesis.Config.GetEIP1559BaseFeeChangeDenominator() != vars.DefaultBaseFeeChangeDenominator {
+			return nil, errors.New("unsupported EIP1559 base fee parameters")
+		}
+		// Aleth bundles the London EIPs under a single fork block.
+		for _, f := range []func() *uint64{
+			genesis.Config.GetEIP3198Transition,
+			genesis.Config.GetEIP3529Transition,
+			genesis.Config.GetEIP3541Transition,
+		} {
+			if n := f(); n == nil || *n != *num {
+				return nil, errors.New("unsupported London EIP transitions")
+			}
+		}
+		spec.Params.LondonForkBlock = (*hexutil.Big)(((*hexutil.Uint64)(num)).Big())
+	}
 	spec.Params.NetworkID = (hexutil.Uint64)(genesis.Config.GetChainID().Uint64())
 	spec.Params.ChainID = (hexutil.Uint64)(genesis.Config.GetChainID().Uint64())
 	spec.Params.MaximumExtraDataSize = (hexutil.Uint64)(vars.MaximumExtraDataSize)
@@ -38,6 +38,7 @@ type AlethGenesisSpec struct {
 		ConstantinopleForkBlock    *hexutil.Big          `json:"constantinopleForkBlock,omitempty"`
 		ConstantinopleFixForkBlock *hexutil.Big          `json:"constantinopleFixForkBlock,omitempty"`
 		IstanbulForkBlock          *hexutil.Big          `json:"istanbulForkBlock,omitempty"`
+		LondonForkBlock            *hexutil.Big          `json:"londonForkBlock,omitempty"`
 		MinGasLimit                hexutil.Uint64        `json:"minGasLimit"`
 		MaxGasLimit                hexutil.Uint64        `json:"maxGasLimit"`
 		TieBreakingGas             bool                  `json:"tieBreakingGas"`
@@ -0,0 +1,126 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package besu
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChainConfig is the "config" object of the genesis file format used by Hyperledger Besu.
+// Besu schedules protocol upgrades by named hard forks. The Ethereum and Ethereum Classic
+// forks have distinct names and bundle different sets of EIPs; a configuration
+// using any of the Ethereum Classic forks is interpreted with the Ethereum Classic schedule.
+type ChainConfig struct {
+	NetworkID uint64   `json:"-"`
+	ChainID   *big.Int `json:"chainId"` // chainId identifies the current chain and is used for replay protection
+
+	// HF: Homestead
+	HomesteadBlock *big.Int `json:"homesteadBlock,omitempty"`
+
+	// Ethereum hard forks.
+	DAOForkBlock        *big.Int    `json:"daoForkBlock,omitempty"` // TheDAO hard-fork switch block; Besu has no opt-out flag
+	EIP150Block         *big.Int    `json:"eip150Block,omitempty"`
+	EIP150Hash          common.Hash `json:"eip150Hash,omitempty"`
+	EIP155Block         *big.Int    `json:"eip155Block,omitempty"`
+	EIP158Block         *big.Int    `json:"eip158Block,omitempty"` // Spurious Dragon, includes 160, 161 and 170
+	ByzantiumBlock      *big.Int    `json:"byzantiumBlock,omitempty"`
+	ConstantinopleBlock *big.Int    `json:"constantinopleBlock,omitempty"`
+	PetersburgBlock     *big.Int    `json:"petersburgBlock,omitempty"`
+	IstanbulBlock       *big.Int    `json:"istanbulBlock,omitempty"`
+	MuirGlacierBlock    *big.Int    `json:"muirGlacierBlock,omitempty"`
+	BerlinBlock         *big.Int    `json:"berlinBlock,omitempty"`
+	LondonBlock         *big.Int    `json:"londonBlock,omitempty"`
+
+	// Ethereum Classic hard forks.
+	ClassicForkBlock  *big.Int `json:"classicForkBlock,omitempty"` // The DAO fork block, which ETC did not adopt; informational only
+	ECIP1015Block     *big.Int `json:"ecip1015Block,omitempty"`    // Tangerine Whistle (EIP150) equivalent
+	DieHardBlock      *big.Int `json:"diehardBlock,omitempty"`     // EIP155, EIP160 and ECIP1010 (difficulty bomb pause)
+	GothamBlock       *big.Int `json:"gothamBlock,omitempty"`      // ECIP1017 (monetary policy) and ECIP1010 (bomb continue)
+	ECIP1041Block     *big.Int `json:"ecip1041Block,omitempty"`    // Difficulty bomb removal
+	AtlantisBlock     *big.Int `json:"atlantisBlock,omitempty"`    // Spurious Dragon (161, 170) and Byzantium equivalent
+	AghartaBlock      *big.Int `json:"aghartaBlock,omitempty"`     // Petersburg equivalent
+	PhoenixBlock      *big.Int `json:"phoenixBlock,omitempty"`     // Istanbul equivalent
+	ThanosBlock       *big.Int `json:"thanosBlock,omitempty"`      // ECIP1099 (Etchash)
+	MagnetoBlock      *big.Int `json:"magnetoBlock,omitempty"`     // Berlin equivalent
+	ECIP1017EraRounds *big.Int `json:"ecip1017EraRounds,omitempty"`
+
+	ContractSizeLimit *uint64 `json:"contractSizeLimit,omitempty"`
+
+	// Various consensus engines
+	Ethash *EthashConfig `json:"ethash,omitempty"`
+	Clique *CliqueConfig `json:"clique,omitempty"`
+}
+
+// EthashConfig is the consensus engine configuration for proof-of-work based sealing.
+type EthashConfig struct {
+	// FixedDifficulty is a Besu development option. It is preserved, but not interpreted.
+	FixedDifficulty *uint64 `json:"fixeddifficulty,omitempty"`
+}
+
+// CliqueConfig is the consensus engine configuration for proof-of-authority based sealing.
+type CliqueConfig struct {
+	BlockPeriodSeconds uint64 `json:"blockperiodseconds"` // Number of seconds between blocks to enforce
+	EpochLength        uint64 `json:"epochlength"`        // Epoch length to reset votes and checkpoint
+}
+
+// String implements the fmt.Stringer interface.
+func (c *ChainConfig) String() string {
+	var engine interface{}
+	switch {
+	case c.Ethash != nil:
+		engine = "ethash"
+	case c.Clique != nil:
+		engine = "clique"
+	default:
+		engine = "unknown"
+	}
+	if c.isClassic() {
+		return fmt.Sprintf("{ChainID: %v Homestead: %v ECIP1015: %v DieHard: %v Gotham: %v ECIP1041: %v Atlantis: %v Agharta: %v Phoenix: %v Thanos: %v Magneto: %v Engine: %v}",
+			c.ChainID,
+			c.HomesteadBlock,
+			c.ECIP1015Block,
+			c.DieHardBlock,
+			c.GothamBlock,
+			c.ECIP1041Block,
+			c.AtlantisBlock,
+			c.AghartaBlock,
+			c.PhoenixBlock,
+			c.ThanosBlock,
+			c.MagnetoBlock,
+			engine,
+		)
+	}
+	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v Petersburg: %v Istanbul: %v MuirGlacier: %v Berlin: %v London: %v Engine: %v}",
+		c.ChainID,
+		c.HomesteadBlock,
+		c.DAOForkBlock,
+		c.EIP150Block,
+		c.EIP155Block,
+		c.EIP158Block,
+		c.ByzantiumBlock,
+		c.ConstantinopleBlock,
+		c.PetersburgBlock,
+		c.IstanbulBlock,
+		c.MuirGlacierBlock,
+		c.BerlinBlock,
+		c.LondonBlock,
+		engine,
+	)
+}
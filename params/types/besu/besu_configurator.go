@@ -0,0 +1,959 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package besu
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+	"github.com/ethereum/go-ethereum/params/types/internal"
+	"github.com/ethereum/go-ethereum/params/vars"
+)
+
+// File contains the Besu implementation of the Configurator interface.
+// Besu bundles features into named forks, so setters only assign a fork
+// once; setting a feature to a block different from the one its fork is already
+// configured at is a fatal error, since the result would be undetermined.
+// Setting a feature to nil is a no-op, since unsetting a single feature
+// of a fork cannot be represented.
+//
+// Configurations are built with the Ethereum forks until they set a feature
+// which the Ethereum schedule cannot express (eg. EIP160 and EIP161 at
+// different blocks, or ECIP1017), at which point the configured forks
+// are moved to their Ethereum Classic equivalents.
+
+func newU64(u uint64) *uint64 {
+	return &u
+}
+
+func bigNewU64(i *big.Int) *uint64 {
+	if i == nil {
+		return nil
+	}
+	return newU64(i.Uint64())
+}
+
+// setBig sets the fork block to n, failing if the fork is already
+// configured at a different block.
+func setBig(i **big.Int, n *uint64) error {
+	if *i != nil && (*i).Uint64() != *n {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	*i = new(big.Int).SetUint64(*n)
+	return nil
+}
+
+// classicForks returns the Ethereum Classic named fork fields.
+func (c *ChainConfig) classicForks() []**big.Int {
+	return []**big.Int{
+		&c.ClassicForkBlock,
+		&c.ECIP1015Block,
+		&c.DieHardBlock,
+		&c.GothamBlock,
+		&c.ECIP1041Block,
+		&c.AtlantisBlock,
+		&c.AghartaBlock,
+		&c.PhoenixBlock,
+		&c.ThanosBlock,
+		&c.MagnetoBlock,
+		&c.ECIP1017EraRounds,
+	}
+}
+
+// isClassic reports whether the configuration uses the Ethereum Classic schedule.
+func (c *ChainConfig) isClassic() bool {
+	for _, f := range c.classicForks() {
+		if *f != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// fork returns the block of the fork holding a feature, given the
+// Ethereum and Ethereum Classic forks which include it.
+func (c *ChainConfig) fork(eth, etc *big.Int) *uint64 {
+	if c.isClassic() {
+		return bigNewU64(etc)
+	}
+	return bigNewU64(eth)
+}
+
+// setFork configures the fork holding a feature to n. Either fork field may be nil
+// when the respective schedule does not include the feature.
+func (c *ChainConfig) setFork(eth, etc **big.Int, n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	if !c.isClassic() {
+		if eth != nil && setBig(eth, n) == nil {
+			return nil
+		}
+		if etc == nil {
+			return ctypes.ErrUnsupportedConfigFatal
+		}
+		if err := c.classicize(); err != nil {
+			return err
+		}
+	}
+	if etc == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return setBig(etc, n)
+}
+
+// classicize moves the configured Ethereum forks to their Ethereum Classic equivalents.
+func (c *ChainConfig) classicize() error {
+	if c.DAOForkBlock != nil || c.MuirGlacierBlock != nil || c.LondonBlock != nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	// Agharta is Constantinople without EIP1283, ie. Petersburg.
+	if c.PetersburgBlock != nil && (c.ConstantinopleBlock == nil || c.PetersburgBlock.Cmp(c.ConstantinopleBlock) != 0) {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.ECIP1015Block = c.EIP150Block
+	c.DieHardBlock = c.EIP155Block
+	c.AtlantisBlock = c.ByzantiumBlock
+	c.AghartaBlock = c.ConstantinopleBlock
+	c.PhoenixBlock = c.IstanbulBlock
+	c.MagnetoBlock = c.BerlinBlock
+
+	// Spurious Dragon features are split between Die Hard (EIP160) and Atlantis (EIP161, EIP170),
+	// so the fork must match one of them.
+	if c.EIP158Block != nil {
+		n := c.EIP158Block.Uint64()
+		if c.DieHardBlock == nil {
+			c.DieHardBlock = new(big.Int).Set(c.EIP158Block)
+		} else if c.DieHardBlock.Uint64() != n {
+			if err := setBig(&c.AtlantisBlock, &n); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.EIP150Block = nil
+	c.EIP155Block = nil
+	c.EIP158Block = nil
+	c.ByzantiumBlock = nil
+	c.ConstantinopleBlock = nil
+	c.PetersburgBlock = nil
+	c.IstanbulBlock = nil
+	c.BerlinBlock = nil
+	return nil
+}
+
+// bombDisposedBy reports whether the difficulty bomb is removed (ECIP1041) at or before block n.
+// Besu ties the ECIP1010 bomb pause to the Die Hard fork, which has no effect
+// if the bomb has already been removed.
+func (c *ChainConfig) bombDisposedBy(n *big.Int) bool {
+	return n != nil && c.ECIP1041Block != nil && c.ECIP1041Block.Cmp(n) <= 0
+}
+
+func (c *ChainConfig) GetAccountStartNonce() *uint64 {
+	return internal.GlobalConfigurator().GetAccountStartNonce()
+}
+
+func (c *ChainConfig) SetAccountStartNonce(n *uint64) error {
+	return internal.GlobalConfigurator().SetAccountStartNonce(n)
+}
+
+func (c *ChainConfig) GetMaximumExtraDataSize() *uint64 {
+	return internal.GlobalConfigurator().GetMaximumExtraDataSize()
+}
+
+func (c *ChainConfig) SetMaximumExtraDataSize(n *uint64) error {
+	return internal.GlobalConfigurator().SetMaximumExtraDataSize(n)
+}
+
+func (c *ChainConfig) GetMinGasLimit() *uint64 {
+	return internal.GlobalConfigurator().GetMinGasLimit()
+}
+
+func (c *ChainConfig) SetMinGasLimit(n *uint64) error {
+	return internal.GlobalConfigurator().SetMinGasLimit(n)
+}
+
+func (c *ChainConfig) GetGasLimitBoundDivisor() *uint64 {
+	return internal.GlobalConfigurator().GetGasLimitBoundDivisor()
+}
+
+func (c *ChainConfig) SetGasLimitBoundDivisor(n *uint64) error {
+	return internal.GlobalConfigurator().SetGasLimitBoundDivisor(n)
+}
+
+// GetNetworkID returns the chain ID unless configured otherwise,
+// since the Besu genesis format has no network ID field.
+func (c *ChainConfig) GetNetworkID() *uint64 {
+	if c.NetworkID != 0 {
+		return &c.NetworkID
+	}
+	if c.ChainID != nil {
+		return newU64(c.ChainID.Uint64())
+	}
+	return newU64(vars.DefaultNetworkID)
+}
+
+func (c *ChainConfig) SetNetworkID(n *uint64) error {
+	if n == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	if c.ChainID == nil {
+		c.ChainID = new(big.Int).SetUint64(*n)
+	}
+	c.NetworkID = *n
+	return nil
+}
+
+func (c *ChainConfig) GetChainID() *big.Int {
+	return c.ChainID
+}
+
+func (c *ChainConfig) SetChainID(n *big.Int) error {
+	c.ChainID = n
+	return nil
+}
+
+func (c *ChainConfig) GetSupportedProtocolVersions() []uint {
+	return vars.DefaultProtocolVersions
+}
+
+func (c *ChainConfig) SetSupportedProtocolVersions(p []uint) error {
+	return ctypes.ErrUnsupportedConfigNoop
+}
+
+func (c *ChainConfig) GetMaxCodeSize() *uint64 {
+	if c.ContractSizeLimit != nil {
+		return c.ContractSizeLimit
+	}
+	return internal.GlobalConfigurator().GetMaxCodeSize()
+}
+
+func (c *ChainConfig) SetMaxCodeSize(n *uint64) error {
+	if n == nil || *n == *internal.GlobalConfigurator().GetMaxCodeSize() {
+		c.ContractSizeLimit = nil
+		return nil
+	}
+	c.ContractSizeLimit = newU64(*n)
+	return nil
+}
+
+func (c *ChainConfig) GetEIP7Transition() *uint64 {
+	return bigNewU64(c.HomesteadBlock)
+}
+
+func (c *ChainConfig) SetEIP7Transition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return setBig(&c.HomesteadBlock, n)
+}
+
+func (c *ChainConfig) GetEIP150Transition() *uint64 {
+	return c.fork(c.EIP150Block, c.ECIP1015Block)
+}
+
+func (c *ChainConfig) SetEIP150Transition(n *uint64) error {
+	return c.setFork(&c.EIP150Block, &c.ECIP1015Block, n)
+}
+
+func (c *ChainConfig) GetEIP152Transition() *uint64 {
+	return c.fork(c.IstanbulBlock, c.PhoenixBlock)
+}
+
+func (c *ChainConfig) SetEIP152Transition(n *uint64) error {
+	return c.setFork(&c.IstanbulBlock, &c.PhoenixBlock, n)
+}
+
+func (c *ChainConfig) GetEIP160Transition() *uint64 {
+	return c.fork(c.EIP158Block, c.DieHardBlock)
+}
+
+func (c *ChainConfig) SetEIP160Transition(n *uint64) error {
+	return c.setFork(&c.EIP158Block, &c.DieHardBlock, n)
+}
+
+func (c *ChainConfig) GetEIP161dTransition() *uint64 {
+	return c.fork(c.EIP158Block, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEIP161dTransition(n *uint64) error {
+	return c.setFork(&c.EIP158Block, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEIP161abcTransition() *uint64 {
+	return c.fork(c.EIP158Block, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEIP161abcTransition(n *uint64) error {
+	return c.setFork(&c.EIP158Block, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEIP170Transition() *uint64 {
+	return c.fork(c.EIP158Block, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEIP170Transition(n *uint64) error {
+	return c.setFork(&c.EIP158Block, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEIP155Transition() *uint64 {
+	return c.fork(c.EIP155Block, c.DieHardBlock)
+}
+
+func (c *ChainConfig) SetEIP155Transition(n *uint64) error {
+	return c.setFork(&c.EIP155Block, &c.DieHardBlock, n)
+}
+
+func (c *ChainConfig) GetEIP140Transition() *uint64 {
+	return c.fork(c.ByzantiumBlock, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEIP140Transition(n *uint64) error {
+	return c.setFork(&c.ByzantiumBlock, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEIP198Transition() *uint64 {
+	return c.fork(c.ByzantiumBlock, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEIP198Transition(n *uint64) error {
+	return c.setFork(&c.ByzantiumBlock, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEIP211Transition() *uint64 {
+	return c.fork(c.ByzantiumBlock, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEIP211Transition(n *uint64) error {
+	return c.setFork(&c.ByzantiumBlock, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEIP212Transition() *uint64 {
+	return c.fork(c.ByzantiumBlock, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEIP212Transition(n *uint64) error {
+	return c.setFork(&c.ByzantiumBlock, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEIP213Transition() *uint64 {
+	return c.fork(c.ByzantiumBlock, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEIP213Transition(n *uint64) error {
+	return c.setFork(&c.ByzantiumBlock, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEIP214Transition() *uint64 {
+	return c.fork(c.ByzantiumBlock, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEIP214Transition(n *uint64) error {
+	return c.setFork(&c.ByzantiumBlock, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEIP658Transition() *uint64 {
+	return c.fork(c.ByzantiumBlock, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEIP658Transition(n *uint64) error {
+	return c.setFork(&c.ByzantiumBlock, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEIP145Transition() *uint64 {
+	return c.fork(c.ConstantinopleBlock, c.AghartaBlock)
+}
+
+func (c *ChainConfig) SetEIP145Transition(n *uint64) error {
+	return c.setFork(&c.ConstantinopleBlock, &c.AghartaBlock, n)
+}
+
+func (c *ChainConfig) GetEIP1014Transition() *uint64 {
+	return c.fork(c.ConstantinopleBlock, c.AghartaBlock)
+}
+
+func (c *ChainConfig) SetEIP1014Transition(n *uint64) error {
+	return c.setFork(&c.ConstantinopleBlock, &c.AghartaBlock, n)
+}
+
+func (c *ChainConfig) GetEIP1052Transition() *uint64 {
+	return c.fork(c.ConstantinopleBlock, c.AghartaBlock)
+}
+
+func (c *ChainConfig) SetEIP1052Transition(n *uint64) error {
+	return c.setFork(&c.ConstantinopleBlock, &c.AghartaBlock, n)
+}
+
+func (c *ChainConfig) GetEIP1283Transition() *uint64 {
+	return c.fork(c.ConstantinopleBlock, nil)
+}
+
+// SetEIP1283Transition treats an unset EIP1283 alongside configured Constantinople
+// features as the Ethereum Classic Agharta fork, which omits it.
+func (c *ChainConfig) SetEIP1283Transition(n *uint64) error {
+	if n == nil {
+		if !c.isClassic() && c.ConstantinopleBlock != nil {
+			return c.classicize()
+		}
+		return nil
+	}
+	return c.setFork(&c.ConstantinopleBlock, nil, n)
+}
+
+func (c *ChainConfig) GetEIP1283DisableTransition() *uint64 {
+	return c.fork(c.PetersburgBlock, nil)
+}
+
+func (c *ChainConfig) SetEIP1283DisableTransition(n *uint64) error {
+	return c.setFork(&c.PetersburgBlock, nil, n)
+}
+
+func (c *ChainConfig) GetEIP1108Transition() *uint64 {
+	return c.fork(c.IstanbulBlock, c.PhoenixBlock)
+}
+
+func (c *ChainConfig) SetEIP1108Transition(n *uint64) error {
+	return c.setFork(&c.IstanbulBlock, &c.PhoenixBlock, n)
+}
+
+func (c *ChainConfig) GetEIP2200Transition() *uint64 {
+	return c.fork(c.IstanbulBlock, c.PhoenixBlock)
+}
+
+func (c *ChainConfig) SetEIP2200Transition(n *uint64) error {
+	return c.setFork(&c.IstanbulBlock, &c.PhoenixBlock, n)
+}
+
+func (c *ChainConfig) GetEIP2200DisableTransition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetEIP2200DisableTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetEIP1344Transition() *uint64 {
+	return c.fork(c.IstanbulBlock, c.PhoenixBlock)
+}
+
+func (c *ChainConfig) SetEIP1344Transition(n *uint64) error {
+	return c.setFork(&c.IstanbulBlock, &c.PhoenixBlock, n)
+}
+
+func (c *ChainConfig) GetEIP1884Transition() *uint64 {
+	return c.fork(c.IstanbulBlock, c.PhoenixBlock)
+}
+
+func (c *ChainConfig) SetEIP1884Transition(n *uint64) error {
+	return c.setFork(&c.IstanbulBlock, &c.PhoenixBlock, n)
+}
+
+func (c *ChainConfig) GetEIP2028Transition() *uint64 {
+	return c.fork(c.IstanbulBlock, c.PhoenixBlock)
+}
+
+func (c *ChainConfig) SetEIP2028Transition(n *uint64) error {
+	return c.setFork(&c.IstanbulBlock, &c.PhoenixBlock, n)
+}
+
+func (c *ChainConfig) GetECIP1080Transition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetECIP1080Transition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetEIP1706Transition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetEIP1706Transition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetEIP2537Transition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetEIP2537Transition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetECBP1100Transition() *uint64 {
+	return nil
+}
+
+// SetECBP1100Transition is a no-op; ECBP1100 (MESS) is a client-side
+// chain acceptance policy rather than a consensus rule.
+func (c *ChainConfig) SetECBP1100Transition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigNoop
+}
+
+func (c *ChainConfig) GetECBP1100Policy() *ctypes.ArtificialFinalityPolicy {
+	return nil
+}
+
+// SetECBP1100Policy is a no-op, as is SetECBP1100Transition.
+func (c *ChainConfig) SetECBP1100Policy(p *ctypes.ArtificialFinalityPolicy) error {
+	if p == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigNoop
+}
+
+func (c *ChainConfig) GetEIP2315Transition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetEIP2315Transition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetEIP2929Transition() *uint64 {
+	return c.fork(c.BerlinBlock, c.MagnetoBlock)
+}
+
+func (c *ChainConfig) SetEIP2929Transition(n *uint64) error {
+	return c.setFork(&c.BerlinBlock, &c.MagnetoBlock, n)
+}
+
+func (c *ChainConfig) GetEIP2930Transition() *uint64 {
+	return c.fork(c.BerlinBlock, c.MagnetoBlock)
+}
+
+func (c *ChainConfig) SetEIP2930Transition(n *uint64) error {
+	return c.setFork(&c.BerlinBlock, &c.MagnetoBlock, n)
+}
+
+func (c *ChainConfig) GetEIP2565Transition() *uint64 {
+	return c.fork(c.BerlinBlock, c.MagnetoBlock)
+}
+
+func (c *ChainConfig) SetEIP2565Transition(n *uint64) error {
+	return c.setFork(&c.BerlinBlock, &c.MagnetoBlock, n)
+}
+
+func (c *ChainConfig) GetEIP2718Transition() *uint64 {
+	return c.fork(c.BerlinBlock, c.MagnetoBlock)
+}
+
+func (c *ChainConfig) SetEIP2718Transition(n *uint64) error {
+	return c.setFork(&c.BerlinBlock, &c.MagnetoBlock, n)
+}
+
+func (c *ChainConfig) GetEIP1559Transition() *uint64 {
+	return c.fork(c.LondonBlock, nil)
+}
+
+func (c *ChainConfig) SetEIP1559Transition(n *uint64) error {
+	return c.setFork(&c.LondonBlock, nil, n)
+}
+
+func (c *ChainConfig) GetEIP1559ElasticityMultiplier() *uint64 {
+	return newU64(vars.DefaultElasticityMultiplier)
+}
+
+func (c *ChainConfig) SetEIP1559ElasticityMultiplier(n *uint64) error {
+	if n == nil || *n == vars.DefaultElasticityMultiplier {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetEIP1559BaseFeeChangeDenominator() *uint64 {
+	return newU64(vars.DefaultBaseFeeChangeDenominator)
+}
+
+func (c *ChainConfig) SetEIP1559BaseFeeChangeDenominator(n *uint64) error {
+	if n == nil || *n == vars.DefaultBaseFeeChangeDenominator {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetEIP3198Transition() *uint64 {
+	return c.fork(c.LondonBlock, nil)
+}
+
+func (c *ChainConfig) SetEIP3198Transition(n *uint64) error {
+	return c.setFork(&c.LondonBlock, nil, n)
+}
+
+func (c *ChainConfig) GetEIP3529Transition() *uint64 {
+	return c.fork(c.LondonBlock, nil)
+}
+
+func (c *ChainConfig) SetEIP3529Transition(n *uint64) error {
+	return c.setFork(&c.LondonBlock, nil, n)
+}
+
+func (c *ChainConfig) GetEIP3541Transition() *uint64 {
+	return c.fork(c.LondonBlock, nil)
+}
+
+func (c *ChainConfig) SetEIP3541Transition(n *uint64) error {
+	return c.setFork(&c.LondonBlock, nil, n)
+}
+
+func (c *ChainConfig) IsEnabled(fn func() *uint64, n *big.Int) bool {
+	f := fn()
+	if f == nil || n == nil {
+		return false
+	}
+	return big.NewInt(int64(*f)).Cmp(n) <= 0
+}
+
+func (c *ChainConfig) GetForkCanonHash(n uint64) common.Hash {
+	if c.EIP150Block != nil && c.EIP150Block.Uint64() == n {
+		return c.EIP150Hash
+	}
+	return common.Hash{}
+}
+
+func (c *ChainConfig) SetForkCanonHash(n uint64, h common.Hash) error {
+	if c.EIP150Block != nil && c.EIP150Block.Uint64() == n {
+		c.EIP150Hash = h
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigNoop
+}
+
+func (c *ChainConfig) GetForkCanonHashes() map[uint64]common.Hash {
+	if c.EIP150Block == nil || c.EIP150Hash == (common.Hash{}) {
+		return nil
+	}
+	return map[uint64]common.Hash{
+		c.EIP150Block.Uint64(): c.EIP150Hash,
+	}
+}
+
+func (c *ChainConfig) GetConsensusEngineType() ctypes.ConsensusEngineT {
+	if c.Clique != nil {
+		return ctypes.ConsensusEngineT_Clique
+	}
+	return ctypes.ConsensusEngineT_Ethash
+}
+
+func (c *ChainConfig) MustSetConsensusEngineType(t ctypes.ConsensusEngineT) error {
+	switch t {
+	case ctypes.ConsensusEngineT_Ethash:
+		c.Ethash = new(EthashConfig)
+		c.Clique = nil
+		return nil
+	case ctypes.ConsensusEngineT_Clique:
+		c.Clique = new(CliqueConfig)
+		c.Ethash = nil
+		return nil
+	default:
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+}
+
+func (c *ChainConfig) GetCatalystTransition() *uint64 {
+	return nil
+}
+
+func (c *ChainConfig) SetCatalystTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetEthashMinimumDifficulty() *big.Int {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return internal.GlobalConfigurator().GetEthashMinimumDifficulty()
+}
+
+func (c *ChainConfig) SetEthashMinimumDifficulty(i *big.Int) error {
+	return internal.GlobalConfigurator().SetEthashMinimumDifficulty(i)
+}
+
+func (c *ChainConfig) GetEthashDifficultyBoundDivisor() *big.Int {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return internal.GlobalConfigurator().GetEthashDifficultyBoundDivisor()
+}
+
+func (c *ChainConfig) SetEthashDifficultyBoundDivisor(i *big.Int) error {
+	return internal.GlobalConfigurator().SetEthashDifficultyBoundDivisor(i)
+}
+
+func (c *ChainConfig) GetEthashDurationLimit() *big.Int {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return internal.GlobalConfigurator().GetEthashDurationLimit()
+}
+
+func (c *ChainConfig) SetEthashDurationLimit(i *big.Int) error {
+	return internal.GlobalConfigurator().SetEthashDurationLimit(i)
+}
+
+func (c *ChainConfig) GetEthashHomesteadTransition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return bigNewU64(c.HomesteadBlock)
+}
+
+func (c *ChainConfig) SetEthashHomesteadTransition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return setBig(&c.HomesteadBlock, n)
+}
+
+func (c *ChainConfig) GetEIP2Transition() *uint64 {
+	return bigNewU64(c.HomesteadBlock)
+}
+
+func (c *ChainConfig) SetEIP2Transition(n *uint64) error {
+	if n == nil {
+		return nil
+	}
+	return setBig(&c.HomesteadBlock, n)
+}
+
+func (c *ChainConfig) GetEthashEIP779Transition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return c.fork(c.DAOForkBlock, nil)
+}
+
+func (c *ChainConfig) SetEthashEIP779Transition(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(&c.DAOForkBlock, nil, n)
+}
+
+func (c *ChainConfig) GetEthashEIP649Transition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return c.fork(c.ByzantiumBlock, nil)
+}
+
+func (c *ChainConfig) SetEthashEIP649Transition(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(&c.ByzantiumBlock, nil, n)
+}
+
+func (c *ChainConfig) GetEthashEIP1234Transition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return c.fork(c.ConstantinopleBlock, nil)
+}
+
+func (c *ChainConfig) SetEthashEIP1234Transition(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(&c.ConstantinopleBlock, nil, n)
+}
+
+func (c *ChainConfig) GetEthashEIP2384Transition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return c.fork(c.MuirGlacierBlock, nil)
+}
+
+func (c *ChainConfig) SetEthashEIP2384Transition(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(&c.MuirGlacierBlock, nil, n)
+}
+
+func (c *ChainConfig) GetEthashECIP1010PauseTransition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	if c.bombDisposedBy(c.DieHardBlock) {
+		return nil
+	}
+	return c.fork(nil, c.DieHardBlock)
+}
+
+func (c *ChainConfig) SetEthashECIP1010PauseTransition(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(nil, &c.DieHardBlock, n)
+}
+
+func (c *ChainConfig) GetEthashECIP1010ContinueTransition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	if c.bombDisposedBy(c.DieHardBlock) {
+		return nil
+	}
+	return c.fork(nil, c.GothamBlock)
+}
+
+func (c *ChainConfig) SetEthashECIP1010ContinueTransition(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(nil, &c.GothamBlock, n)
+}
+
+func (c *ChainConfig) GetEthashECIP1017Transition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return c.fork(nil, c.GothamBlock)
+}
+
+func (c *ChainConfig) SetEthashECIP1017Transition(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(nil, &c.GothamBlock, n)
+}
+
+func (c *ChainConfig) GetEthashECIP1017EraRounds() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return c.fork(nil, c.ECIP1017EraRounds)
+}
+
+func (c *ChainConfig) SetEthashECIP1017EraRounds(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(nil, &c.ECIP1017EraRounds, n)
+}
+
+func (c *ChainConfig) GetEthashEIP100BTransition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return c.fork(c.ByzantiumBlock, c.AtlantisBlock)
+}
+
+func (c *ChainConfig) SetEthashEIP100BTransition(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(&c.ByzantiumBlock, &c.AtlantisBlock, n)
+}
+
+func (c *ChainConfig) GetEthashECIP1041Transition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return c.fork(nil, c.ECIP1041Block)
+}
+
+func (c *ChainConfig) SetEthashECIP1041Transition(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(nil, &c.ECIP1041Block, n)
+}
+
+func (c *ChainConfig) GetEthashECIP1099Transition() *uint64 {
+	if c.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
+		return nil
+	}
+	return c.fork(nil, c.ThanosBlock)
+}
+
+func (c *ChainConfig) SetEthashECIP1099Transition(n *uint64) error {
+	if c.Ethash == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	return c.setFork(nil, &c.ThanosBlock, n)
+}
+
+func (c *ChainConfig) GetEthashDifficultyBombDelaySchedule() ctypes.Uint64BigMapEncodesHex {
+	return nil
+}
+
+func (c *ChainConfig) SetEthashDifficultyBombDelaySchedule(m ctypes.Uint64BigMapEncodesHex) error {
+	return ctypes.ErrUnsupportedConfigNoop
+}
+
+func (c *ChainConfig) GetEthashBlockRewardSchedule() ctypes.Uint64BigMapEncodesHex {
+	return nil
+}
+
+func (c *ChainConfig) SetEthashBlockRewardSchedule(m ctypes.Uint64BigMapEncodesHex) error {
+	return ctypes.ErrUnsupportedConfigNoop
+}
+
+func (c *ChainConfig) GetCliquePeriod() uint64 {
+	if c.Clique == nil {
+		return 0
+	}
+	return c.Clique.BlockPeriodSeconds
+}
+
+func (c *ChainConfig) SetCliquePeriod(n uint64) error {
+	if c.Clique == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.Clique.BlockPeriodSeconds = n
+	return nil
+}
+
+func (c *ChainConfig) GetCliqueEpoch() uint64 {
+	if c.Clique == nil {
+		return 0
+	}
+	return c.Clique.EpochLength
+}
+
+func (c *ChainConfig) SetCliqueEpoch(n uint64) error {
+	if c.Clique == nil {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	c.Clique.EpochLength = n
+	return nil
+}
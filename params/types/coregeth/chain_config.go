@@ -17,6 +17,8 @@
 package coregeth
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -177,6 +179,10 @@ type CoreGethChainConfig struct {
 	ECIP1099FBlock *big.Int `json:"ecip1099FBlock,omitempty"` // ECIP1099 etchash HF block
 	ECBP1100FBlock *big.Int `json:"ecbp1100FBlock,omitempty"` // ECBP1100:MESS artificial finality
 
+	// ECBP1100Policy selects the artificial finality policy used from the ECBP1100FBlock.
+	// When nil, the ECBP1100 (MESS) policy is used.
+	ECBP1100Policy *ctypes.ArtificialFinalityPolicy `json:"ecbp1100Policy,omitempty"`
+
 	// EIP-2315: Simple Subroutines
 	// https://eips.ethereum.org/EIPS/eip-2315
 	EIP2315FBlock *big.Int `json:"eip2315FBlock,omitempty"`
@@ -194,6 +200,25 @@ type CoreGethChainConfig struct {
 	// TODO: Document me.
 	EIP2930FBlock *big.Int `json:"eip2930FBlock,omitempty"`
 
+	// EIP-1559: Fee market change for ETH 1.0 chain
+	// https://eips.ethereum.org/EIPS/eip-1559
+	EIP1559FBlock *big.Int `json:"eip1559FBlock,omitempty"`
+	// EIP-1559 base fee parameters. When nil, the EIP-specified defaults are used.
+	EIP1559ElasticityMultiplier     *big.Int `json:"eip1559ElasticityMultiplier,omitempty"`
+	EIP1559BaseFeeChangeDenominator *big.Int `json:"eip1559BaseFeeChangeDenominator,omitempty"`
+
+	// EIP-3198: BASEFEE opcode
+	// https://eips.ethereum.org/EIPS/eip-3198
+	EIP3198FBlock *big.Int `json:"eip3198FBlock,omitempty"`
+
+	// EIP-3529: Reduction in refunds
+	// https://eips.ethereum.org/EIPS/eip-3529
+	EIP3529FBlock *big.Int `json:"eip3529FBlock,omitempty"`
+
+	// EIP-3541: Reject new contracts starting with the 0xEF byte
+	// https://eips.ethereum.org/EIPS/eip-3541
+	EIP3541FBlock *big.Int `json:"eip3541FBlock,omitempty"`
+
 	DisposalBlock *big.Int `json:"disposalBlock,omitempty"` // Bomb disposal HF block
 
 	// Various consensus engines
@@ -212,6 +237,24 @@ type CoreGethChainConfig struct {
 	RequireBlockHashes map[uint64]common.Hash `json:"requireBlockHashes"`
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface, rejecting EIP-1559
+// parameters the base fee calculation would divide by zero with.
+func (c *CoreGethChainConfig) UnmarshalJSON(input []byte) error {
+	type chainConfig CoreGethChainConfig
+	var dec chainConfig
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.EIP1559ElasticityMultiplier != nil && dec.EIP1559ElasticityMultiplier.Sign() == 0 {
+		return errors.New("eip1559ElasticityMultiplier must not be zero")
+	}
+	if dec.EIP1559BaseFeeChangeDenominator != nil && dec.EIP1559BaseFeeChangeDenominator.Sign() == 0 {
+		return errors.New("eip1559BaseFeeChangeDenominator must not be zero")
+	}
+	*c = CoreGethChainConfig(dec)
+	return nil
+}
+
 // String implements the fmt.Stringer interface.
 func (c *CoreGethChainConfig) String() string {
 	var engine interface{}
@@ -407,6 +407,15 @@ func (c *CoreGethChainConfig) SetECBP1100Transition(n *uint64) error {
 	return nil
 }
 
+func (c *CoreGethChainConfig) GetECBP1100Policy() *ctypes.ArtificialFinalityPolicy {
+	return c.ECBP1100Policy
+}
+
+func (c *CoreGethChainConfig) SetECBP1100Policy(p *ctypes.ArtificialFinalityPolicy) error {
+	c.ECBP1100Policy = p
+	return nil
+}
+
 func (c *CoreGethChainConfig) GetEIP2315Transition() *uint64 {
 	return bigNewU64(c.EIP2315FBlock)
 }
@@ -452,6 +461,80 @@ func (c *CoreGethChainConfig) SetEIP2718Transition(n *uint64) error {
 	return nil
 }
 
+func (c *CoreGethChainConfig) GetEIP1559Transition() *uint64 {
+	return bigNewU64(c.EIP1559FBlock)
+}
+
+func (c *CoreGethChainConfig) SetEIP1559Transition(n *uint64) error {
+	c.EIP1559FBlock = setBig(c.EIP1559FBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetEIP1559ElasticityMultiplier() *uint64 {
+	if c.EIP1559ElasticityMultiplier == nil || c.EIP1559ElasticityMultiplier.Sign() == 0 {
+		return newU64(vars.DefaultElasticityMultiplier)
+	}
+	return bigNewU64(c.EIP1559ElasticityMultiplier)
+}
+
+func (c *CoreGethChainConfig) SetEIP1559ElasticityMultiplier(n *uint64) error {
+	if n != nil && *n == 0 {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	// Default values are not stored.
+	if n != nil && *n == vars.DefaultElasticityMultiplier {
+		n = nil
+	}
+	c.EIP1559ElasticityMultiplier = setBig(c.EIP1559ElasticityMultiplier, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetEIP1559BaseFeeChangeDenominator() *uint64 {
+	if c.EIP1559BaseFeeChangeDenominator == nil || c.EIP1559BaseFeeChangeDenominator.Sign() == 0 {
+		return newU64(vars.DefaultBaseFeeChangeDenominator)
+	}
+	return bigNewU64(c.EIP1559BaseFeeChangeDenominator)
+}
+
+func (c *CoreGethChainConfig) SetEIP1559BaseFeeChangeDenominator(n *uint64) error {
+	if n != nil && *n == 0 {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	// Default values are not stored.
+	if n != nil && *n == vars.DefaultBaseFeeChangeDenominator {
+		n = nil
+	}
+	c.EIP1559BaseFeeChangeDenominator = setBig(c.EIP1559BaseFeeChangeDenominator, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetEIP3198Transition() *uint64 {
+	return bigNewU64(c.EIP3198FBlock)
+}
+
+func (c *CoreGethChainConfig) SetEIP3198Transition(n *uint64) error {
+	c.EIP3198FBlock = setBig(c.EIP3198FBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetEIP3529Transition() *uint64 {
+	return bigNewU64(c.EIP3529FBlock)
+}
+
+func (c *CoreGethChainConfig) SetEIP3529Transition(n *uint64) error {
+	c.EIP3529FBlock = setBig(c.EIP3529FBlock, n)
+	return nil
+}
+
+func (c *CoreGethChainConfig) GetEIP3541Transition() *uint64 {
+	return bigNewU64(c.EIP3541FBlock)
+}
+
+func (c *CoreGethChainConfig) SetEIP3541Transition(n *uint64) error {
+	c.EIP3541FBlock = setBig(c.EIP3541FBlock, n)
+	return nil
+}
+
 func (c *CoreGethChainConfig) IsEnabled(fn func() *uint64, n *big.Int) bool {
 	f := fn()
 	if f == nil || n == nil {
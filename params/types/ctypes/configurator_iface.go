@@ -134,6 +134,10 @@ type ProtocolSpecifier interface {
 	SetEIP2537Transition(n *uint64) error
 	GetECBP1100Transition() *uint64
 	SetECBP1100Transition(n *uint64) error
+	// GetECBP1100Policy returns the artificial finality policy applied from the ECBP1100 transition.
+	// A nil value defaults to the ECBP1100 (MESS) policy.
+	GetECBP1100Policy() *ArtificialFinalityPolicy
+	SetECBP1100Policy(p *ArtificialFinalityPolicy) error
 	GetEIP2315Transition() *uint64
 	SetEIP2315Transition(n *uint64) error
 
@@ -152,6 +156,29 @@ type ProtocolSpecifier interface {
 	// Typed transaction envelope
 	GetEIP2718Transition() *uint64
 	SetEIP2718Transition(n *uint64) error
+
+	// Fee market change: base fee, burn, and dynamic fee transactions
+	GetEIP1559Transition() *uint64
+	SetEIP1559Transition(n *uint64) error
+
+	// EIP1559 base fee parameters.
+	// Getters should return the canonical default values when the fields are not set.
+	GetEIP1559ElasticityMultiplier() *uint64
+	SetEIP1559ElasticityMultiplier(n *uint64) error
+	GetEIP1559BaseFeeChangeDenominator() *uint64
+	SetEIP1559BaseFeeChangeDenominator(n *uint64) error
+
+	// BASEFEE opcode
+	GetEIP3198Transition() *uint64
+	SetEIP3198Transition(n *uint64) error
+
+	// Reduction in refunds
+	GetEIP3529Transition() *uint64
+	SetEIP3529Transition(n *uint64) error
+
+	// Reject new contracts starting with the 0xEF byte
+	GetEIP3541Transition() *uint64
+	SetEIP3541Transition(n *uint64) error
 }
 
 type Forker interface {
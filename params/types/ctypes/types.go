@@ -304,6 +304,44 @@ func (c ConsensusEngineT) IsUnknown() bool {
 	return c == ConsensusEngineT_Unknown
 }
 
+// ArtificialFinalityPolicyT names a policy used to arbitrate chain reorganizations
+// when artificial finality features are activated.
+type ArtificialFinalityPolicyT string
+
+const (
+	// ArtificialFinalityPolicyT_MESS is the ECBP1100 (MESS) polynomial antigravity policy.
+	ArtificialFinalityPolicyT_MESS ArtificialFinalityPolicyT = "mess"
+	// ArtificialFinalityPolicyT_Checkpoint disallows reorganizations deeper than a fixed number of blocks.
+	ArtificialFinalityPolicyT_Checkpoint ArtificialFinalityPolicyT = "checkpoint"
+	// ArtificialFinalityPolicyT_MaxAge disallows reorganizations of chain segments older than a fixed number of seconds.
+	ArtificialFinalityPolicyT_MaxAge ArtificialFinalityPolicyT = "maxage"
+)
+
+// ArtificialFinalityPolicy configures the policy consulted on chain reorganizations
+// once artificial finality is activated (by the ECBP1100 transition).
+type ArtificialFinalityPolicy struct {
+	Type ArtificialFinalityPolicyT `json:"type"`
+
+	// Depth is the number of blocks below the current head at which the checkpoint policy
+	// disallows reorganizations.
+	Depth uint64 `json:"depth,omitempty"`
+
+	// MaxAge is the number of seconds of current chain segment span at which the maxage policy
+	// disallows reorganizations.
+	MaxAge uint64 `json:"maxAge,omitempty"`
+}
+
+// String implements the fmt.Stringer interface.
+func (p ArtificialFinalityPolicy) String() string {
+	switch p.Type {
+	case ArtificialFinalityPolicyT_Checkpoint:
+		return fmt.Sprintf("%s(depth=%d)", p.Type, p.Depth)
+	case ArtificialFinalityPolicyT_MaxAge:
+		return fmt.Sprintf("%s(maxAge=%ds)", p.Type, p.MaxAge)
+	}
+	return string(p.Type)
+}
+
 type BlockSealingT int
 
 const (
@@ -52,6 +52,7 @@ type Genesis struct {
 	Number     uint64      `json:"number"`
 	GasUsed    uint64      `json:"gasUsed"`
 	ParentHash common.Hash `json:"parentHash"`
+	BaseFee    *big.Int    `json:"baseFeePerGas"`
 }
 
 func (g *Genesis) ForEachAccount(fn func(address common.Address, bal *big.Int, nonce uint64, code []byte, storage map[common.Hash]common.Hash) error) error {
@@ -115,6 +116,7 @@ type genesisSpecMarshaling struct {
 	GasUsed    math.HexOrDecimal64
 	Number     math.HexOrDecimal64
 	Difficulty *math.HexOrDecimal256
+	BaseFee    *math.HexOrDecimal256
 	Alloc      map[common.UnprefixedAddress]GenesisAccount
 }
 
@@ -592,6 +594,54 @@ func (g *Genesis) SetEIP2718Transition(n *uint64) error {
 	return g.Config.SetEIP2718Transition(n)
 }
 
+func (g *Genesis) GetEIP1559Transition() *uint64 {
+	return g.Config.GetEIP1559Transition()
+}
+
+func (g *Genesis) SetEIP1559Transition(n *uint64) error {
+	return g.Config.SetEIP1559Transition(n)
+}
+
+func (g *Genesis) GetEIP1559ElasticityMultiplier() *uint64 {
+	return g.Config.GetEIP1559ElasticityMultiplier()
+}
+
+func (g *Genesis) SetEIP1559ElasticityMultiplier(n *uint64) error {
+	return g.Config.SetEIP1559ElasticityMultiplier(n)
+}
+
+func (g *Genesis) GetEIP1559BaseFeeChangeDenominator() *uint64 {
+	return g.Config.GetEIP1559BaseFeeChangeDenominator()
+}
+
+func (g *Genesis) SetEIP1559BaseFeeChangeDenominator(n *uint64) error {
+	return g.Config.SetEIP1559BaseFeeChangeDenominator(n)
+}
+
+func (g *Genesis) GetEIP3198Transition() *uint64 {
+	return g.Config.GetEIP3198Transition()
+}
+
+func (g *Genesis) SetEIP3198Transition(n *uint64) error {
+	return g.Config.SetEIP3198Transition(n)
+}
+
+func (g *Genesis) GetEIP3529Transition() *uint64 {
+	return g.Config.GetEIP3529Transition()
+}
+
+func (g *Genesis) SetEIP3529Transition(n *uint64) error {
+	return g.Config.SetEIP3529Transition(n)
+}
+
+func (g *Genesis) GetEIP3541Transition() *uint64 {
+	return g.Config.GetEIP3541Transition()
+}
+
+func (g *Genesis) SetEIP3541Transition(n *uint64) error {
+	return g.Config.SetEIP3541Transition(n)
+}
+
 func (g *Genesis) GetECBP1100Transition() *uint64 {
 	return g.Config.GetECBP1100Transition()
 }
@@ -600,6 +650,14 @@ func (g *Genesis) SetECBP1100Transition(n *uint64) error {
 	return g.Config.SetECBP1100Transition(n)
 }
 
+func (g *Genesis) GetECBP1100Policy() *ctypes.ArtificialFinalityPolicy {
+	return g.Config.GetECBP1100Policy()
+}
+
+func (g *Genesis) SetECBP1100Policy(p *ctypes.ArtificialFinalityPolicy) error {
+	return g.Config.SetECBP1100Policy(p)
+}
+
 func (g *Genesis) IsEnabled(fn func() *uint64, n *big.Int) bool {
 	return g.Config.IsEnabled(fn, n)
 }
@@ -58,6 +58,7 @@ type ChainConfig struct {
 	MuirGlacierBlock *big.Int `json:"muirGlacierBlock,omitempty"` // Eip-2384 (bomb delay) switch block (nil = no fork, 0 = already activated)
 
 	BerlinBlock *big.Int `json:"berlinBlock,omitempty"` // Berlin switch block
+	LondonBlock *big.Int `json:"londonBlock,omitempty"` // London switch block
 	YoloV3Block *big.Int `json:"yoloV3Block,omitempty"` // YOLO v3 (Ephemeral testnet)
 
 	EWASMBlock    *big.Int `json:"ewasmBlock,omitempty"`    // EWASM switch block (nil = no fork, 0 = already activated)
@@ -77,6 +78,7 @@ type ChainConfig struct {
 
 	// Cache types for use with testing, but will not show up in config API.
 	ecbp1100Transition *big.Int
+	ecbp1100Policy     *ctypes.ArtificialFinalityPolicy
 }
 
 // String implements the fmt.Stringer interface.
@@ -90,7 +92,7 @@ func (c *ChainConfig) String() string {
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v DAOSupport: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v ConstantinopleFix: %v Istanbul: %v MuirGlacier: %v Berlin: %v Engine: %v}",
+	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v DAOSupport: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v ConstantinopleFix: %v Istanbul: %v MuirGlacier: %v Berlin: %v London: %v Engine: %v}",
 		c.ChainID,
 		c.HomesteadBlock,
 		c.DAOForkBlock,
@@ -104,6 +106,7 @@ func (c *ChainConfig) String() string {
 		c.IstanbulBlock,
 		c.MuirGlacierBlock,
 		c.BerlinBlock,
+		c.LondonBlock,
 		engine,
 	)
 }
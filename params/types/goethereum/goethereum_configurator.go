@@ -425,6 +425,15 @@ func (c *ChainConfig) SetECBP1100Transition(n *uint64) error {
 	return nil
 }
 
+func (c *ChainConfig) GetECBP1100Policy() *ctypes.ArtificialFinalityPolicy {
+	return c.ecbp1100Policy
+}
+
+func (c *ChainConfig) SetECBP1100Policy(p *ctypes.ArtificialFinalityPolicy) error {
+	c.ecbp1100Policy = p
+	return nil
+}
+
 func (c *ChainConfig) GetEIP2315Transition() *uint64 {
 	return bigNewU64(c.YoloV3Block)
 }
@@ -470,6 +479,64 @@ func (c *ChainConfig) SetEIP2718Transition(n *uint64) error {
 	return nil
 }
 
+func (c *ChainConfig) GetEIP1559Transition() *uint64 {
+	return bigNewU64(c.LondonBlock)
+}
+
+func (c *ChainConfig) SetEIP1559Transition(n *uint64) error {
+	c.LondonBlock = setBig(c.LondonBlock, n)
+	return nil
+}
+
+func (c *ChainConfig) GetEIP1559ElasticityMultiplier() *uint64 {
+	return newU64(vars.DefaultElasticityMultiplier)
+}
+
+func (c *ChainConfig) SetEIP1559ElasticityMultiplier(n *uint64) error {
+	if n == nil || *n == vars.DefaultElasticityMultiplier {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetEIP1559BaseFeeChangeDenominator() *uint64 {
+	return newU64(vars.DefaultBaseFeeChangeDenominator)
+}
+
+func (c *ChainConfig) SetEIP1559BaseFeeChangeDenominator(n *uint64) error {
+	if n == nil || *n == vars.DefaultBaseFeeChangeDenominator {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
+func (c *ChainConfig) GetEIP3198Transition() *uint64 {
+	return bigNewU64(c.LondonBlock)
+}
+
+func (c *ChainConfig) SetEIP3198Transition(n *uint64) error {
+	c.LondonBlock = setBig(c.LondonBlock, n)
+	return nil
+}
+
+func (c *ChainConfig) GetEIP3529Transition() *uint64 {
+	return bigNewU64(c.LondonBlock)
+}
+
+func (c *ChainConfig) SetEIP3529Transition(n *uint64) error {
+	c.LondonBlock = setBig(c.LondonBlock, n)
+	return nil
+}
+
+func (c *ChainConfig) GetEIP3541Transition() *uint64 {
+	return bigNewU64(c.LondonBlock)
+}
+
+func (c *ChainConfig) SetEIP3541Transition(n *uint64) error {
+	c.LondonBlock = setBig(c.LondonBlock, n)
+	return nil
+}
+
 func (c *ChainConfig) IsEnabled(fn func() *uint64, n *big.Int) bool {
 	f := fn()
 	if f == nil || n == nil {
@@ -0,0 +1,30 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package nethermind
+
+import (
+	"github.com/ethereum/go-ethereum/params/types/parity"
+)
+
+// NethermindChainSpec is the chain specification format used by Nethermind.
+// Nethermind reads the chain specification schema established by Parity (OpenEthereum),
+// so the type reuses the Parity implementation of the Configurator interface.
+// The Parity types also accept the conventions of Nethermind chainspecs, such as
+// decimal transitions and balances, and 0x-prefixed account addresses.
+type NethermindChainSpec struct {
+	parity.ParityChainSpec
+}
@@ -19,6 +19,7 @@ package parity
 import (
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"math/big"
 	"reflect"
 	"sort"
@@ -122,6 +123,13 @@ type ParityChainSpec struct {
 		EIP2718Transition         *ParityU64 `json:"eip2718Transition,omitempty"`  // FIXME, when and if i'm implemented in Parity
 		ECIP1080Transition        *ParityU64 `json:"ecip1080Transition,omitempty"` // FIXME, when and if i'm implemented in Parity
 
+		EIP1559Transition                  *ParityU64 `json:"eip1559Transition,omitempty"`
+		EIP1559ElasticityMultiplier        *ParityU64 `json:"eip1559ElasticityMultiplier,omitempty"`
+		EIP1559BaseFeeMaxChangeDenominator *ParityU64 `json:"eip1559BaseFeeMaxChangeDenominator,omitempty"`
+		EIP3198Transition                  *ParityU64 `json:"eip3198Transition,omitempty"`
+		EIP3529Transition                  *ParityU64 `json:"eip3529Transition,omitempty"`
+		EIP3541Transition                  *ParityU64 `json:"eip3541Transition,omitempty"`
+
 		// supportedProtocolVersions is left here as a caching field only.
 		// I don't think this feature is supported by Parity, but
 		// this value allows the configurator converter to gracefully handle test cases for Parity
@@ -152,6 +160,24 @@ type ParityChainSpec struct {
 	Accounts map[common.UnprefixedAddress]*ParityChainSpecAccount `json:"accounts"`
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface, rejecting EIP-1559
+// parameters the base fee calculation would divide by zero with.
+func (c *ParityChainSpec) UnmarshalJSON(input []byte) error {
+	type chainSpec ParityChainSpec
+	var dec chainSpec
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if p := dec.Params.EIP1559ElasticityMultiplier; p != nil && *p == 0 {
+		return errors.New("eip1559ElasticityMultiplier must not be zero")
+	}
+	if p := dec.Params.EIP1559BaseFeeMaxChangeDenominator; p != nil && *p == 0 {
+		return errors.New("eip1559BaseFeeMaxChangeDenominator must not be zero")
+	}
+	*c = ParityChainSpec(dec)
+	return nil
+}
+
 func (c *ParityChainSpec) String() string {
 	cc := &ParityChainSpec{}
 	*cc = *c
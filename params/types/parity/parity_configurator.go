@@ -647,6 +647,17 @@ func (spec *ParityChainSpec) SetECBP1100Transition(n *uint64) error {
 	return ctypes.ErrUnsupportedConfigFatal
 }
 
+func (spec *ParityChainSpec) GetECBP1100Policy() *ctypes.ArtificialFinalityPolicy {
+	return nil
+}
+
+func (spec *ParityChainSpec) SetECBP1100Policy(p *ctypes.ArtificialFinalityPolicy) error {
+	if p == nil {
+		return nil
+	}
+	return ctypes.ErrUnsupportedConfigFatal
+}
+
 func (spec *ParityChainSpec) IsEnabled(fn func() *uint64, n *big.Int) bool {
 	f := fn()
 	if f == nil || n == nil {
@@ -1058,6 +1069,82 @@ func (spec *ParityChainSpec) SetEIP2718Transition(n *uint64) error {
 	return nil
 }
 
+func (spec *ParityChainSpec) GetEIP1559Transition() *uint64 {
+	return spec.Params.EIP1559Transition.Uint64P()
+}
+
+func (spec *ParityChainSpec) SetEIP1559Transition(n *uint64) error {
+	spec.Params.EIP1559Transition = new(ParityU64).SetUint64(n)
+	return nil
+}
+
+func (spec *ParityChainSpec) GetEIP1559ElasticityMultiplier() *uint64 {
+	if spec.Params.EIP1559ElasticityMultiplier == nil || *spec.Params.EIP1559ElasticityMultiplier == 0 {
+		n := vars.DefaultElasticityMultiplier
+		return &n
+	}
+	return spec.Params.EIP1559ElasticityMultiplier.Uint64P()
+}
+
+func (spec *ParityChainSpec) SetEIP1559ElasticityMultiplier(n *uint64) error {
+	if n != nil && *n == 0 {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	if n != nil && *n == vars.DefaultElasticityMultiplier {
+		// Default values are not stored.
+		n = nil
+	}
+	spec.Params.EIP1559ElasticityMultiplier = new(ParityU64).SetUint64(n)
+	return nil
+}
+
+func (spec *ParityChainSpec) GetEIP1559BaseFeeChangeDenominator() *uint64 {
+	if spec.Params.EIP1559BaseFeeMaxChangeDenominator == nil || *spec.Params.EIP1559BaseFeeMaxChangeDenominator == 0 {
+		n := vars.DefaultBaseFeeChangeDenominator
+		return &n
+	}
+	return spec.Params.EIP1559BaseFeeMaxChangeDenominator.Uint64P()
+}
+
+func (spec *ParityChainSpec) SetEIP1559BaseFeeChangeDenominator(n *uint64) error {
+	if n != nil && *n == 0 {
+		return ctypes.ErrUnsupportedConfigFatal
+	}
+	if n != nil && *n == vars.DefaultBaseFeeChangeDenominator {
+		// Default values are not stored.
+		n = nil
+	}
+	spec.Params.EIP1559BaseFeeMaxChangeDenominator = new(ParityU64).SetUint64(n)
+	return nil
+}
+
+func (spec *ParityChainSpec) GetEIP3198Transition() *uint64 {
+	return spec.Params.EIP3198Transition.Uint64P()
+}
+
+func (spec *ParityChainSpec) SetEIP3198Transition(n *uint64) error {
+	spec.Params.EIP3198Transition = new(ParityU64).SetUint64(n)
+	return nil
+}
+
+func (spec *ParityChainSpec) GetEIP3529Transition() *uint64 {
+	return spec.Params.EIP3529Transition.Uint64P()
+}
+
+func (spec *ParityChainSpec) SetEIP3529Transition(n *uint64) error {
+	spec.Params.EIP3529Transition = new(ParityU64).SetUint64(n)
+	return nil
+}
+
+func (spec *ParityChainSpec) GetEIP3541Transition() *uint64 {
+	return spec.Params.EIP3541Transition.Uint64P()
+}
+
+func (spec *ParityChainSpec) SetEIP3541Transition(n *uint64) error {
+	spec.Params.EIP3541Transition = new(ParityU64).SetUint64(n)
+	return nil
+}
+
 func (spec *ParityChainSpec) GetEthashDifficultyBombDelaySchedule() ctypes.Uint64BigMapEncodesHex {
 	if spec.GetConsensusEngineType() != ctypes.ConsensusEngineT_Ethash {
 		return nil
@@ -80,6 +80,15 @@ var (
 	SstoreResetGasEIP2200             uint64 = 5000  // Once per SSTORE operation from clean non-zero to something else
 	SstoreClearsScheduleRefundEIP2200 uint64 = 15000 // Once per SSTORE operation for clearing an originally existing storage slot
 
+	// In EIP-3529: SSTORE_CLEARS_SCHEDULE is defined as SSTORE_RESET_GAS + ACCESS_LIST_STORAGE_KEY_COST
+	// Which becomes: 5000 - 2100 + 1900 = 4800
+	SstoreClearsScheduleRefundEIP3529 uint64 = 4800
+
+	// The Refund Quotient is the cap on how much of the used gas can be refunded. Prior to
+	// EIP-3529, refunds were capped to gasUsed / RefundQuotient, EIP-3529 lowered it.
+	RefundQuotient        uint64 = 2
+	RefundQuotientEIP3529 uint64 = 5
+
 	JumpdestGas   uint64 = 1     // Once per JUMPDEST operation.
 	EpochDuration uint64 = 30000 // Duration between proof-of-work epochs.
 
@@ -101,6 +110,11 @@ var (
 	TxAccessListAddressGas    uint64 = 2400 // Per address specified in EIP 2930 access list
 	TxAccessListStorageKeyGas uint64 = 1900 // Per storage key specified in EIP 2930 access list
 
+	// EIP1559 fee market defaults, used unless a chain configuration defines its own.
+	DefaultBaseFeeChangeDenominator uint64 = 8          // Bounds the amount the base fee can change between blocks.
+	DefaultElasticityMultiplier     uint64 = 2          // Bounds the maximum gas limit an EIP-1559 block may have.
+	InitialBaseFee                  uint64 = 1000000000 // Initial base fee for EIP-1559 blocks.
+
 	// These have been changed during the course of the chain
 	CallGasFrontier              uint64 = 40  // Once per CALL operation & message call transaction.
 	CallGasEIP150                uint64 = 700 // Static portion of gas for CALL-derivates after EIP 150 (Tangerine)
@@ -0,0 +1,421 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package evmc implements a differential fuzzer, executing random contracts with
+// both the Go interpreter and an EVMC VM and comparing the outcomes.
+package evmc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+)
+
+const (
+	// evmcEnv is the environment variable holding the EVMC configuration of the
+	// VM to compare with, e.g. /path/to/libevmone.so or /path/to/libevmone.so,O=0.
+	evmcEnv = "EVMC_EVM"
+
+	// opcodesEnv is the environment variable holding the comma separated names
+	// of the opcodes implemented by the VM, e.g. STOP,ADD,PUSH1,RETURN. If set,
+	// the contracts are generated from these opcodes only.
+	opcodesEnv = "EVMC_OPCODES"
+
+	// forkEnv is the environment variable holding the name of the latest fork
+	// supported by the VM, e.g. Byzantium. If set, later forks aren't scheduled.
+	forkEnv = "EVMC_FORK"
+)
+
+// setter schedules a transition of a chain config.
+type setter func(ctypes.ChainConfigurator, *uint64) error
+
+// hardfork is a set of transitions activated together.
+type hardfork struct {
+	name    string
+	setters []setter
+}
+
+// forks are the forks known to EVMC, in activation order. Berlin is not supported
+// by EVMC v7, so it is left out.
+var forks = []hardfork{
+	{"Homestead", []setter{
+		ctypes.ChainConfigurator.SetEIP2Transition,
+		ctypes.ChainConfigurator.SetEIP7Transition,
+	}},
+	{"TangerineWhistle", []setter{
+		ctypes.ChainConfigurator.SetEIP150Transition,
+	}},
+	{"SpuriousDragon", []setter{
+		ctypes.ChainConfigurator.SetEIP155Transition,
+		ctypes.ChainConfigurator.SetEIP160Transition,
+		ctypes.ChainConfigurator.SetEIP161abcTransition,
+		ctypes.ChainConfigurator.SetEIP161dTransition,
+		ctypes.ChainConfigurator.SetEIP170Transition,
+	}},
+	{"Byzantium", []setter{
+		ctypes.ChainConfigurator.SetEIP140Transition,
+		ctypes.ChainConfigurator.SetEIP198Transition,
+		ctypes.ChainConfigurator.SetEIP211Transition,
+		ctypes.ChainConfigurator.SetEIP212Transition,
+		ctypes.ChainConfigurator.SetEIP213Transition,
+		ctypes.ChainConfigurator.SetEIP214Transition,
+		ctypes.ChainConfigurator.SetEIP658Transition,
+	}},
+	{"Constantinople", []setter{
+		ctypes.ChainConfigurator.SetEIP145Transition,
+		ctypes.ChainConfigurator.SetEIP1014Transition,
+		ctypes.ChainConfigurator.SetEIP1052Transition,
+		ctypes.ChainConfigurator.SetEIP1283Transition,
+	}},
+	{"Petersburg", []setter{
+		ctypes.ChainConfigurator.SetEIP1283DisableTransition,
+	}},
+	{"Istanbul", []setter{
+		ctypes.ChainConfigurator.SetEIP152Transition,
+		ctypes.ChainConfigurator.SetEIP1108Transition,
+		ctypes.ChainConfigurator.SetEIP1344Transition,
+		ctypes.ChainConfigurator.SetEIP1884Transition,
+		ctypes.ChainConfigurator.SetEIP2028Transition,
+		ctypes.ChainConfigurator.SetEIP2200Transition,
+	}},
+}
+
+// capabilities restricts the testcases to the features implemented by the VM.
+type capabilities struct {
+	opcodes []vm.OpCode // Opcodes the code is generated from, all if empty
+	forks   int         // Number of forks which may be scheduled
+}
+
+// parseCapabilities parses the capabilities of the VM from the opcode list and
+// the name of the latest supported fork, both optional.
+func parseCapabilities(opcodes, latest string) (*capabilities, error) {
+	caps := &capabilities{forks: len(forks)}
+	if opcodes != "" {
+		for _, name := range strings.Split(opcodes, ",") {
+			name = strings.ToUpper(strings.TrimSpace(name))
+			op := vm.StringToOp(name)
+			if op.String() != name {
+				return nil, fmt.Errorf("unknown opcode %q", name)
+			}
+			caps.opcodes = append(caps.opcodes, op)
+		}
+	}
+	if latest != "" {
+		caps.forks = -1
+		for i, fork := range forks {
+			if strings.EqualFold(fork.name, latest) {
+				caps.forks = i + 1
+			}
+		}
+		if caps.forks < 0 {
+			return nil, fmt.Errorf("unknown fork %q", latest)
+		}
+	}
+	return caps, nil
+}
+
+// filterCode rewrites the opcodes of the code which the VM doesn't implement
+// into implemented ones, leaving the push data untouched.
+func (caps *capabilities) filterCode(code []byte) []byte {
+	if len(caps.opcodes) == 0 {
+		return code
+	}
+	allowed := make(map[vm.OpCode]bool)
+	for _, op := range caps.opcodes {
+		allowed[op] = true
+	}
+	for pc := 0; pc < len(code); pc++ {
+		op := vm.OpCode(code[pc])
+		if !allowed[op] {
+			op = caps.opcodes[int(op)%len(caps.opcodes)]
+			code[pc] = byte(op)
+		}
+		if op.IsPush() {
+			pc += int(op - vm.PUSH1 + 1)
+		}
+	}
+	return code
+}
+
+var (
+	// accounts are the addresses of the contracts in the pre-state, the first
+	// one being the called contract
+	accounts = []common.Address{
+		common.BytesToAddress([]byte{0xc0}),
+		common.BytesToAddress([]byte{0xc1}),
+		common.BytesToAddress([]byte{0xc2}),
+		common.BytesToAddress([]byte{0xc3}),
+	}
+	origin   = common.BytesToAddress([]byte("origin"))
+	coinbase = common.BytesToAddress([]byte("coinbase"))
+
+	initOnce sync.Once
+	vmCaps   *capabilities
+)
+
+type fuzzer struct {
+	input     io.Reader
+	exhausted bool
+	caps      *capabilities
+}
+
+func (f *fuzzer) read(size int) []byte {
+	out := make([]byte, size)
+	if _, err := f.input.Read(out); err != nil {
+		f.exhausted = true
+	}
+	return out
+}
+
+func (f *fuzzer) readSlice(min, max int) []byte {
+	var a uint16
+	binary.Read(f.input, binary.LittleEndian, &a)
+	size := min + int(a)%(max-min)
+	out := make([]byte, size)
+	if _, err := f.input.Read(out); err != nil {
+		f.exhausted = true
+	}
+	return out
+}
+
+func (f *fuzzer) readUint64(min, max uint64) uint64 {
+	if min == max {
+		return min
+	}
+	var a uint64
+	if err := binary.Read(f.input, binary.LittleEndian, &a); err != nil {
+		f.exhausted = true
+	}
+	a = min + a%(max-min)
+	return a
+}
+
+func (f *fuzzer) readBool() bool {
+	return f.read(1)[0]&0x1 == 0
+}
+
+// testcase is a call executed on top of a pre-state.
+type testcase struct {
+	config ctypes.ChainConfigurator
+	number uint64
+	alloc  map[common.Address]account
+	input  []byte
+	value  uint64
+	gas    uint64
+}
+
+type account struct {
+	balance uint64
+	nonce   uint64
+	code    []byte
+	storage map[common.Hash]common.Hash
+}
+
+// outcome is the result of a call, as compared between the VMs.
+type outcome struct {
+	status  string // success, revert or failure
+	ret     []byte
+	gasLeft uint64
+	logs    []*types.Log
+	root    common.Hash
+}
+
+// The function must return
+// 1 if the fuzzer should increase priority of the
+//    given input during subsequent fuzzing (for example, the input is lexically
+//    correct and was parsed successfully);
+// -1 if the input must not be added to corpus even if gives new coverage; and
+// 0  otherwise
+// other values are reserved for future use.
+func Fuzz(data []byte) int {
+	initEVMC()
+	f := fuzzer{
+		input:     bytes.NewReader(data),
+		exhausted: false,
+		caps:      vmCaps,
+	}
+	return f.fuzz()
+}
+
+// initEVMC loads the configured EVMC VM and its capabilities.
+func initEVMC() {
+	initOnce.Do(func() {
+		config := os.Getenv(evmcEnv)
+		if config == "" {
+			panic(fmt.Sprintf("EVMC VM not configured, set %s=/path/to/vm.so", evmcEnv))
+		}
+		caps, err := parseCapabilities(os.Getenv(opcodesEnv), os.Getenv(forkEnv))
+		if err != nil {
+			panic(fmt.Sprintf("invalid EVMC VM capabilities: %v", err))
+		}
+		vm.InitEVMCEVM(config)
+		vmCaps = caps
+	})
+}
+
+func (f *fuzzer) fuzz() int {
+	test := f.readTestcase()
+	if f.exhausted {
+		return 0
+	}
+	want := test.execute(vm.Config{})
+	have := test.execute(vm.Config{EVMInterpreter: os.Getenv(evmcEnv)})
+
+	for i, check := range []struct {
+		name       string
+		want, have interface{}
+	}{
+		{"status", want.status, have.status},
+		{"return data", want.ret, have.ret},
+		{"gas left", want.gasLeft, have.gasLeft},
+		{"logs", want.logs, have.logs},
+		{"post-state root", want.root, have.root},
+	} {
+		if !reflect.DeepEqual(check.want, check.have) {
+			panic(fmt.Sprintf("case %d: %s mismatch\ngo  : %v\nevmc: %v\n%v", i, check.name, check.want, check.have, test))
+		}
+	}
+	if want.status != "success" {
+		return 0
+	}
+	return 1
+}
+
+// readTestcase generates a chain config, a pre-state and a call from the input,
+// within the capabilities of the VM.
+func (f *fuzzer) readTestcase() *testcase {
+	caps := f.caps
+	if caps == nil {
+		caps = &capabilities{forks: len(forks)}
+	}
+	test := &testcase{
+		config: &coregeth.CoreGethChainConfig{
+			NetworkID: 1,
+			ChainID:   big.NewInt(1),
+			Ethash:    new(ctypes.EthashConfig),
+		},
+		number: f.readUint64(0, 16),
+		alloc:  make(map[common.Address]account),
+	}
+	// Schedule a prefix of the forks, each at or after the previous one
+	var block uint64
+	for _, fork := range forks[:caps.forks] {
+		if !f.readBool() {
+			break
+		}
+		block += f.readUint64(0, 4)
+		for _, set := range fork.setters {
+			n := block
+			set(test.config, &n)
+		}
+	}
+	for _, addr := range accounts {
+		acc := account{
+			balance: f.readUint64(0, 1<<32),
+			nonce:   f.readUint64(0, 4),
+			code:    caps.filterCode(f.readSlice(0, 512)),
+			storage: make(map[common.Hash]common.Hash),
+		}
+		for i := f.readUint64(0, 4); i > 0; i-- {
+			acc.storage[common.BigToHash(new(big.Int).SetUint64(f.readUint64(0, 8)))] = common.BytesToHash(f.read(32))
+		}
+		test.alloc[addr] = acc
+	}
+	test.input = f.readSlice(0, 128)
+	test.value = f.readUint64(0, 1<<16)
+	test.gas = f.readUint64(1, 1<<22)
+	return test
+}
+
+// execute runs the call on a fresh pre-state with the given VM.
+func (t *testcase) execute(vmConfig vm.Config) *outcome {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetBalance(origin, new(big.Int).SetUint64(1<<32))
+	for addr, acc := range t.alloc {
+		statedb.SetBalance(addr, new(big.Int).SetUint64(acc.balance))
+		statedb.SetNonce(addr, acc.nonce)
+		statedb.SetCode(addr, acc.code)
+		for key, value := range acc.storage {
+			statedb.SetState(addr, key, value)
+		}
+	}
+	statedb.Finalise(true)
+
+	number := new(big.Int).SetUint64(t.number)
+	ret, gasLeft, err := runtime.Call(accounts[0], t.input, &runtime.Config{
+		ChainConfig: t.config,
+		Difficulty:  big.NewInt(0x20000),
+		Origin:      origin,
+		Coinbase:    coinbase,
+		BlockNumber: number,
+		Time:        big.NewInt(1000),
+		GasLimit:    t.gas,
+		Value:       new(big.Int).SetUint64(t.value),
+		EVMConfig:   vmConfig,
+		State:       statedb,
+	})
+	out := &outcome{
+		status:  "success",
+		ret:     ret,
+		gasLeft: gasLeft,
+		logs:    statedb.Logs(),
+		root:    statedb.IntermediateRoot(t.config.IsEnabled(t.config.GetEIP161dTransition, number)),
+	}
+	switch {
+	case err == vm.ErrExecutionReverted:
+		out.status = "revert"
+	case err != nil:
+		// Failures consume all gas and return nothing, the errors of the
+		// VMs aren't comparable.
+		out.status, out.ret = "failure", nil
+	}
+	return out
+}
+
+func (t *testcase) String() string {
+	var forks []uint64
+	for _, n := range []*uint64{
+		t.config.GetEIP7Transition(), t.config.GetEIP150Transition(), t.config.GetEIP155Transition(),
+		t.config.GetEIP198Transition(), t.config.GetEIP145Transition(), t.config.GetEIP1283DisableTransition(),
+		t.config.GetEIP1884Transition(),
+	} {
+		if n == nil {
+			break
+		}
+		forks = append(forks, *n)
+	}
+	s := fmt.Sprintf("forks: %v\nnumber: %d\ninput: %x\nvalue: %d\ngas: %d\n", forks, t.number, t.input, t.value, t.gas)
+	for _, addr := range accounts {
+		acc := t.alloc[addr]
+		s += fmt.Sprintf("account %x: balance %d, nonce %d, storage %x\n  code: %x\n", addr, acc.balance, acc.nonce, acc.storage, acc.code)
+	}
+	return s
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 The core-geth Authors
+// This file is part of the core-geth library.
+//
+// The core-geth library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The core-geth library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the core-geth library. If not, see <http://www.gnu.org/licenses/>.
+
+package evmc
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params/types/coregeth"
+	"github.com/ethereum/go-ethereum/params/types/ctypes"
+)
+
+// TestFuzzer runs the differential fuzzer over random inputs, if an EVMC VM is
+// configured.
+func TestFuzzer(t *testing.T) {
+	if os.Getenv(evmcEnv) == "" {
+		t.Skipf("no EVMC VM configured, set %s to run", evmcEnv)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		data := make([]byte, 256+rng.Intn(2048))
+		rng.Read(data)
+		Fuzz(data)
+	}
+}
+
+// Tests that the testcases are generated deterministically from the input.
+func TestReadTestcase(t *testing.T) {
+	data := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	a := (&fuzzer{input: bytes.NewReader(data)}).readTestcase()
+	b := (&fuzzer{input: bytes.NewReader(data)}).readTestcase()
+	if a.String() != b.String() {
+		t.Fatalf("testcase mismatch:\n%v\n%v", a, b)
+	}
+	want := a.execute(vm.Config{})
+	if have := b.execute(vm.Config{}); have.root != want.root || have.gasLeft != want.gasLeft {
+		t.Errorf("outcome mismatch: have %+v, want %+v", have, want)
+	}
+}
+
+// Tests that calls executed by the EVMC VM have the same outcome as with the Go
+// interpreter, if an EVMC VM is configured.
+func TestExecuteEVMC(t *testing.T) {
+	if os.Getenv(evmcEnv) == "" {
+		t.Skipf("no EVMC VM configured, set %s to run", evmcEnv)
+	}
+	initEVMC()
+
+	for i, code := range []string{
+		"0x00",                                   // STOP
+		"0x602a60005260206000f3",                 // MSTORE(0, 42), RETURN(0, 32)
+		"0x602a600055602a60005260206000fd",       // SSTORE(0, 42), MSTORE(0, 42), REVERT(0, 32)
+		"0x34600055600160015560a060006000a100",   // SSTORE(0, CALLVALUE), SSTORE(1, 1), LOG1(0, 0, 0xa0)
+		"0x6000600060006000600060c15af160005500", // SSTORE(0, CALL(GAS, 0xc1, 0, 0, 0, 0, 0))
+	} {
+		code := hexutil.MustDecode(code)
+		if !reflect.DeepEqual(vmCaps.filterCode(common.CopyBytes(code)), code) {
+			t.Logf("case %d: skipped, not supported by the VM", i)
+			continue
+		}
+		config := &coregeth.CoreGethChainConfig{
+			NetworkID: 1,
+			ChainID:   big.NewInt(1),
+			Ethash:    new(ctypes.EthashConfig),
+		}
+		for _, fork := range forks[:vmCaps.forks] {
+			for _, set := range fork.setters {
+				set(config, new(uint64))
+			}
+		}
+		test := &testcase{
+			config: config,
+			number: 1,
+			alloc: map[common.Address]account{
+				accounts[0]: {code: code},
+				accounts[1]: {code: []byte{byte(vm.STOP)}},
+			},
+			value: 1,
+			gas:   100000,
+		}
+		want := test.execute(vm.Config{})
+		if have := test.execute(vm.Config{EVMInterpreter: os.Getenv(evmcEnv)}); !reflect.DeepEqual(have, want) {
+			t.Errorf("case %d: outcome mismatch:\nhave %+v\nwant %+v", i, have, want)
+		}
+	}
+}
+
+// Tests that the generated code is restricted to the opcodes implemented by the
+// VM, leaving push data untouched.
+func TestFilterCode(t *testing.T) {
+	caps, err := parseCapabilities("stop, PUSH2,add", "Byzantium")
+	if err != nil {
+		t.Fatalf("failed to parse capabilities: %v", err)
+	}
+	if caps.forks != 4 {
+		t.Errorf("fork count mismatch: have %d, want %d", caps.forks, 4)
+	}
+	code := caps.filterCode([]byte{0x00, 0x61, 0xff, 0xfe, 0x01, 0x03, 0x04, 0x05})
+	if want := []byte{0x00, 0x61, 0xff, 0xfe, 0x01, 0x00, 0x61, 0x05}; !bytes.Equal(code, want) {
+		t.Errorf("code mismatch: have %x, want %x", code, want)
+	}
+	if _, err := parseCapabilities("PUSH33", ""); err == nil {
+		t.Errorf("unknown opcode accepted")
+	}
+	if _, err := parseCapabilities("", "Berlin"); err == nil {
+		t.Errorf("unknown fork accepted")
+	}
+}